@@ -21,8 +21,16 @@
 
 package gosuki
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Bookmark type
 type Bookmark struct {
+	// Row id in the database, zero for bookmarks not stored yet
+	ID uint64 `json:"id,omitempty"`
+
 	URL      string   `json:"url"`
 	Title    string   `json:"metadata"`
 	Tags     []string `json:"tags"`
@@ -31,5 +39,96 @@ type Bookmark struct {
 	Version  uint64   `json:"version"`
 	Modified uint64   `json:"modified"`
 	Xhsum    string   `json:"xhsum"`
-	//flags int
+	Flags    Flags    `json:"flags"`
+
+	// Markdown notes owned by gosuki, never written by browser syncs
+	Notes string `json:"notes,omitempty"`
+}
+
+// Flags is the bitmask stored in the `flags` column of bookmarks. The two
+// lowest bits hold the [ReadState].
+type Flags uint64
+
+const (
+	// Bits of the reading state
+	StateMask Flags = 0b11
+
+	// The bookmark is pinned as favorite
+	FlagFavorite Flags = 1 << 2
+)
+
+// ReadState is the reading list state of a bookmark
+type ReadState uint8
+
+const (
+	// Not in the reading list
+	StateNone ReadState = iota
+	StateUnread
+	StateRead
+	StateArchived
+)
+
+var readStates = []string{"none", "unread", "read", "archived"}
+
+func (s ReadState) String() string {
+	if int(s) < len(readStates) {
+		return readStates[s]
+	}
+	return fmt.Sprintf("ReadState(%d)", s)
+}
+
+// ParseReadState parses a state name. `archive` is accepted for Pocket
+// compatibility.
+func ParseReadState(name string) (ReadState, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "", "none":
+		return StateNone, nil
+	case "archive":
+		return StateArchived, nil
+	}
+
+	for i, s := range readStates {
+		if s == name {
+			return ReadState(i), nil
+		}
+	}
+	return StateNone, fmt.Errorf("unknown reading state %q", name)
+}
+
+func (f Flags) State() ReadState {
+	return ReadState(f & StateMask)
+}
+
+// WithState returns the flags with the reading state replaced
+func (f Flags) WithState(s ReadState) Flags {
+	return f&^StateMask | Flags(s)&StateMask
+}
+
+func (f Flags) Favorite() bool {
+	return f&FlagFavorite != 0
+}
+
+// WithFavorite returns the flags with the favorite bit set or cleared
+func (f Flags) WithFavorite(fav bool) Flags {
+	if fav {
+		return f | FlagFavorite
+	}
+	return f &^ FlagFavorite
+}
+
+// Mask returns the bits carried by f: the state bits when f has a reading
+// state and the favorite bit when it is set.
+func (f Flags) Mask() Flags {
+	mask := f & FlagFavorite
+	if f.State() != StateNone {
+		mask |= StateMask
+	}
+	return mask
+}
+
+// Merge returns f with the bits carried by src applied on top, see
+// [Flags.Mask]. Merging never clears flags.
+func (f Flags) Merge(src Flags) Flags {
+	return f&^src.Mask() | src
 }
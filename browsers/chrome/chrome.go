@@ -45,10 +45,12 @@ import (
 	"github.com/blob42/gosuki/internal/database"
 	"github.com/blob42/gosuki/pkg/browsers"
 	"github.com/blob42/gosuki/pkg/events"
+	"github.com/blob42/gosuki/pkg/history"
 	"github.com/blob42/gosuki/pkg/logging"
 	"github.com/blob42/gosuki/pkg/modules"
 	"github.com/blob42/gosuki/pkg/parsing"
 	"github.com/blob42/gosuki/pkg/profiles"
+	"github.com/blob42/gosuki/pkg/tabs"
 	"github.com/blob42/gosuki/pkg/tree"
 	"github.com/blob42/gosuki/pkg/watch"
 )
@@ -57,21 +59,15 @@ var (
 	log = logging.GetLogger("chrome")
 )
 
-// Type of the func used recursively on each json node entry by [jsonparser.ArrayEach]
-type ParseChildJSONFunc func([]byte, jsonparser.ValueType, int, error)
-type RecursiveParseJSONFunc func([]byte, []byte, jsonparser.ValueType, int) error
-
 var jsonNodeTypes = map[string]tree.NodeType{
 	"folder": tree.FolderNode,
 	"url":    tree.URLNode,
 }
 
-var jsonNodePaths = struct {
-	Type, Children, URL string
-}{"type", "children", "url"}
-
 // type used to store json nodes in memory for parsing.
 type RawNode struct {
+	id           []byte
+	guid         []byte
 	title        []byte
 	nType        []byte
 	url          []byte
@@ -87,6 +83,8 @@ func (rawNode *RawNode) parseItems(nodeData []byte) {
 		{"name"}, // Title of page
 		{"url"},
 		{"children"},
+		{"id"},
+		{"guid"},
 	}
 
 	jsonparser.EachKey(nodeData, func(idx int, value []byte, vt jsonparser.ValueType, err error) {
@@ -106,6 +104,10 @@ func (rawNode *RawNode) parseItems(nodeData []byte) {
 			rawNode.url = value
 		case 3:
 			rawNode.children, rawNode.childrenType = value, vt
+		case 4:
+			rawNode.id = value
+		case 5:
+			rawNode.guid = value
 		}
 	}, paths...)
 }
@@ -145,6 +147,15 @@ type Chrome struct {
 	activeProfile *profiles.Profile
 
 	activeFlavour *browsers.BrowserDef
+
+	// checksum of the last loaded bookmark file
+	lastChecksum string
+
+	// last loaded url nodes by node key
+	urlNodes map[string]*urlNode
+
+	// closed when the background load started by PreLoad is done
+	preloaded chan struct{}
 }
 
 func (ch *Chrome) Init(ctx *modules.Context, p *profiles.Profile) error {
@@ -177,7 +188,8 @@ func (ch *Chrome) Init(ctx *modules.Context, p *profiles.Profile) error {
 		return ch.init(ctx)
 	}
 
-	ch.ChromeConfig = NewChromeConfig()
+	// use a copy of the config for this profile
+	ch.ChromeConfig = profileConfig()
 	ch.Profile = p.ID
 
 	if bookmarkDir, err := p.AbsolutePath(); err != nil {
@@ -245,6 +257,11 @@ func (ch Chrome) Config() *modules.BrowserConfig {
 	return ch.BrowserConfig
 }
 
+// ProfileConfig implements the modules.ProfileConfigurer interface
+func (Chrome) ProfileConfig() *modules.BrowserConfig {
+	return profileConfig().BrowserConfig
+}
+
 func (ch Chrome) ModInfo() modules.ModInfo {
 	return modules.ModInfo{
 		ID: modules.ModID(ch.Name),
@@ -263,274 +280,204 @@ func (ch *Chrome) Run() {
 	ch.run(true)
 }
 
+// run parses the bookmark file and loads the bookmarks that changed since
+// the last run. Chrome rewrites the whole file on every change, the rewrite is
+// ignored when the file checksum did not change.
 func (ch *Chrome) run(runTask bool) {
 	startRun := time.Now()
 
-	// Rebuild node tree
-	ch.NodeTree = &tree.Node{
-		Title:  RootNodeName,
-		Parent: nil,
-		Type:   tree.RootNode,
-	}
-
-	// Load bookmark file
-	bookmarkPath, err := ch.BookmarkPath()
+	data, err := ch.readBookmarks()
 	if err != nil {
 		log.Error(err)
 		return
 	}
 
-	var parseChildren ParseChildJSONFunc
-	var jsonParseRecursive RecursiveParseJSONFunc
-
-	parseChildren = func(childVal []byte,
-		dataType jsonparser.ValueType,
-		offset int,
-		err error) {
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		err = jsonParseRecursive(nil, childVal, dataType, offset)
-		if err != nil {
-			log.Error(err)
-		}
-
+	checksum, _ := jsonparser.GetString(data, "checksum")
+	if checksum != "" && checksum == ch.lastChecksum {
+		log.Debugf("<%s> bookmarks checksum unchanged, skipping", ch.Name)
+		ch.SetLastWatchRuntime(time.Since(startRun))
+		return
 	}
 
-	// Needed to store the parent of each child node
-	var parentNodes []*tree.Node
-
-	jsonParseRoots := func(key []byte,
-		node []byte,
-		dataType jsonparser.ValueType,
-		offset int,
-	) error {
-
-		// If node type is string ignore (needed for sync_transaction_version)
-		if dataType == jsonparser.String {
-			return nil
-		}
-
-		ch.IncNodeCount()
-		rawNode := new(RawNode)
-		rawNode.parseItems(node)
-
-		//log.Debugf("Parsing root folder %s", rawNode.name)
-
-		currentNode := rawNode.getNode(ch)
-
-		// Process this node as parent node later
-		parentNodes = append(parentNodes, currentNode)
-
-		// add the root node as parent to this node
-		currentNode.Parent = ch.NodeTree
-
-		// Add this root node as a child of the root node
-		ch.NodeTree.Children = append(ch.NodeTree.Children, currentNode)
+	parsed := ch.parseTree(data)
+	ch.SetLastTreeParseRuntime(time.Since(startRun))
+	log.Debugf("<%s> parsed tree in %s", ch.Name, ch.LastFullTreeParseRT())
 
-		// Call recursive parsing of this node which must
-		// a root folder node
-		jsonparser.ArrayEach(node, parseChildren, jsonNodePaths.Children)
+	ch.load(parsed, runTask)
+	ch.SetLastWatchRuntime(time.Since(startRun))
+}
 
-		// Finished parsing this root, it is not anymore a parent
-		_, parentNodes = parentNodes[len(parentNodes)-1],
-			parentNodes[:len(parentNodes)-1]
+func (ch *Chrome) readBookmarks() ([]byte, error) {
+	bookmarkPath, err := ch.BookmarkPath()
+	if err != nil {
+		return nil, err
+	}
 
-		//log.Debugf("Parsed root %s folder", rawNode.name)
+	return os.ReadFile(bookmarkPath)
+}
 
-		return nil
+// parseTree builds a new node tree from the content of a bookmark file
+func (ch *Chrome) parseTree(data []byte) *parsedTree {
+	parsed := &parsedTree{
+		root: &tree.Node{
+			Title:  RootNodeName,
+			Parent: nil,
+			Type:   tree.RootNode,
+		},
 	}
+	parsed.checksum, _ = jsonparser.GetString(data, "checksum")
+
+	// starts from the "roots" key of chrome json bookmark file
+	rootsData, _, _, _ := jsonparser.Get(data, "roots")
 
-	// Main recursive parsing underneath each root folder
-	jsonParseRecursive = func(_ []byte, //key
+	jsonparser.ObjectEach(rootsData, func(_ []byte,
 		node []byte,
 		dataType jsonparser.ValueType,
-		_ int, //offset
+		_ int,
 	) error {
-
 		// If node type is string ignore (needed for sync_transaction_version)
-		if dataType == jsonparser.String {
+		if dataType != jsonparser.Object {
 			return nil
 		}
 
-		ch.IncNodeCount()
-
-		rawNode := new(RawNode)
-		rawNode.parseItems(node)
-
-		currentNode := rawNode.getNode(ch)
-		//log.Debugf("parsing node %s", currentNode.Name)
+		ch.parseNode(parsed, node, parsed.root, nil)
+		return nil
+	})
 
-		// if parents array is not empty
-		if len(parentNodes) != 0 {
-			parent := parentNodes[len(parentNodes)-1]
-			//log.Debugf("Adding current node to parent %s", parent.Name)
+	return parsed
+}
 
-			// Add current node to closest parent
-			currentNode.Parent = parent
+// parseNode adds the json node and its children under parent. folders holds
+// the titles of the parent folders of the node.
+func (ch *Chrome) parseNode(parsed *parsedTree, data []byte, parent *tree.Node, folders []string) {
+	ch.IncNodeCount()
 
-			// Add current node as child to parent
-			currentNode.Parent.Children = append(currentNode.Parent.Children, currentNode)
-		}
+	rawNode := new(RawNode)
+	rawNode.parseItems(data)
 
-		// if node is a folder with children
-		if rawNode.childrenType == jsonparser.Array && len(rawNode.children) > 2 { // if len(children) > len("[]")
+	currentNode := rawNode.getNode(ch)
+	currentNode.Parent = parent
+	parent.Children = append(parent.Children, currentNode)
 
-			//log.Debugf("Started folder %s", rawNode.name)
-			parentNodes = append(parentNodes, currentNode)
+	switch currentNode.Type {
+	case tree.URLNode:
+		currentNode.URL = string(rawNode.url)
+		currentNode.NameHash = xxhash.ChecksumString64(currentNode.Title)
 
-			// Recursively process all child nodes of this folder node
-			jsonparser.ArrayEach(node, parseChildren, jsonNodePaths.Children)
+		//If parent is folder, add it as tag
+		if parent.Type == tree.FolderNode {
+			currentNode.Tags = append(currentNode.Tags, parent.Title)
+		}
 
-			//log.Debugf("Finished folder %s", rawNode.name)
-			_, parentNodes = parentNodes[len(parentNodes)-1], parentNodes[:len(parentNodes)-1]
+		parsed.urls = append(parsed.urls, &urlNode{
+			key:  rawNode.key(),
+			hash: nodeHash(currentNode, folders),
+			node: currentNode,
+		})
 
+	case tree.FolderNode:
+		// if len(children) > len("[]")
+		if rawNode.childrenType != jsonparser.Array || len(rawNode.children) <= 2 {
+			return
 		}
 
-		// if node is url(leaf), handle the url
-		if currentNode.Type == tree.URLNode {
-			currentNode.URL = string(rawNode.url)
-			ch.IncURLCount()
-
-			progress := ch.Progress()
-			if progress-ch.lastSentProgress >= 0.05 || progress == 1 {
-				ch.lastSentProgress = progress
-				go func() {
-					msg := events.ProgressUpdateMsg{
-						ID:           ch.ModInfo().ID,
-						Instance:     ch,
-						CurrentCount: ch.URLCount(),
-						Total:        ch.Total(),
-					}
-					if runTask {
-						msg.NewBk = true
-					}
-					events.TUIBus <- msg
-				}()
+		folders = append(folders, currentNode.Title)
+		jsonparser.ArrayEach(rawNode.children, func(child []byte,
+			dataType jsonparser.ValueType,
+			_ int,
+			err error,
+		) {
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			if dataType != jsonparser.Object {
+				return
 			}
 
-			// Check if url-node already in index
-			var nodeVal *tree.Node
-			iVal, found := ch.URLIndex.Get(currentNode.URL)
-
-			nameHash := xxhash.ChecksumString64(currentNode.Title)
-
-			// If node url not in index, add it to index
-			if !found {
-				//log.Debugf("Not found")
-
-				// store hash(name)
-				currentNode.NameHash = nameHash
-
-				// The value in the index will be a
-				// pointer to currentNode
-				log.Tracef("Inserting url %s to index", currentNode.URL)
-				ch.URLIndex.Insert(currentNode.URL, currentNode)
-
-				// Run registered bookmark parsing hooks
-				err = ch.CallHooks(currentNode)
-				if err != nil {
-					return err
-				}
+			ch.parseNode(parsed, child, currentNode, folders)
+		})
+	}
+}
 
-				// If we find the node already in index
-				// we check if the hash(name) changed  meaning
-				// the data changed
-			} else {
-				// log.Debugf("URL Found in index")
-				nodeVal = iVal.(*tree.Node)
+// load replaces the node tree with the parsed tree and pushes the bookmarks
+// that changed since the previous tree to the buffer.
+func (ch *Chrome) load(parsed *parsedTree, runTask bool) {
+	ch.NodeTree = parsed.root
 
-				// hash(name) is different meaning new commands/tags could
-				// be added, we need to process the parsing hoos
-				if nodeVal.NameHash != nameHash {
-					// log.Debugf("URL name changed !")
+	// Reset the index to represent the nodetree
+	ch.RebuildIndex()
 
-					// Run parse hooks on node
-					ch.CallHooks(currentNode)
-				}
+	changed, nodes := diffNodes(ch.urlNodes, parsed.urls)
+	log.Debugf("<%s> %d of %d bookmarks changed", ch.Name, len(changed), len(parsed.urls))
 
-				// Else we do nothing, the node will not
-				// change
-			}
+	for _, un := range changed {
+		if _, seen := ch.urlNodes[un.key]; !seen {
+			ch.IncURLCount()
+			ch.sendProgress(runTask)
+		}
 
-			//If parent is folder, add it as tag and add current node as child
-			//And add this link as child
-			if currentNode.Parent.Type == tree.FolderNode {
-				// log.Debug("Parent is folder, parsing as tag ...")
-				currentNode.Tags = append(currentNode.Tags, currentNode.Parent.Title)
-			}
+		// Run registered bookmark parsing hooks
+		if err := ch.CallHooks(un.node); err != nil {
+			log.Error(err)
 		}
 
-		return nil
+		bk := un.node.GetBookmark()
+		if err := ch.BufferDB.UpsertBookmark(bk); err != nil {
+			log.Errorf("db upsert: %s", bk.URL)
+		}
 	}
 
-	f, err := os.ReadFile(bookmarkPath)
-	if err != nil {
-		log.Error(err)
+	ch.urlNodes = nodes
+	ch.lastChecksum = parsed.checksum
+
+	if len(changed) == 0 {
 		return
 	}
 
-	// starts from the "roots" key of chrome json bookmark file
-	rootsData, _, _, _ := jsonparser.Get(f, "roots")
-
-	// Start a new node tree building job
-	jsonparser.ObjectEach(rootsData, jsonParseRoots)
-	ch.SetLastTreeParseRuntime(time.Since(startRun))
-	log.Debugf("<%s> parsed tree in %s", ch.Name, ch.LastFullTreeParseRT())
-	// Finished node tree building job
-
-	// Debug walk tree
-	//go PrintTree(ch.NodeTree)
-
-	// Reset the index to represent the nodetree
-	ch.RebuildIndex()
-
-	// Finished parsing
-	log.Debugf("<%s> parsed %d bookmarks and %d nodes", ch.Name, ch.URLCount(), ch.NodeCount())
-
-	//Add nodeTree to Cache
-	//log.Debugf("<%s> buffer content", ch.Name)
-	//ch.BufferDB.Print()
-
-	log.Debugf("<%s> syncing to buffer", ch.Name)
-	database.SyncTreeToBuffer(ch.NodeTree, ch.BufferDB)
-	log.Debugf("<%s> tree synced to buffer", ch.Name)
-
-	//ch.BufferDB.Print()
-
 	// database.Cache represents bookmarks across all browsers
 	// From browsers it should support: add/update
 	// Delete method should only be possible through admin interface
-	//NOTE: We could have an @ignore command to ignore a bookmark
-
-	// URLIndex is a hashmap index of all URLS representing current state
-	// of the browser
-
-	// nodeTree is current state of the browser as tree
-
-	// Buffer is the current state of the browser represetned by
-	// URLIndex and nodeTree
-
-	// debugPrint("%d", BufferDB.Count())
-
-	// If the cache is empty just copy buffer to cache
-	// until local db is already populated and preloaded
-	if err = ch.BufferDB.SyncToCache(); err != nil {
+	if err := ch.BufferDB.SyncToCache(); err != nil {
 		log.Errorf("syncing buffer to cache: %v", err)
 	}
 
 	database.ScheduleBackupToDisk()
-	ch.SetLastWatchRuntime(time.Since(startRun))
+}
+
+func (ch *Chrome) sendProgress(runTask bool) {
+	progress := ch.Progress()
+	if progress-ch.lastSentProgress < 0.05 && progress != 1 {
+		return
+	}
+
+	ch.lastSentProgress = progress
+	go func() {
+		msg := events.ProgressUpdateMsg{
+			ID:           ch.ModInfo().ID,
+			Instance:     ch,
+			CurrentCount: ch.URLCount(),
+			Total:        ch.Total(),
+		}
+		if runTask {
+			msg.NewBk = true
+		}
+		events.TUIBus <- msg
+	}()
 }
 
 // PreLoad() will be called right after a browser is initialized
 func (ch *Chrome) PreLoad(_ *modules.Context) error {
-	bookmarkPath, err := ch.BookmarkPath()
+	startRun := time.Now()
+
+	data, err := ch.readBookmarks()
 	if err != nil {
 		log.Error(err)
 	}
-	ch.SetTotal(preCountCountUrls(bookmarkPath))
+
+	parsed := ch.parseTree(data)
+	ch.SetLastTreeParseRuntime(time.Since(startRun))
+	ch.SetTotal(uint(len(parsed.urls)))
 
 	// Send total to msg bus
 	go func() {
@@ -540,7 +487,11 @@ func (ch *Chrome) PreLoad(_ *modules.Context) error {
 		}
 	}()
 
-	go ch.run(false)
+	ch.preloaded = make(chan struct{})
+	go func() {
+		defer close(ch.preloaded)
+		ch.load(parsed, false)
+	}()
 	return nil
 }
 
@@ -558,6 +509,8 @@ func NewChrome() *Chrome {
 
 func init() {
 	modules.RegisterBrowser(Chrome{ChromeConfig: ChromeCfg})
+	tabs.RegisterReader(BrowserName, NewChrome())
+	history.RegisterReader(BrowserName, NewChrome())
 }
 
 // interface guards
@@ -569,3 +522,6 @@ var _ watch.WatchRunner = (*Chrome)(nil)
 var _ hooks.HookRunner = (*Chrome)(nil)
 var _ parsing.Counter = (*Chrome)(nil)
 var _ profiles.ProfileManager = (*Chrome)(nil)
+var _ tabs.ProfileReader = (*Chrome)(nil)
+var _ history.ProfileReader = (*Chrome)(nil)
+var _ profiles.ProfileIndexer = (*Chrome)(nil)
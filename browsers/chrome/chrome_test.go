@@ -1,10 +1,15 @@
 package chrome
 
 import (
+	"encoding/json"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/blob42/gosuki/internal/database"
 	"github.com/blob42/gosuki/internal/index"
@@ -22,16 +27,20 @@ const statePath = "testdata/Local State"
 var ch Chrome
 
 func setupChrome() {
+	ch = newTestChrome("testdata")
+}
+
+func newTestChrome(bkDir string) Chrome {
 	bufDB, err := database.NewBuffer("chrome_test")
 	if err != nil {
 		panic(err)
 	}
-	ch = Chrome{
+	return Chrome{
 		ChromeConfig: &ChromeConfig{
 			BrowserConfig: &modules.BrowserConfig{
 				Name:     "chrome",
 				BaseDir:  "",
-				BkDir:    "testdata",
+				BkDir:    bkDir,
 				BkFile:   "Bookmarks",
 				BufferDB: bufDB,
 				URLIndex: index.NewIndex(),
@@ -57,6 +66,7 @@ func TestMain(m *testing.M) {
 	}
 
 	database.Cache = &database.CacheDB{DB: cacheDB}
+	database.Clock = &database.LamportClock{}
 
 	setupChrome()
 	exitVal := m.Run()
@@ -112,10 +122,182 @@ func TestPreCount(t *testing.T) {
 	assert.NoError(t, ch.PreLoad(&modules.Context{}), "error preloading bookmarks")
 	total := ch.Total()
 	assert.EqualValues(t, 2007, int(total), "wrong # of url count")
+	<-ch.preloaded
 }
 
-func BenchmarkRun(b *testing.B) {
-	for i := 0; i < b.N; i++ {
-		ch.Run()
+// testBookmarks returns a bookmark file with the url `a` in the bookmark bar
+// and `b` in the folder `folder`.
+func testBookmarks(checksum, titleA, folder string) []byte {
+	url := func(guid, id, name, url string) map[string]any {
+		return map[string]any{"guid": guid, "id": id, "name": name, "type": "url", "url": url}
+	}
+
+	data := map[string]any{
+		"checksum": checksum,
+		"roots": map[string]any{
+			"bookmark_bar": map[string]any{
+				"guid": "0bc5d13f-2cba-5d74-951f-3f233fe6c908",
+				"id":   "1",
+				"name": "Bookmarks bar",
+				"type": "folder",
+				"children": []any{
+					url("a", "3", titleA, "https://a.example.com/"),
+					map[string]any{
+						"guid": "f",
+						"id":   "4",
+						"name": folder,
+						"type": "folder",
+						"children": []any{
+							url("b", "5", "B", "https://b.example.com/"),
+						},
+					},
+				},
+			},
+		},
+		"version": 1,
 	}
+
+	res, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func changedURLs(changed []*urlNode) []string {
+	var urls []string
+	for _, un := range changed {
+		urls = append(urls, un.node.URL)
+	}
+	return urls
+}
+
+func TestDiffNodes(t *testing.T) {
+	logging.SetLevel(logging.Silent)
+	tch := newTestChrome(t.TempDir())
+
+	parsed := tch.parseTree(testBookmarks("1", "A", "F"))
+	require.Len(t, parsed.urls, 2)
+	assert.Equal(t, "guid:a", parsed.urls[0].key)
+
+	changed, nodes := diffNodes(nil, parsed.urls)
+	assert.Len(t, changed, 2, "all nodes are new")
+
+	// tags added by hooks on the previous run
+	parsed.urls[0].node.Tags = append(parsed.urls[0].node.Tags, "hooked")
+
+	reparsed := tch.parseTree(testBookmarks("2", "A", "F"))
+	changed, _ = diffNodes(nodes, reparsed.urls)
+	assert.Empty(t, changed, "identical tree")
+	assert.Contains(t, reparsed.urls[0].node.Tags, "hooked",
+		"unchanged nodes keep the tags of hooks")
+
+	changed, _ = diffNodes(nodes, tch.parseTree(testBookmarks("3", "A renamed", "F")).urls)
+	assert.Equal(t, []string{"https://a.example.com/"}, changedURLs(changed))
+
+	changed, _ = diffNodes(nodes, tch.parseTree(testBookmarks("4", "A", "F renamed")).urls)
+	assert.Equal(t, []string{"https://b.example.com/"}, changedURLs(changed),
+		"children of a renamed folder get a new tag")
+}
+
+func TestRunChecksum(t *testing.T) {
+	logging.SetLevel(logging.Silent)
+	dir := t.TempDir()
+	bkPath := filepath.Join(dir, "Bookmarks")
+	tch := newTestChrome(dir)
+
+	require.NoError(t, os.WriteFile(bkPath, testBookmarks("1", "A", "F"), 0o600))
+	tch.run(false)
+	assert.Equal(t, "1", tch.lastChecksum)
+	var count int
+	require.NoError(t, tch.BufferDB.Handle.Get(&count, "SELECT count(*) FROM gskbookmarks"))
+	assert.Equal(t, 2, count)
+
+	// rewrite with the same checksum is ignored
+	require.NoError(t, os.WriteFile(bkPath, testBookmarks("1", "A renamed", "F"), 0o600))
+	tch.run(true)
+	node, ok := tch.URLIndex.Get("https://a.example.com/")
+	require.True(t, ok)
+	assert.Equal(t, "A", node.(*tree.Node).Title)
+}
+
+// generatedBookmarks writes the output of internal/scripts/gen-chrome-bookmarks.go
+// to a temporary directory
+func generatedBookmarks(b *testing.B, amount int) string {
+	dir := b.TempDir()
+	out, err := exec.Command("go", "run",
+		"../../internal/scripts/gen-chrome-bookmarks.go",
+		"-amt", strconv.Itoa(amount),
+	).Output()
+	if err != nil {
+		b.Skipf("generating bookmarks: %s", err)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, "Bookmarks"), out, 0o600); err != nil {
+		b.Fatal(err)
+	}
+	return dir
+}
+
+// emptyCache empties the cache db, the buffer is copied to an empty cache
+// instead of being synchronized.
+func emptyCache(b *testing.B) {
+	if _, err := database.Cache.Handle.Exec("DROP TABLE IF EXISTS gskbookmarks"); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	logging.SetLevel(logging.Silent)
+	dir := generatedBookmarks(b, 2000)
+
+	// full parse and load of all bookmarks
+	b.Run("full", func(b *testing.B) {
+		for b.Loop() {
+			b.StopTimer()
+			emptyCache(b)
+			bch := newTestChrome(dir)
+			b.StartTimer()
+			bch.run(false)
+		}
+	})
+
+	// file rewritten without changes to the bookmarks
+	b.Run("unchanged", func(b *testing.B) {
+		emptyCache(b)
+		bch := newTestChrome(dir)
+		bch.run(false)
+		for b.Loop() {
+			bch.lastChecksum = ""
+			bch.run(true)
+		}
+	})
+
+	// file rewritten with the same checksum
+	b.Run("checksum", func(b *testing.B) {
+		emptyCache(b)
+		bch := newTestChrome(dir)
+		bch.run(false)
+		for b.Loop() {
+			bch.run(true)
+		}
+	})
+}
+
+func TestProfileConfig(t *testing.T) {
+	savedHooks, savedUse := ChromeCfg.Hooks, ChromeCfg.UseHooks
+	defer func() { ChromeCfg.Hooks, ChromeCfg.UseHooks = savedHooks, savedUse }()
+	ChromeCfg.Hooks = []string{"node_tags_from_name"}
+	ChromeCfg.UseHooks = ChromeCfg.Hooks
+
+	cfg := profileConfig()
+	assert.Equal(t, []string{"node_tags_from_name"}, cfg.UseHooks)
+	assert.Equal(t, cfg.UseHooks, Chrome{}.ProfileConfig().UseHooks)
+	assert.Equal(t, ChromeCfg.ProfilePrefs, cfg.ProfilePrefs)
+
+	cfg.UseHooks[0] = "changed"
+	assert.Equal(t, "node_tags_from_name", ChromeCfg.UseHooks[0])
+
+	NewChromeConfig().UseHooks[0] = "changed"
+	assert.Equal(t, "node_tags_from_name", DefaultHooks[0])
 }
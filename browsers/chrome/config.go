@@ -22,6 +22,11 @@
 package chrome
 
 import (
+	"context"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+
 	"github.com/blob42/gosuki/pkg/config"
 	"github.com/blob42/gosuki/pkg/modules"
 	"github.com/blob42/gosuki/pkg/profiles"
@@ -38,8 +43,14 @@ type ChromeConfig struct {
 	*modules.BrowserConfig `toml:"-"`
 	modules.ProfilePrefs   `toml:"profile-options" mapstructure:"profile-options"`
 	CustomProfiles         []profiles.CustomProfile `toml:"custom-profiles" mapstructure:"custom-profiles"`
+
+	// Hooks run on every bookmark loaded from the browser, see `gosuki hooks list`
+	Hooks []string `toml:"hooks" mapstructure:"hooks"`
 }
 
+// DefaultHooks are the hooks used when the config does not set them
+var DefaultHooks = []string{"node_tags_from_name", "node_autotag"}
+
 var (
 	ProfileManager = &ChromeProfileManager{}
 
@@ -58,8 +69,9 @@ func NewChromeConfig() *ChromeConfig {
 				Type:   tree.RootNode,
 			},
 			UseFileWatcher: true,
-			UseHooks:       []string{"node_tags_from_name"},
+			UseHooks:       slices.Clone(DefaultHooks),
 		},
+		Hooks: slices.Clone(DefaultHooks),
 		ProfilePrefs: modules.ProfilePrefs{
 			Profile:          DefaultProfile,
 			WatchAllProfiles: true,
@@ -69,6 +81,19 @@ func NewChromeConfig() *ChromeConfig {
 	return config
 }
 
+// profileConfig returns a copy of the user config for a profile instance
+func profileConfig() *ChromeConfig {
+	cfg := NewChromeConfig()
+	cfg.ProfilePrefs = ChromeCfg.ProfilePrefs
+	cfg.CustomProfiles = ChromeCfg.CustomProfiles
+	cfg.Hooks = slices.Clone(ChromeCfg.Hooks)
+	cfg.UseHooks = slices.Clone(ChromeCfg.UseHooks)
+	return cfg
+}
+
 func init() {
 	config.RegisterConfigurator(BrowserName, config.AsConfigurator(ChromeCfg))
+	config.RegisterConfReadyHooks(func(context.Context, *cli.Command) error {
+		return modules.SetHooks[*tree.Node](ChromeCfg.BrowserConfig, ChromeCfg.Hooks)
+	})
 }
@@ -0,0 +1,92 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package chrome
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/blob42/gosuki/pkg/history"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+const (
+	// History database of a profile
+	HistoryFile = "History"
+
+	// seconds between 1601-01-01 and the unix epoch. Chromium timestamps are
+	// microseconds since 1601-01-01.
+	webkitEpochOffset = 11644473600
+)
+
+// Visited pages
+const QHistorySince = `
+	SELECT url, title, visit_count, last_visit_time
+	FROM urls
+	WHERE hidden = 0 AND last_visit_time > ?
+`
+
+type chromeVisit struct {
+	URL           string
+	Title         string
+	VisitCount    int   `db:"visit_count"`
+	LastVisitTime int64 `db:"last_visit_time"`
+}
+
+func toWebkitTime(t time.Time) int64 {
+	return t.UnixMicro() + webkitEpochOffset*1_000_000
+}
+
+func fromWebkitTime(ts int64) time.Time {
+	return time.UnixMicro(ts - webkitEpochOffset*1_000_000)
+}
+
+// History implements the history.Reader interface
+func (*Chrome) History(p *profiles.Profile, since time.Time) ([]*history.Visit, error) {
+	profileDir, err := p.AbsolutePath()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := history.OpenSnapshot(filepath.Join(profileDir, HistoryFile))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	visits := []chromeVisit{}
+	if err = db.Select(&visits, QHistorySince, toWebkitTime(since)); err != nil {
+		return nil, err
+	}
+
+	result := make([]*history.Visit, 0, len(visits))
+	for _, v := range visits {
+		result = append(result, &history.Visit{
+			URL:        v.URL,
+			Title:      v.Title,
+			VisitCount: v.VisitCount,
+			LastVisit:  fromWebkitTime(v.LastVisitTime),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package chrome
+
+import (
+	"slices"
+
+	"github.com/OneOfOne/xxhash"
+
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+// parsedTree is the node tree parsed from a bookmark file
+type parsedTree struct {
+	checksum string
+	root     *tree.Node
+
+	// url nodes in file order
+	urls []*urlNode
+}
+
+type urlNode struct {
+	key  string
+	hash uint64
+	node *tree.Node
+}
+
+// key returns the identifier of the node across rewrites of the bookmark
+// file. The guid is preferred as it is kept by chrome sync, the id is only
+// stable locally.
+func (rawNode *RawNode) key() string {
+	switch {
+	case len(rawNode.guid) > 0:
+		return "guid:" + string(rawNode.guid)
+	case len(rawNode.id) > 0:
+		return "id:" + string(rawNode.id)
+	default:
+		return "url:" + string(rawNode.url)
+	}
+}
+
+// nodeHash hashes the data of a url node stored as bookmark. Parent folders
+// are turned into tags, moving or renaming them changes the hash.
+func nodeHash(node *tree.Node, folders []string) uint64 {
+	h := xxhash.New64()
+	h.WriteString(node.URL)
+	h.Write([]byte{0})
+	h.WriteString(node.Title)
+	for _, folder := range folders {
+		h.Write([]byte{0})
+		h.WriteString(folder)
+	}
+
+	return h.Sum64()
+}
+
+// diffNodes returns the url nodes that are new or changed compared to the
+// previous run, along with the current nodes by key. Hooks only run on changed
+// nodes, unchanged nodes keep the data set by hooks on the previous nodes.
+func diffNodes(previous map[string]*urlNode, current []*urlNode) ([]*urlNode, map[string]*urlNode) {
+	var changed []*urlNode
+	nodes := make(map[string]*urlNode, len(current))
+
+	for _, un := range current {
+		nodes[un.key] = un
+
+		prev, ok := previous[un.key]
+		if !ok || prev.hash != un.hash {
+			changed = append(changed, un)
+			continue
+		}
+
+		un.node.Title = prev.node.Title
+		un.node.Tags = slices.Clone(prev.node.Tags)
+		un.node.Desc = prev.node.Desc
+	}
+
+	return changed, nodes
+}
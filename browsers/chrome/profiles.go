@@ -24,10 +24,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/pkg/browsers"
 	"github.com/blob42/gosuki/pkg/profiles"
+	"github.com/blob42/gosuki/pkg/tabs"
 )
 
 // Chrome state file.
@@ -86,6 +88,22 @@ func (*Chrome) GetProfiles(flavour string) ([]*profiles.Profile, error) {
 	return append(profiles, customProfiles...), nil
 }
 
+// ProfileIndexFiles implements the profiles.ProfileIndexer interface. Chrome
+// lists its profiles in the `Local State` file.
+func (*Chrome) ProfileIndexFiles(flavour string) ([]string, error) {
+	flv, ok := browsers.Defined(browsers.ChromeBased)[flavour]
+	if !ok {
+		return nil, fmt.Errorf("unknown flavour <%s>", flavour)
+	}
+
+	baseDir, err := flv.ExpandBaseDir()
+	if err != nil {
+		return nil, fmt.Errorf("expanding base directory: %w", err)
+	}
+
+	return []string{filepath.Join(baseDir, StateFile)}, nil
+}
+
 // Returns all flavours supported by this browser
 func (*Chrome) ListFlavours() []browsers.BrowserDef {
 	var result []browsers.BrowserDef
@@ -177,3 +195,8 @@ func loadLocalState(path string) (*StateData, error) {
 	err = json.Unmarshal(data, &state)
 	return &state, err
 }
+
+// OpenTabs implements the tabs.ProfileReader interface
+func (*Chrome) OpenTabs(p *profiles.Profile) ([]*tabs.Tab, error) {
+	return tabs.ReadProfile(p, ReadSessionTabs)
+}
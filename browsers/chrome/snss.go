@@ -0,0 +1,398 @@
+// Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package chrome
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/blob42/gosuki/pkg/tabs"
+)
+
+const (
+	// Directory holding session files since Chromium 86
+	SessionsDir = "Sessions"
+
+	// Session file used by older Chromium versions
+	CurrentSessionFile = "Current Session"
+
+	snssMagic = "SNSS"
+)
+
+// SNSS session command ids, see
+// components/sessions/core/session_service_commands.cc
+const (
+	cmdSetTabWindow                     = 0
+	cmdSetTabIndexInWindow              = 2
+	cmdTabNavigationPathPrunedFromBack  = 5
+	cmdUpdateTabNavigation              = 6
+	cmdSetSelectedNavigationIndex       = 7
+	cmdTabNavigationPathPrunedFromFront = 11
+	cmdSetPinnedState                   = 12
+	cmdTabClosed                        = 16
+	cmdWindowClosed                     = 17
+	cmdTabNavigationPathPruned          = 24
+)
+
+var (
+	ErrNoSession  = errors.New("no session file found")
+	ErrNotSNSS    = errors.New("not a SNSS session file")
+	ErrCorruptCmd = errors.New("corrupt session command")
+)
+
+// SessionTab is an open tab restored from a Chromium session file
+type SessionTab struct {
+	URL    string
+	Title  string
+	Window int
+	Pinned bool
+}
+
+// Tab implements the tabs.SessionTab interface
+func (t *SessionTab) Tab() *tabs.Tab {
+	return &tabs.Tab{
+		URL:    t.URL,
+		Title:  t.Title,
+		Window: t.Window,
+		Pinned: t.Pinned,
+	}
+}
+
+type snssNavigation struct {
+	url   string
+	title string
+}
+
+type snssTab struct {
+	id       int32
+	window   int32
+	index    int32
+	selected int32
+	pinned   bool
+	navs     map[int32]snssNavigation
+}
+
+// LatestSessionFile returns the most recent session file of the profile in
+// `profileDir`
+func LatestSessionFile(profileDir string) (string, error) {
+	sessions, err := filepath.Glob(filepath.Join(profileDir, SessionsDir, "Session_*"))
+	if err != nil {
+		return "", err
+	}
+
+	// Session_<timestamp>, timestamps have the same number of digits
+	if len(sessions) > 0 {
+		slices.Sort(sessions)
+		return sessions[len(sessions)-1], nil
+	}
+
+	current := filepath.Join(profileDir, CurrentSessionFile)
+	if _, err = os.Stat(current); err == nil {
+		return current, nil
+	}
+
+	return "", fmt.Errorf("%w in %s", ErrNoSession, profileDir)
+}
+
+// ReadSessionTabs returns the open tabs of the profile in `profileDir`
+func ReadSessionTabs(profileDir string) ([]*SessionTab, error) {
+	path, err := LatestSessionFile(profileDir)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tabs, err := ParseSNSS(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return tabs, nil
+}
+
+// ParseSNSS replays the commands of a SNSS session file and returns the tabs
+// that are still open at the end of the session, ordered by window and
+// position in the window.
+func ParseSNSS(data []byte) ([]*SessionTab, error) {
+	if len(data) < 8 || string(data[:4]) != snssMagic {
+		return nil, ErrNotSNSS
+	}
+
+	tabs := map[int32]*snssTab{}
+	getTab := func(id int32) *snssTab {
+		t, ok := tabs[id]
+		if !ok {
+			t = &snssTab{id: id, selected: -1, navs: map[int32]snssNavigation{}}
+			tabs[id] = t
+		}
+		return t
+	}
+
+	// skip magic and version
+	r := bytes.NewReader(data[8:])
+	for r.Len() >= 2 {
+		var size uint16
+		if err := binary.Read(r, binary.LittleEndian, &size); err != nil || size == 0 {
+			return nil, ErrCorruptCmd
+		}
+
+		// the last command can be truncated if the browser is writing to the
+		// file
+		cmd := make([]byte, size)
+		if _, err := io.ReadFull(r, cmd); err != nil {
+			break
+		}
+
+		id, payload := cmd[0], cmd[1:]
+		ints := func(n int) []int32 {
+			if len(payload) < n*4 {
+				return nil
+			}
+			res := make([]int32, n)
+			for i := range n {
+				res[i] = int32(binary.LittleEndian.Uint32(payload[i*4:]))
+			}
+			return res
+		}
+
+		switch id {
+		case cmdSetTabWindow:
+			if v := ints(2); v != nil {
+				getTab(v[1]).window = v[0]
+			}
+
+		case cmdSetTabIndexInWindow:
+			if v := ints(2); v != nil {
+				getTab(v[0]).index = v[1]
+			}
+
+		case cmdSetSelectedNavigationIndex:
+			if v := ints(2); v != nil {
+				getTab(v[0]).selected = v[1]
+			}
+
+		case cmdSetPinnedState:
+			if v := ints(2); v != nil {
+				getTab(v[0]).pinned = v[1]&0xff != 0
+			}
+
+		case cmdUpdateTabNavigation:
+			tabID, index, nav, err := parseNavigation(payload)
+			if err != nil {
+				return nil, err
+			}
+			getTab(tabID).navs[index] = nav
+
+		case cmdTabNavigationPathPrunedFromBack:
+			if v := ints(2); v != nil {
+				t := getTab(v[0])
+				for i := range t.navs {
+					if i >= v[1] {
+						delete(t.navs, i)
+					}
+				}
+			}
+
+		case cmdTabNavigationPathPrunedFromFront:
+			if v := ints(2); v != nil {
+				getTab(v[0]).prune(0, v[1])
+			}
+
+		case cmdTabNavigationPathPruned:
+			if v := ints(3); v != nil {
+				getTab(v[0]).prune(v[1], v[2])
+			}
+
+		case cmdTabClosed:
+			if v := ints(1); v != nil {
+				delete(tabs, v[0])
+			}
+
+		case cmdWindowClosed:
+			if v := ints(1); v != nil {
+				for id, t := range tabs {
+					if t.window == v[0] {
+						delete(tabs, id)
+					}
+				}
+			}
+		}
+	}
+
+	ordered := make([]*snssTab, 0, len(tabs))
+	for _, t := range tabs {
+		ordered = append(ordered, t)
+	}
+	slices.SortFunc(ordered, func(a, b *snssTab) int {
+		if a.window != b.window {
+			return int(a.window - b.window)
+		}
+		if a.index != b.index {
+			return int(a.index - b.index)
+		}
+		return int(a.id - b.id)
+	})
+
+	var result []*SessionTab
+	for _, t := range ordered {
+		nav, ok := t.current()
+		if !ok {
+			continue
+		}
+		result = append(result, &SessionTab{
+			URL:    nav.url,
+			Title:  nav.title,
+			Window: int(t.window),
+			Pinned: t.pinned,
+		})
+	}
+
+	return result, nil
+}
+
+// prune removes `count` navigations starting at `index` and shifts the
+// following navigations
+func (t *snssTab) prune(index, count int32) {
+	navs := map[int32]snssNavigation{}
+	for i, nav := range t.navs {
+		switch {
+		case i < index:
+			navs[i] = nav
+		case i >= index+count:
+			navs[i-count] = nav
+		}
+	}
+	t.navs = navs
+}
+
+// current returns the selected navigation of the tab, or the last one when
+// no navigation was selected
+func (t *snssTab) current() (snssNavigation, bool) {
+	if nav, ok := t.navs[t.selected]; ok {
+		return nav, true
+	}
+
+	if len(t.navs) == 0 {
+		return snssNavigation{}, false
+	}
+
+	last := slices.Max(slices.Collect(func(yield func(int32) bool) {
+		for i := range t.navs {
+			if !yield(i) {
+				return
+			}
+		}
+	}))
+	return t.navs[last], true
+}
+
+// parseNavigation reads a pickled UpdateTabNavigation payload:
+// uint32 pickle size, int32 tab id, int32 navigation index, string url,
+// string16 title, ... Fields are aligned on 4 bytes.
+func parseNavigation(payload []byte) (int32, int32, snssNavigation, error) {
+	nav := snssNavigation{}
+	p := pickleReader{data: payload}
+
+	if _, err := p.uint32(); err != nil {
+		return 0, 0, nav, err
+	}
+	tabID, err := p.uint32()
+	if err != nil {
+		return 0, 0, nav, err
+	}
+	index, err := p.uint32()
+	if err != nil {
+		return 0, 0, nav, err
+	}
+	if nav.url, err = p.string(); err != nil {
+		return 0, 0, nav, err
+	}
+	if nav.title, err = p.string16(); err != nil {
+		return 0, 0, nav, err
+	}
+
+	return int32(tabID), int32(index), nav, nil
+}
+
+// pickleReader reads values serialized with base::Pickle
+type pickleReader struct {
+	data []byte
+	pos  int
+}
+
+func (p *pickleReader) next(n int) ([]byte, error) {
+	if n < 0 || p.pos+n > len(p.data) {
+		return nil, ErrCorruptCmd
+	}
+	b := p.data[p.pos : p.pos+n]
+	// values are padded to 4 bytes
+	p.pos += (n + 3) &^ 3
+	return b, nil
+}
+
+func (p *pickleReader) uint32() (uint32, error) {
+	b, err := p.next(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (p *pickleReader) string() (string, error) {
+	n, err := p.uint32()
+	if err != nil {
+		return "", err
+	}
+	b, err := p.next(int(n))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (p *pickleReader) string16() (string, error) {
+	n, err := p.uint32()
+	if err != nil {
+		return "", err
+	}
+	b, err := p.next(int(n) * 2)
+	if err != nil {
+		return "", err
+	}
+
+	u16 := make([]uint16, n)
+	for i := range u16 {
+		u16[i] = binary.LittleEndian.Uint16(b[i*2:])
+	}
+	return strings.ToValidUTF8(string(utf16.Decode(u16)), ""), nil
+}
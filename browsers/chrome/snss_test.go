@@ -0,0 +1,145 @@
+package chrome
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// snssWriter builds SNSS session files for tests
+type snssWriter struct {
+	bytes.Buffer
+}
+
+func newSNSSWriter() *snssWriter {
+	w := &snssWriter{}
+	w.WriteString(snssMagic)
+	binary.Write(w, binary.LittleEndian, int32(3))
+	return w
+}
+
+func (w *snssWriter) command(id byte, payload []byte) {
+	binary.Write(w, binary.LittleEndian, uint16(len(payload)+1))
+	w.WriteByte(id)
+	w.Write(payload)
+}
+
+func (w *snssWriter) ints(id byte, values ...int32) {
+	payload := &bytes.Buffer{}
+	for _, v := range values {
+		binary.Write(payload, binary.LittleEndian, v)
+	}
+	w.command(id, payload.Bytes())
+}
+
+func (w *snssWriter) navigation(tab, index int32, url, title string) {
+	pad := func(b *bytes.Buffer) {
+		for b.Len()%4 != 0 {
+			b.WriteByte(0)
+		}
+	}
+
+	body := &bytes.Buffer{}
+	binary.Write(body, binary.LittleEndian, tab)
+	binary.Write(body, binary.LittleEndian, index)
+	binary.Write(body, binary.LittleEndian, int32(len(url)))
+	body.WriteString(url)
+	pad(body)
+
+	title16 := utf16.Encode([]rune(title))
+	binary.Write(body, binary.LittleEndian, int32(len(title16)))
+	binary.Write(body, binary.LittleEndian, title16)
+	pad(body)
+
+	payload := &bytes.Buffer{}
+	binary.Write(payload, binary.LittleEndian, uint32(body.Len()))
+	payload.Write(body.Bytes())
+	w.command(cmdUpdateTabNavigation, payload.Bytes())
+}
+
+func TestParseSNSS(t *testing.T) {
+	w := newSNSSWriter()
+
+	// tab 1: two navigations, second one selected
+	w.ints(cmdSetTabWindow, 1, 1)
+	w.ints(cmdSetTabIndexInWindow, 1, 1)
+	w.navigation(1, 0, "https://example.com/", "Example")
+	w.navigation(1, 1, "https://go.dev/doc/", "Documentation - The Go Programming Language")
+	w.ints(cmdSetSelectedNavigationIndex, 1, 1)
+
+	// tab 2: pinned, first in the window
+	w.ints(cmdSetTabWindow, 1, 2)
+	w.ints(cmdSetTabIndexInWindow, 2, 0)
+	w.ints(cmdSetPinnedState, 2, 1)
+	w.navigation(2, 0, "https://github.com/blob42/gosuki", "gosuki — ünïcode")
+
+	// tab 3: closed
+	w.ints(cmdSetTabWindow, 1, 3)
+	w.navigation(3, 0, "https://closed.example.com/", "Closed")
+	w.ints(cmdTabClosed, 3, 0, 0, 0)
+
+	// tab 4: in a closed window
+	w.ints(cmdSetTabWindow, 2, 4)
+	w.navigation(4, 0, "https://window.example.com/", "Window")
+	w.ints(cmdWindowClosed, 2, 0, 0)
+
+	// tab 5: back history pruned, selected index out of range
+	w.ints(cmdSetTabWindow, 3, 5)
+	w.navigation(5, 0, "https://first.example.com/", "First")
+	w.navigation(5, 1, "https://pruned.example.com/", "Pruned")
+	w.ints(cmdTabNavigationPathPrunedFromBack, 5, 1)
+	w.ints(cmdSetSelectedNavigationIndex, 5, 4)
+
+	data := w.Bytes()
+
+	t.Run("tabs", func(t *testing.T) {
+		tabs, err := ParseSNSS(data)
+		require.NoError(t, err)
+		require.Len(t, tabs, 3)
+
+		assert.Equal(t, "https://github.com/blob42/gosuki", tabs[0].URL)
+		assert.Equal(t, "gosuki — ünïcode", tabs[0].Title)
+		assert.True(t, tabs[0].Pinned)
+
+		assert.Equal(t, "https://go.dev/doc/", tabs[1].URL)
+		assert.Equal(t, 1, tabs[1].Window)
+		assert.False(t, tabs[1].Pinned)
+
+		assert.Equal(t, "https://first.example.com/", tabs[2].URL)
+		assert.Equal(t, 3, tabs[2].Window)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		tabs, err := ParseSNSS(data[:len(data)-3])
+		require.NoError(t, err)
+		assert.NotEmpty(t, tabs)
+	})
+
+	t.Run("not snss", func(t *testing.T) {
+		_, err := ParseSNSS([]byte("{\"roots\": {}}"))
+		assert.ErrorIs(t, err, ErrNotSNSS)
+	})
+
+	t.Run("latest session file", func(t *testing.T) {
+		profileDir := t.TempDir()
+		_, err := ReadSessionTabs(profileDir)
+		assert.ErrorIs(t, err, ErrNoSession)
+
+		sessions := filepath.Join(profileDir, SessionsDir)
+		require.NoError(t, os.Mkdir(sessions, 0o700))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(sessions, "Session_13350000000000000"), []byte("SNSS\x03\x00\x00\x00"), 0o600))
+		require.NoError(t, os.WriteFile(
+			filepath.Join(sessions, "Session_13360000000000000"), data, 0o600))
+
+		tabs, err := ReadSessionTabs(profileDir)
+		require.NoError(t, err)
+		assert.Len(t, tabs, 3)
+	})
+}
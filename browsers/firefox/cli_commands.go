@@ -23,30 +23,49 @@ package firefox
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v3"
 
 	"github.com/blob42/gosuki/cmd"
 	"github.com/blob42/gosuki/pkg/browsers/mozilla"
 	"github.com/blob42/gosuki/pkg/logging"
-
-	"github.com/urfave/cli/v3"
+	"github.com/blob42/gosuki/pkg/profiles"
 )
 
 var fflog = logging.GetLogger("ff")
 
 var (
+	ffVFSFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    "profile",
+			Aliases: []string{"p"},
+			Usage:   "firefox `PROFILE` name, all profiles if not set",
+		},
+		&cli.StringFlag{
+			Name:    "flavour",
+			Aliases: []string{"f"},
+			Usage:   "browser `FLAVOUR` (firefox, librewolf ...)",
+			Value:   BrowserName,
+		},
+	}
+
 	ffUnlockVFSCmd = cli.Command{
 		Name:    "unlock",
 		Usage:   "Remove VFS lock from places.sqlite",
 		Aliases: []string{"u"},
+		Flags:   ffVFSFlags,
 		Action:  ffUnlockVFS,
 	}
 
 	ffCheckVFSCmd = cli.Command{
 		Name:    "check",
+		Usage:   "Check if places.sqlite is locked by firefox",
 		Aliases: []string{"c"},
+		Flags:   ffVFSFlags,
 		Action:  ffCheckVFS,
 	}
-
 	ffVFSCommands = cli.Command{
 		Name:  "vfs",
 		Usage: "VFS locking commands",
@@ -70,21 +89,83 @@ func init() {
 	cmd.RegisterModCommand(BrowserName, FirefoxCmds)
 }
 
+// vfsProfiles returns the profiles selected with the --profile and --flavour
+// flags
+func vfsProfiles(cmd *cli.Command) ([]*profiles.Profile, error) {
+	flavour := cmd.String("flavour")
+	ff := NewFirefox()
+
+	profs, err := ff.GetProfiles(flavour)
+	if err != nil {
+		return nil, err
+	}
+
+	name := cmd.String("profile")
+	if name == "" {
+		return profs, nil
+	}
+
+	for _, p := range profs {
+		if p.Name == name {
+			return []*profiles.Profile{p}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("profile <%s> not found for flavour <%s>", name, flavour)
+}
+
+func ffCheckVFS(_ context.Context, cmd *cli.Command) error {
+	red := color.New(color.FgRed).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
 
-func ffCheckVFS(_ context.Context, _ *cli.Command) error {
-	err := mozilla.CheckVFSLock("path to profile")
+	profs, err := vfsProfiles(cmd)
 	if err != nil {
 		return err
 	}
 
+	for _, p := range profs {
+		pPath, err := p.AbsolutePath()
+		if err != nil {
+			fmt.Printf(" %s %s: %s\n", red(""), p.Name, err)
+			continue
+		}
+
+		status, err := mozilla.CheckVFSLock(pPath)
+		if err != nil {
+			fmt.Printf(" %s %s: %s\n", red(""), cyan(p.Name), err)
+			continue
+		}
+
+		if status.Locked {
+			fmt.Printf(" %s %s: locked\n", red(""), cyan(p.Name))
+		} else {
+			fmt.Printf(" %s %s: unlocked\n", green(""), cyan(p.Name))
+		}
+		fmt.Printf("      Path: %s\n", status.Path)
+		fmt.Printf("      %s: %t\n", mozilla.PrefMultiProcessAccess, status.MultiProcessAccess)
+	}
+
 	return nil
 }
 
-func ffUnlockVFS(_ context.Context, _ *cli.Command) error {
-	err := mozilla.UnlockPlaces("path to profile")
+func ffUnlockVFS(_ context.Context, cmd *cli.Command) error {
+	profs, err := vfsProfiles(cmd)
 	if err != nil {
 		return err
 	}
 
+	for _, p := range profs {
+		pPath, err := p.AbsolutePath()
+		if err != nil {
+			return err
+		}
+
+		fflog.Infof("unlocking VFS for profile <%s>", p.Name)
+		if err = mozilla.UnlockPlaces(pPath); err != nil {
+			return fmt.Errorf("profile %s: %w", p.Name, err)
+		}
+	}
+
 	return nil
 }
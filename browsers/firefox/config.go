@@ -23,6 +23,11 @@
 package firefox
 
 import (
+	"context"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+
 	"github.com/blob42/gosuki/internal/database"
 	"github.com/blob42/gosuki/pkg/browsers/mozilla"
 	"github.com/blob42/gosuki/pkg/config"
@@ -38,6 +43,9 @@ const (
 	BrowserName = "firefox"
 )
 
+// DefaultHooks are the hooks used when the config does not set them
+var DefaultHooks = []string{"node_tags_from_name", "node_autotag"}
+
 var (
 
 	// firefox global config state.
@@ -71,6 +79,9 @@ type FirefoxConfig struct {
 
 	CustomProfiles []profiles.CustomProfile `toml:"custom-profiles" mapstructure:"custom-profiles"`
 
+	// Hooks run on every bookmark loaded from the browser, see `gosuki hooks list`
+	Hooks []string `toml:"hooks" mapstructure:"hooks"`
+
 	//TEST: ignore this field in config.Configurator interface
 	// Embed base browser config
 	*modules.BrowserConfig `toml:"-"`
@@ -90,10 +101,12 @@ func NewFirefoxConfig() *FirefoxConfig {
 			UseFileWatcher: true,
 			// NOTE: see parsing.Hook to add custom parsing logic for each
 			// parsed bookmark node
-			// UseHooks: []string{"node_notify_send"},
-			UseHooks: []string{"node_tags_from_name"},
+			UseHooks: slices.Clone(DefaultHooks),
 		},
 
+		// ex. add "node_notify_send" to get notified of new bookmarks
+		Hooks: slices.Clone(DefaultHooks),
+
 		// Default data source name query options for `places.sqlite` db
 		PlacesDSN: database.DsnOptions{
 			"_journal_mode": "WAL",
@@ -110,9 +123,22 @@ func NewFirefoxConfig() *FirefoxConfig {
 	return cfg
 }
 
+// profileConfig returns a copy of the user config for a profile instance
+func profileConfig() *FirefoxConfig {
+	cfg := NewFirefoxConfig()
+	cfg.ProfilePrefs = FFConfig.ProfilePrefs
+	cfg.CustomProfiles = FFConfig.CustomProfiles
+	cfg.Hooks = slices.Clone(FFConfig.Hooks)
+	cfg.UseHooks = slices.Clone(FFConfig.UseHooks)
+	return cfg
+}
+
 func init() {
 	FFConfig = NewFirefoxConfig()
 	config.RegisterConfigurator(BrowserName, config.AsConfigurator(FFConfig))
+	config.RegisterConfReadyHooks(func(context.Context, *cli.Command) error {
+		return modules.SetHooks[*tree.Node](FFConfig.BrowserConfig, FFConfig.Hooks)
+	})
 
 	// An example of running custom code when config is ready
 	// config.RegisterConfReadyHooks(func(c *cli.Context) error{
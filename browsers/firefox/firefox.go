@@ -33,10 +33,12 @@ import (
 	"github.com/blob42/gosuki/pkg/browsers"
 	"github.com/blob42/gosuki/pkg/browsers/mozilla"
 	"github.com/blob42/gosuki/pkg/events"
+	"github.com/blob42/gosuki/pkg/history"
 	"github.com/blob42/gosuki/pkg/logging"
 	"github.com/blob42/gosuki/pkg/modules"
 	"github.com/blob42/gosuki/pkg/parsing"
 	"github.com/blob42/gosuki/pkg/profiles"
+	"github.com/blob42/gosuki/pkg/tabs"
 
 	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/pkg/tree"
@@ -91,6 +93,10 @@ type Firefox struct {
 	activeProfile *profiles.Profile
 
 	activeFlavour *browsers.BrowserDef
+
+	// true when places.sqlite is not available and bookmarks are read from
+	// the profile's bookmark backups
+	usingBackups bool
 }
 
 // GetCurFlavour implements profiles.ProfileManager.
@@ -234,6 +240,35 @@ func (f *Firefox) scanBookmarks() ([]*MozBookmark, error) {
 	return bookmarks, err
 }
 
+// scans bookmarks from the newest bookmark backup of the profile. Folders are
+// loaded into the node tree.
+func (f *Firefox) scanBackupBookmarks() ([]*MozBookmark, error) {
+	backupPath, err := mozilla.LatestBookmarkBackup(f.BkDir)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := mozilla.ParseBookmarkBackup(backupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	folders, bookmarks := backup.Flatten()
+
+	f.folderScanMap = make(map[sqlid]*MozFolder)
+	for _, folder := range folders {
+		f.folderScanMap[folder.ID] = folder
+	}
+
+	for _, folder := range folders {
+		f.addFolderNode(*folder)
+	}
+
+	log.Infof("<%s> loaded bookmarks from backup <%s>", f.fullID(), utils.Shorten(backupPath))
+
+	return bookmarks, nil
+}
+
 func (f *Firefox) scanModifiedBookmarks(since timestamp) ([]*MozBookmark, error) {
 	// scan new/modifed folders and load them into node tree
 	_, err := f.scanFolders(since)
@@ -307,6 +342,11 @@ func (*Firefox) GetProfiles(flavour string) ([]*profiles.Profile, error) {
 	return append(profiles, customProfiles...), nil
 }
 
+// ProfileIndexFiles implements the profiles.ProfileIndexer interface
+func (*Firefox) ProfileIndexFiles(flavour string) ([]string, error) {
+	return FirefoxProfileManager.ProfileIndexFiles(flavour)
+}
+
 func (f *Firefox) WatchAllProfiles() bool {
 	return FFConfig.WatchAllProfiles
 }
@@ -363,8 +403,8 @@ func (f *Firefox) Init(ctx *modules.Context, p *profiles.Profile) error {
 	}
 
 	//TEST: try multiple profiles at same time
-	// use a new config for this profile
-	f.FirefoxConfig = NewFirefoxConfig()
+	// use a copy of the config for this profile
+	f.FirefoxConfig = profileConfig()
 	f.Profile = p.Name
 
 	if bookmarkDir, err := p.AbsolutePath(); err != nil {
@@ -382,7 +422,6 @@ func (f *Firefox) init(ctx *modules.Context) error {
 	log.Debugf("initializing <%s>", f.fullID())
 
 	watchedPath := f.BkDir
-	log.Debugf("Watching path: %s", watchedPath)
 
 	// Setup watcher
 	w := &watch.Watch{
@@ -392,6 +431,29 @@ func (f *Firefox) init(ctx *modules.Context) error {
 		ResetWatch: false,
 	}
 
+	// places.sqlite is missing, use the bookmark backups instead and watch
+	// for new backups
+	if _, err := f.BookmarkPath(); err != nil {
+		backup, bErr := mozilla.LatestBookmarkBackup(f.BkDir)
+		if bErr != nil {
+			log.Error(err)
+			return modules.ErrWatcherSetup
+		}
+
+		log.Warnf("<%s> %s, using bookmark backups", f.fullID(), err)
+		f.usingBackups = true
+		f.BkFile = filepath.Join(mozilla.BookmarkBackupsDir, filepath.Base(backup))
+		watchedPath = filepath.Join(f.BkDir, mozilla.BookmarkBackupsDir)
+		w = &watch.Watch{
+			Path:       watchedPath,
+			EventTypes: []fsnotify.Op{fsnotify.Create},
+			EventNames: []string{"*"},
+			ResetWatch: false,
+		}
+	}
+
+	log.Debugf("Watching path: %s", watchedPath)
+
 	ok, err := modules.SetupWatchersWithReducer(f.BrowserConfig, modules.ReducerChanLen, w)
 	if err != nil {
 		log.Error(err)
@@ -421,23 +483,17 @@ func (f Firefox) Config() *modules.BrowserConfig {
 	return f.BrowserConfig
 }
 
+// ProfileConfig implements the modules.ProfileConfigurer interface
+func (Firefox) ProfileConfig() *modules.BrowserConfig {
+	return profileConfig().BrowserConfig
+}
+
 // Firefox custom logic for preloading the bookmarks when the browser module
 // starts. Implements modules.PreLoader interface.
 func (f *Firefox) PreLoad(_ *modules.Context) error {
-	pc, err := f.initPlacesCopy()
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if err = pc.Clean(); err != nil {
-			log.Errorf("error cleaning tmp places file: %s", err)
-		}
-	}()
-
 	// load all bookmarks
 	start := time.Now()
-	bookmarks, err := f.scanBookmarks()
+	bookmarks, err := f.scanAllBookmarks()
 	if err != nil {
 		return err
 	}
@@ -479,33 +535,61 @@ func (f *Firefox) PreLoad(_ *modules.Context) error {
 	//DEBUG:
 	// tree.PrintTree(f.NodeTree)
 
-	// Close the copy places.sqlite
-	err = f.places.Close()
+	return nil
+}
 
-	return err
+// scanAllBookmarks scans all bookmarks from places.sqlite. If places.sqlite
+// cannot be opened, the newest bookmark backup is used instead.
+func (f *Firefox) scanAllBookmarks() ([]*MozBookmark, error) {
+	if f.usingBackups {
+		return f.scanBackupBookmarks()
+	}
+
+	release, err := f.openPlaces()
+	defer release()
+
+	if err != nil {
+		log.Warnf("<%s> %s: falling back to bookmark backups", f.fullID(), err)
+		return f.scanBackupBookmarks()
+	}
+
+	return f.scanBookmarks()
 }
 
 // Implements modules.Runner interface
 func (ff *Firefox) Run() {
+	var bookmarks []*MozBookmark
+	var err error
 	startRun := time.Now()
 
-	pc, err := ff.initPlacesCopy()
-	if err != nil {
-		log.Error(err)
+	if !ff.usingBackups {
+		var release func()
+		release, err = ff.openPlaces()
+		defer release()
+		if err != nil {
+			log.Warnf("<%s> %s: falling back to bookmark backups", ff.fullID(), err)
+		}
 	}
-	defer pc.Clean()
 
-	// go one step back in time to avoid missing changes
-	scanSince := ff.lastRunAt.Add(-1 * time.Second)
-	scanSinceSQL := scanSince.UTC().UnixNano() / 1000
+	if ff.usingBackups || err != nil {
+		// backups are full snapshots, rescan everything
+		bookmarks, err = ff.scanBackupBookmarks()
+		if err != nil {
+			log.Error(err)
+		}
+	} else {
+		// go one step back in time to avoid missing changes
+		scanSince := ff.lastRunAt.Add(-1 * time.Second)
+		scanSinceSQL := scanSince.UTC().UnixNano() / 1000
 
-	log.Debugf("Checking changes since <%d> %s",
-		scanSinceSQL,
-		scanSince.Local().Format("Mon Jan 2 15:04:05 MST 2006"))
+		log.Debugf("Checking changes since <%d> %s",
+			scanSinceSQL,
+			scanSince.Local().Format("Mon Jan 2 15:04:05 MST 2006"))
 
-	bookmarks, err := ff.scanModifiedBookmarks(scanSinceSQL)
-	if err != nil {
-		log.Error(err)
+		bookmarks, err = ff.scanModifiedBookmarks(scanSinceSQL)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 	ff.loadBookmarksToTree(bookmarks, true)
 	// tree.PrintTree(ff.NodeTree)
@@ -686,6 +770,52 @@ func (f *Firefox) addFolderNode(folder MozFolder) (bool, *tree.Node) {
 	return true, folderNode
 }
 
+// openPlaces opens places.sqlite for reading. The database is read in place
+// unless it is exclusively locked by Firefox (VFS lock), in which case it is
+// copied to a tmp dir first. The returned func closes the database and cleans
+// up the copy, it must always be called.
+func (f *Firefox) openPlaces() (func(), error) {
+	locked, err := mozilla.PlacesLocked(path.Join(f.BkDir, f.BkFile))
+	if err != nil {
+		log.Debugf("<%s> checking VFS lock: %s", f.fullID(), err)
+		locked = true
+	}
+
+	if !locked {
+		if err = f.initPlacesDirect(); err == nil {
+			log.Debugf("<%s> reading places.sqlite directly", f.fullID())
+			return f.closePlaces, nil
+		}
+		log.Debugf("<%s> direct read failed: %s", f.fullID(), err)
+	}
+
+	log.Debugf("<%s> places.sqlite is locked, using a copy", f.fullID())
+	pc, err := f.initPlacesCopy()
+	return func() {
+		f.closePlaces()
+		if err := pc.Clean(); err != nil {
+			log.Errorf("error cleaning tmp places file: %s", err)
+		}
+	}, err
+}
+
+// Opens places.sqlite in place, only possible when the db is not VFS locked.
+// The connection is query only; a read only (mode=ro) connection would leave
+// stale -wal and -shm files in the profile when Firefox is not running.
+func (f *Firefox) initPlacesDirect() error {
+	opts := database.DsnOptions{"_query_only": "true"}
+	for k, v := range FFConfig.PlacesDSN {
+		opts[k] = v
+	}
+
+	var err error
+	f.places, err = database.NewDB("places",
+		path.Join(f.BkDir, f.BkFile),
+		database.DBTypeFileDSN, opts).Init()
+
+	return err
+}
+
 // Copies places.sqlite to a tmp dir to read a VFS lock sqlite db
 func (f *Firefox) initPlacesCopy() (mozilla.PlaceCopyJob, error) {
 	// create a new copy job
@@ -711,9 +841,22 @@ func (f *Firefox) initPlacesCopy() (mozilla.PlaceCopyJob, error) {
 	return pc, nil
 }
 
+func (f *Firefox) closePlaces() {
+	if f.places == nil {
+		return
+	}
+
+	if err := f.places.Close(); err != nil {
+		log.Errorf("<%s> closing places.sqlite: %s", f.fullID(), err)
+	}
+	f.places = nil
+}
+
 // init is required to register the module as a plugin when it is imported
 func init() {
 	modules.RegisterBrowser(Firefox{FirefoxConfig: FFConfig})
+	tabs.RegisterReader(BrowserName, NewFirefox())
+	history.RegisterReader(BrowserName, NewFirefox())
 
 	// Exaple for registering a command under the browser name
 	//TIP: cmd.RegisterModCommand(BrowserName, &cli.Command{
@@ -729,9 +872,17 @@ func init() {
 var _ modules.BrowserModule = (*Firefox)(nil)
 var _ modules.ProfileInitializer = (*Firefox)(nil)
 var _ profiles.ProfileManager = (*Firefox)(nil)
+var _ profiles.ProfileIndexer = (*Firefox)(nil)
+var _ tabs.ProfileReader = (*Firefox)(nil)
+var _ history.ProfileReader = (*Firefox)(nil)
 var _ modules.PreLoader = (*Firefox)(nil)
 var _ modules.Shutdowner = (*Firefox)(nil)
 var _ watch.WatchRunner = (*Firefox)(nil)
 var _ hooks.HookRunner = (*Firefox)(nil)
 var _ parsing.Counter = (*Firefox)(nil)
 var _ profiles.ProfileManager = (*Firefox)(nil)
+
+// OpenTabs implements the tabs.ProfileReader interface
+func (*Firefox) OpenTabs(p *profiles.Profile) ([]*tabs.Tab, error) {
+	return tabs.ReadProfile(p, mozilla.ReadSessionTabs)
+}
@@ -708,6 +708,55 @@ func Test_FindModifiedBookmarks(t *testing.T) {
 	})
 }
 
+func Test_scanBackupBookmarks(t *testing.T) {
+	setupFirefox()
+	ff.BkDir = "../../pkg/browsers/mozilla/testdata/backup-profile"
+	defer setupFirefox()
+
+	bookmarks, err := ff.scanBackupBookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, bookmarks, 4)
+
+	ff.loadBookmarksToTree(bookmarks, false)
+
+	iNode, exists := ff.URLIndex.Get("https://go.dev/doc/")
+	if !exists {
+		t.Fatal("url not found in index")
+	}
+
+	bk := iNode.(*tree.Node).GetBookmark()
+	assert.ElementsMatch(t, []string{"Golang", "Dev", "menu"}, bk.Tags)
+
+	iNode, exists = ff.URLIndex.Get("https://github.com/blob42/gosuki")
+	if !exists {
+		t.Fatal("url not found in index")
+	}
+	bk = iNode.(*tree.Node).GetBookmark()
+	assert.ElementsMatch(t, []string{"go", "bookmarks", "menu"}, bk.Tags)
+}
+
+func Test_openPlaces(t *testing.T) {
+	setupFirefox()
+	defer setupFirefox()
+
+	// places.sqlite is not locked, it should be read in place
+	release, err := ff.openPlaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, ff.places.Path, ff.BkDir)
+	assert.Contains(t, ff.places.Path, "_query_only=true")
+
+	bookmarks, err := ff.scanBookmarks()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, bookmarks)
+
+	release()
+	assert.Nil(t, ff.places)
+}
+
 func TestBrowserImplProfileManager(t *testing.T) {
 	assert.Implements(t, (*profiles.ProfileManager)(nil), NewFirefox())
 }
@@ -715,3 +764,21 @@ func TestBrowserImplProfileManager(t *testing.T) {
 // func Test_FindModifiedFolders(t *testing.T) {
 // 	t.Skip("modified folder names should change the corresponding bookmark tags")
 // }
+
+func TestProfileConfig(t *testing.T) {
+	savedHooks, savedUse := FFConfig.Hooks, FFConfig.UseHooks
+	defer func() { FFConfig.Hooks, FFConfig.UseHooks = savedHooks, savedUse }()
+	FFConfig.Hooks = []string{"node_tags_from_name"}
+	FFConfig.UseHooks = FFConfig.Hooks
+
+	cfg := profileConfig()
+	assert.Equal(t, []string{"node_tags_from_name"}, cfg.UseHooks)
+	assert.Equal(t, cfg.UseHooks, Firefox{}.ProfileConfig().UseHooks)
+	assert.Equal(t, FFConfig.ProfilePrefs, cfg.ProfilePrefs)
+
+	cfg.UseHooks[0] = "changed"
+	assert.Equal(t, "node_tags_from_name", FFConfig.UseHooks[0])
+
+	NewFirefoxConfig().UseHooks[0] = "changed"
+	assert.Equal(t, "node_tags_from_name", DefaultHooks[0])
+}
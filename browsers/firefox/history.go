@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package firefox
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/blob42/gosuki/pkg/browsers/mozilla"
+	"github.com/blob42/gosuki/pkg/history"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+// Visited pages, last_visit_date is in microseconds since epoch
+const QHistorySince = `
+	SELECT url, COALESCE(title, '') AS title, visit_count, last_visit_date
+	FROM moz_places
+	WHERE hidden = 0 AND visit_count > 0 AND last_visit_date > ?
+`
+
+type mozVisit struct {
+	URL           string
+	Title         string
+	VisitCount    int   `db:"visit_count"`
+	LastVisitDate int64 `db:"last_visit_date"`
+}
+
+// History implements the history.Reader interface
+func (*Firefox) History(p *profiles.Profile, since time.Time) ([]*history.Visit, error) {
+	profileDir, err := p.AbsolutePath()
+	if err != nil {
+		return nil, err
+	}
+
+	places, err := history.OpenSnapshot(filepath.Join(profileDir, mozilla.PlacesFile))
+	if err != nil {
+		return nil, err
+	}
+	defer places.Close()
+
+	visits := []mozVisit{}
+	if err = places.Select(&visits, QHistorySince, since.UnixMicro()); err != nil {
+		return nil, err
+	}
+
+	result := make([]*history.Visit, 0, len(visits))
+	for _, v := range visits {
+		result = append(result, &history.Visit{
+			URL:        v.URL,
+			Title:      v.Title,
+			VisitCount: v.VisitCount,
+			LastVisit:  time.UnixMicro(v.LastVisitDate),
+		})
+	}
+
+	return result, nil
+}
@@ -23,9 +23,17 @@
 package qute
 
 import (
+	"context"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
 	"github.com/blob42/gosuki/pkg/browsers"
+	"github.com/blob42/gosuki/pkg/config"
 	"github.com/blob42/gosuki/pkg/logging"
 	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/profiles"
 )
 
 var QuteBrowser = browsers.Defined(browsers.Qutebrowser)["qutebrowser"]
@@ -35,6 +43,9 @@ const (
 	DefaultProfile = "Default"
 )
 
+// DefaultHooks are the hooks used when the config does not set them
+var DefaultHooks = []string{"bk_tags_from_name", "bk_autotag"}
+
 var (
 	QuteCfg = NewQuteConfig()
 	log     = logging.GetLogger("qute")
@@ -43,7 +54,14 @@ var (
 type QuteConfig struct {
 	quickmarksPath         string `toml:"-"`
 	*modules.BrowserConfig `toml:"-"`
-	modules.ProfilePrefs   `toml:"profile_options" mapstructure:"profile_options"`
+	modules.ProfilePrefs   `toml:"profile-options" mapstructure:"profile-options"`
+
+	// Additional base directories, ex. used with `qutebrowser --basedir`.
+	// The flavour can be left empty.
+	CustomProfiles []profiles.CustomProfile `toml:"custom-profiles" mapstructure:"custom-profiles"`
+
+	// Hooks run on every bookmark loaded from the browser, see `gosuki hooks list`
+	Hooks []string `toml:"hooks" mapstructure:"hooks"`
 }
 
 func NewQuteConfig() *QuteConfig {
@@ -58,12 +76,31 @@ func NewQuteConfig() *QuteConfig {
 			BkDir:          baseDir + "/bookmarks",
 			BaseDir:        baseDir,
 			UseFileWatcher: true,
-			UseHooks:       []string{"bk_tags_from_name"},
+			UseHooks:       slices.Clone(DefaultHooks),
 		},
+		Hooks: slices.Clone(DefaultHooks),
 		ProfilePrefs: modules.ProfilePrefs{
-			Profile: DefaultProfile,
+			Profile:          DefaultProfile,
+			WatchAllProfiles: true,
 		},
 	}
 
 	return config
 }
+
+// profileConfig returns a copy of the user config for a profile instance
+func profileConfig() *QuteConfig {
+	cfg := NewQuteConfig()
+	cfg.ProfilePrefs = QuteCfg.ProfilePrefs
+	cfg.CustomProfiles = QuteCfg.CustomProfiles
+	cfg.Hooks = slices.Clone(QuteCfg.Hooks)
+	cfg.UseHooks = slices.Clone(QuteCfg.UseHooks)
+	return cfg
+}
+
+func init() {
+	config.RegisterConfigurator(BrowserName, config.AsConfigurator(QuteCfg))
+	config.RegisterConfReadyHooks(func(context.Context, *cli.Command) error {
+		return modules.SetHooks[*gosuki.Bookmark](QuteCfg.BrowserConfig, QuteCfg.Hooks)
+	})
+}
@@ -0,0 +1,108 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package qute
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/history"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+const (
+	HistoryFile = "history.sqlite"
+
+	// Directory holding the data files when qutebrowser is started with
+	// `--basedir`
+	basedirDataDir = "data"
+)
+
+// Visited pages, atime is in seconds since epoch
+const QHistorySince = `
+	SELECT url, MAX(title) AS title, COUNT(*) AS visit_count, MAX(atime) AS last_visit
+	FROM History
+	WHERE redirect = 0 AND atime > ?
+	GROUP BY url
+`
+
+type quteVisit struct {
+	URL        string
+	Title      string
+	VisitCount int   `db:"visit_count"`
+	LastVisit  int64 `db:"last_visit"`
+}
+
+// historyPath returns the path of the history database of a profile. The
+// default profile keeps its data in the user data directory.
+func historyPath(p *profiles.Profile, profileDir string) (string, error) {
+	if configDir(profileDir) != profileDir {
+		return filepath.Join(profileDir, basedirDataDir, HistoryFile), nil
+	}
+
+	if p.IsCustom {
+		return filepath.Join(profileDir, HistoryFile), nil
+	}
+
+	dataDir, err := utils.GetDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, BrowserName, HistoryFile), nil
+}
+
+// History implements the history.Reader interface
+func (*Qute) History(p *profiles.Profile, since time.Time) ([]*history.Visit, error) {
+	profileDir, err := p.AbsolutePath()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := historyPath(p, profileDir)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := history.OpenSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	visits := []quteVisit{}
+	if err = db.Select(&visits, QHistorySince, since.Unix()); err != nil {
+		return nil, err
+	}
+
+	result := make([]*history.Visit, 0, len(visits))
+	for _, v := range visits {
+		result = append(result, &history.Visit{
+			URL:        v.URL,
+			Title:      v.Title,
+			VisitCount: v.VisitCount,
+			LastVisit:  time.Unix(v.LastVisit, 0),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,179 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package qute
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/browsers"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+// Directory holding the config files when qutebrowser is started with
+// `--basedir`
+const basedirConfigDir = "config"
+
+// GetProfiles implements the profiles.ProfileManager interface. The default
+// profile is the flavour base directory, custom profiles are additional
+// base directories from the config.
+func (*Qute) GetProfiles(flavour string) ([]*profiles.Profile, error) {
+	flv, ok := browsers.Defined(browsers.Qutebrowser)[flavour]
+	if !ok {
+		return nil, fmt.Errorf("unknown flavour <%s>", flavour)
+	}
+
+	var result []*profiles.Profile
+	if baseDir, err := flv.ExpandBaseDir(); err == nil {
+		result = append(result, &profiles.Profile{
+			ID:      DefaultProfile,
+			Name:    DefaultProfile,
+			Path:    baseDir,
+			BaseDir: baseDir,
+		})
+	}
+
+	result = append(result, profiles.FromCustom(customProfiles(), flavour)...)
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no profiles found for <%s>", flavour)
+	}
+
+	return result, nil
+}
+
+// customProfiles returns the custom profiles from the config, using the
+// default flavour when none is set
+func customProfiles() []profiles.CustomProfile {
+	result := make([]profiles.CustomProfile, 0, len(QuteCfg.CustomProfiles))
+	for _, cp := range QuteCfg.CustomProfiles {
+		if cp.Flavour == "" {
+			cp.Flavour = BrowserName
+		}
+		result = append(result, cp)
+	}
+	return result
+}
+
+func (qu *Qute) GetProfileByName(flavour string, name string) (*profiles.Profile, error) {
+	profs, err := qu.GetProfiles(flavour)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range profs {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("profile %s not found", name)
+}
+
+// ListFlavours returns the detected flavours or the ones used by a custom
+// profile
+func (*Qute) ListFlavours() []browsers.BrowserDef {
+	var result []browsers.BrowserDef
+
+	custom := map[string]bool{}
+	for _, cp := range customProfiles() {
+		custom[cp.Flavour] = true
+	}
+
+	for _, v := range browsers.Defined(browsers.Qutebrowser) {
+		if custom[v.Flavour] || v.Detect() {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func (qu *Qute) WatchAllProfiles() bool {
+	return QuteCfg.WatchAllProfiles
+}
+
+func (qu *Qute) UseProfile(p *profiles.Profile, flv *browsers.BrowserDef) error {
+	if p != nil {
+		qu.activeProfile = p
+	}
+
+	if flv != nil {
+		qu.activeFlavour = flv
+	}
+
+	return nil
+}
+
+func (qu *Qute) GetProfile() *profiles.Profile {
+	return qu.activeProfile
+}
+
+func (qu *Qute) GetCurFlavour() *browsers.BrowserDef {
+	return qu.activeFlavour
+}
+
+// configDir returns the directory holding the bookmarks and quickmarks of a
+// profile. Base directories created with `--basedir` keep them in a `config`
+// sub directory.
+func configDir(profileDir string) string {
+	if _, err := os.Stat(filepath.Join(profileDir, "quickmarks")); err == nil {
+		return profileDir
+	}
+
+	sub := filepath.Join(profileDir, basedirConfigDir)
+	if exists, _ := utils.DirExists(sub); exists {
+		return sub
+	}
+	return profileDir
+}
+
+// usePaths sets the bookmark and quickmark paths from the profile directory
+func (qu *Qute) usePaths(profileDir string) error {
+	dir := configDir(profileDir)
+	qu.BaseDir = dir
+	qu.BkDir = filepath.Join(dir, "bookmarks")
+	qu.quickmarksPath = filepath.Join(dir, "quickmarks")
+
+	// This section handles symlinks to qutebrowser
+	// Typically the case with dotfiles.
+	isSym, err := utils.IsSymlink(qu.quickmarksPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// set parent directory as the new base dir
+	if isSym {
+		fullQuickmarksPath, err := filepath.EvalSymlinks(qu.quickmarksPath)
+		if err != nil {
+			return err
+		}
+
+		qu.quickmarksPath = fullQuickmarksPath
+		qu.BkDir = filepath.Join(filepath.Dir(fullQuickmarksPath), "bookmarks")
+		qu.BaseDir = filepath.Dir(fullQuickmarksPath)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package qute
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+func TestProfiles(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, basedirConfigDir, "bookmarks"), 0o700))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(projectDir, basedirConfigDir, "bookmarks", "urls"), nil, 0o600))
+
+	plainDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(plainDir, "quickmarks"), nil, 0o600))
+
+	saved := QuteCfg.CustomProfiles
+	defer func() { QuteCfg.CustomProfiles = saved }()
+	QuteCfg.CustomProfiles = []profiles.CustomProfile{
+		{Name: "project", Path: projectDir},
+		{Name: "plain", Path: plainDir, Flavour: BrowserName},
+		{Name: "other", Path: plainDir, Flavour: "unknown"},
+	}
+
+	qu := NewQute()
+
+	t.Run("GetProfiles", func(t *testing.T) {
+		profs, err := qu.GetProfiles(BrowserName)
+		require.NoError(t, err)
+
+		var names []string
+		for _, p := range profs {
+			names = append(names, p.Name)
+		}
+		assert.Contains(t, names, "project")
+		assert.Contains(t, names, "plain")
+		assert.NotContains(t, names, "other")
+
+		flavours := qu.ListFlavours()
+		require.Len(t, flavours, 1)
+		assert.Equal(t, BrowserName, flavours[0].Flavour)
+	})
+
+	t.Run("Init", func(t *testing.T) {
+		p, err := qu.GetProfileByName(BrowserName, "project")
+		require.NoError(t, err)
+
+		q := NewQute()
+		require.NoError(t, q.UseProfile(p, nil))
+		require.NoError(t, q.Init(nil, p))
+		defer q.Shutdown()
+
+		assert.Equal(t, "qutebrowser_project", q.fullID())
+		assert.Equal(t, filepath.Join(projectDir, basedirConfigDir, "bookmarks"), q.BkDir)
+		assert.Equal(t, filepath.Join(projectDir, basedirConfigDir, "quickmarks"), q.quickmarksPath)
+
+		// the shared config is left untouched
+		assert.NotEqual(t, q.QuteConfig, QuteCfg)
+		assert.Equal(t, DefaultProfile, QuteCfg.Profile)
+	})
+
+	t.Run("profile config", func(t *testing.T) {
+		savedHooks := QuteCfg.Hooks
+		savedUse := QuteCfg.UseHooks
+		defer func() {
+			QuteCfg.Hooks = savedHooks
+			QuteCfg.UseHooks = savedUse
+		}()
+		QuteCfg.Hooks = []string{"bk_tags_from_name"}
+		QuteCfg.UseHooks = QuteCfg.Hooks
+
+		p, err := qu.GetProfileByName(BrowserName, "project")
+		require.NoError(t, err)
+
+		q := NewQute()
+		require.NoError(t, q.UseProfile(p, nil))
+		require.NoError(t, q.Init(nil, p))
+		defer q.Shutdown()
+
+		assert.Equal(t, []string{"bk_tags_from_name"}, q.UseHooks)
+		assert.Equal(t, QuteCfg.CustomProfiles, q.CustomProfiles)
+
+		q.UseHooks[0] = "changed"
+		assert.Equal(t, "bk_tags_from_name", QuteCfg.UseHooks[0])
+	})
+
+	t.Run("default profile module", func(t *testing.T) {
+		q := NewQute()
+		q.Profile = DefaultProfile
+		assert.Equal(t, BrowserName, q.fullID())
+	})
+
+	t.Run("configDir", func(t *testing.T) {
+		assert.Equal(t, filepath.Join(projectDir, basedirConfigDir), configDir(projectDir))
+		assert.Equal(t, plainDir, configDir(plainDir))
+	})
+}
@@ -28,7 +28,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -38,9 +37,12 @@ import (
 	"github.com/blob42/gosuki/hooks"
 	"github.com/blob42/gosuki/internal/database"
 	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/browsers"
 	"github.com/blob42/gosuki/pkg/events"
+	"github.com/blob42/gosuki/pkg/history"
 	"github.com/blob42/gosuki/pkg/modules"
 	"github.com/blob42/gosuki/pkg/parsing"
+	"github.com/blob42/gosuki/pkg/profiles"
 	"github.com/blob42/gosuki/pkg/watch"
 )
 
@@ -50,6 +52,9 @@ type Qute struct {
 	*QuteConfig
 	parsing.Counter
 	lastSentProgress float64
+
+	activeProfile *profiles.Profile
+	activeFlavour *browsers.BrowserDef
 }
 
 // PreCount implements parsing.Counter.
@@ -72,17 +77,18 @@ func (qu *Qute) preCountUrls() error {
 	}
 	qu.AddTotal(uint(count))
 
+	// quickmarks are optional, the file is created on the first quickmark
 	qmFile, err := os.Open(qu.quickmarksPath)
-	if err != nil {
+	if err == nil {
+		defer qmFile.Close()
+		if count, err = utils.CountLines(qmFile); err != nil {
+			return fmt.Errorf("reading file %s : %w", qu.quickmarksPath, err)
+		}
+		qu.AddTotal(uint(count))
+	} else if !errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("open %s : %w", qu.quickmarksPath, err)
 	}
 
-	if count, err = utils.CountLines(qmFile); err != nil {
-		return fmt.Errorf("reading file %s : %w", qu.quickmarksPath, err)
-	}
-
-	qu.AddTotal(uint(count))
-
 	// Send total to msg bus
 	go func() {
 		events.TUIBus <- events.StartedLoadingMsg{
@@ -114,43 +120,46 @@ func (qu *Qute) Detect() ([]modules.Detected, error) {
 	return res, nil
 }
 
-func (qu Qute) Init(ctx *modules.Context) error {
+// Init implements modules.ProfileInitializer. Each profile is a qutebrowser
+// base directory.
+func (qu *Qute) Init(ctx *modules.Context, p *profiles.Profile) error {
 	var err error
 
-	// This section handles symlinks to qutebrowser
-	// Typically the case with dotfiles.
-	qu.quickmarksPath, err = utils.ExpandOnly(qu.quickmarksPath)
-	if err != nil {
-		return err
+	if p == nil {
+		if p, err = qu.GetProfileByName(BrowserName, qu.Profile); err != nil {
+			return err
+		}
+	} else {
+		// use a copy of the config for this profile
+		qu.QuteConfig = profileConfig()
 	}
 
-	isSym, err := utils.IsSymlink(qu.quickmarksPath)
+	qu.Profile = p.Name
+	qu.activeProfile = p
+
+	profileDir, err := p.AbsolutePath()
 	if err != nil {
 		return err
 	}
 
-	// set parent directory as the new base dir
-	if isSym {
-		fullQuickmarksPath, err := filepath.EvalSymlinks(qu.quickmarksPath)
-		if err != nil {
-			return err
-		}
-
-		qu.quickmarksPath = fullQuickmarksPath
-		qu.BkDir = filepath.Dir(fullQuickmarksPath) + "/bookmarks"
-		qu.BaseDir = filepath.Dir(fullQuickmarksPath)
-	} else {
-		qu.BaseDir, err = utils.ExpandPath(qu.BaseDir)
-		if err != nil {
-			return fmt.Errorf("expadning %s : %w", qu.BaseDir, err)
-		}
-
+	if err = qu.usePaths(profileDir); err != nil {
+		return err
 	}
 
+	log.Debugf("initializing <%s>", qu.fullID())
 	return qu.setupWatchers()
 }
 
-func (qu Qute) setupWatchers() error {
+// fullID returns the module name used for bookmarks of the active profile.
+// Bookmarks of the default profile keep the browser name.
+func (qu *Qute) fullID() string {
+	if qu.Profile == DefaultProfile {
+		return qu.Name
+	}
+	return fmt.Sprintf("%s_%s", qu.Name, qu.Profile)
+}
+
+func (qu *Qute) setupWatchers() error {
 	bookmarkPath, err := qu.BookmarkPath()
 	if err != nil {
 		return fmt.Errorf("%s : %w", bookmarkPath, err)
@@ -188,6 +197,11 @@ func (qu Qute) Config() *modules.BrowserConfig {
 	return qu.BrowserConfig
 }
 
+// ProfileConfig implements the modules.ProfileConfigurer interface
+func (Qute) ProfileConfig() *modules.BrowserConfig {
+	return profileConfig().BrowserConfig
+}
+
 func (qu Qute) ModInfo() modules.ModInfo {
 	return modules.ModInfo{
 		ID: modules.ModID(qu.Name),
@@ -235,7 +249,7 @@ func (qu *Qute) loadBookmarks(runTask bool) error {
 				strings.Join(fields[1:], " "),
 			),
 			Desc:   "",
-			Module: qu.Name,
+			Module: qu.fullID(),
 		}
 
 		qu.CallHooks(bk)
@@ -250,9 +264,12 @@ func (qu *Qute) loadBookmarks(runTask bool) error {
 
 func (qu *Qute) loadQuickMarks(runTask bool) error {
 	qmFile, err := os.Open(qu.quickmarksPath)
-	if err != nil {
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
 		return err
 	}
+	defer qmFile.Close()
 
 	reader := bufio.NewReader(qmFile)
 
@@ -275,7 +292,7 @@ func (qu *Qute) loadQuickMarks(runTask bool) error {
 		bk := &gosuki.Bookmark{
 			URL:    strings.TrimSpace(fields[len(fields)-1]), // Last field is the URL
 			Tags:   fields[:len(fields)-1],
-			Module: qu.Name,
+			Module: qu.fullID(),
 		}
 
 		// Call hooks on bookmark instead of node
@@ -333,11 +350,11 @@ func (qu *Qute) load(runTask bool) error {
 	}
 
 	qu.SetLastTreeParseRuntime(time.Since(startWork))
-	log.Debugf("<%s> loaded bookmarks in %s", qu.Name, qu.LastFullTreeParseRT())
+	log.Debugf("<%s> loaded bookmarks in %s", qu.fullID(), qu.LastFullTreeParseRT())
 
 	err = qu.BufferDB.SyncToCache()
 	if err != nil {
-		log.Errorf("<%s>: %v", qu.Name, err)
+		log.Errorf("<%s>: %v", qu.fullID(), err)
 	}
 
 	database.ScheduleBackupToDisk()
@@ -369,12 +386,15 @@ func NewQute() *Qute {
 
 func init() {
 	modules.RegisterBrowser(Qute{QuteConfig: QuteCfg})
+	history.RegisterReader(BrowserName, NewQute())
 }
 
 // interface guards
 
 var _ modules.BrowserModule = (*Qute)(nil)
-var _ modules.Initializer = (*Qute)(nil)
+var _ modules.ProfileInitializer = (*Qute)(nil)
+var _ profiles.ProfileManager = (*Qute)(nil)
+var _ history.ProfileReader = (*Qute)(nil)
 
 var _ modules.Detector = (*Qute)(nil)
 var _ watch.WatchRunner = (*Qute)(nil)
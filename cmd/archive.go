@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/archive"
+)
+
+var ArchiveCmds = &cli.Command{
+	Name:      "archive",
+	Usage:     "save a local snapshot of web pages",
+	ArgsUsage: "URL...",
+	Description: `Fetches the pages and their assets into a single file under the archive
+directory and records the snapshots in the database. The daemon archives the
+bookmarks with the tag set in the [archive] config section.
+
+The snapshots are written to the database file, a running daemon overwrites
+it with its cache on the next sync: stop the daemon first or tag the
+bookmarks with the archive tag instead.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "snapshot `FORMAT`: html or warc",
+		},
+	},
+	Action: archiveURLs,
+	Commands: []*cli.Command{
+		listArchivesCmd,
+	},
+}
+
+func archiveURLs(ctx context.Context, cmd *cli.Command) error {
+	if cmd.NArg() == 0 {
+		return errors.New("missing URL")
+	}
+
+	a, err := archive.NewArchiver()
+	if err != nil {
+		return err
+	}
+	switch format := cmd.String("format"); format {
+	case "":
+	case archive.FormatHTML, archive.FormatWARC:
+		a.Format = format
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	db.Init(ctx, cmd)
+	defer db.DiskDB.Close()
+
+	var failed int
+	for _, u := range cmd.Args().Slice() {
+		snap, err := a.Archive(ctx, u)
+		if err == nil {
+			err = db.DiskDB.SaveSnapshot(snap)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "archiving %s: %s\n", u, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s\n  %s (%d bytes)\n", u, filepath.Join(a.Dir, snap.Path), snap.Size)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d page(s) could not be archived", failed)
+	}
+	return nil
+}
+
+var listArchivesCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list the snapshots, most recent first",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "show at most `N` snapshots",
+			Value: 20,
+		},
+		&cli.StringFlag{
+			Name:  "query",
+			Usage: "only show snapshots with `TEXT` in the URL or title",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		db.Init(ctx, cmd)
+		defer db.DiskDB.Close()
+
+		result, err := db.QuerySnapshots(ctx, cmd.String("query"),
+			&db.PaginationParams{Page: 1, Size: int(cmd.Int("limit"))})
+		if err != nil {
+			return err
+		}
+
+		for _, snap := range result.Snapshots {
+			path, err := snap.File()
+			if err != nil {
+				path = snap.Path
+			}
+			fmt.Printf("%-5d %s  %-4s %s\n  %s\n",
+				snap.ID,
+				time.Unix(snap.Created, 0).Format(time.DateTime),
+				snap.Format,
+				snap.URL,
+				path,
+			)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v3"
+
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/autotag"
+)
+
+var AutotagCmds = &cli.Command{
+	Name:  "autotag",
+	Usage: "rule based tagging commands",
+	Description: `Auto-tagging rules are defined in the [autotag] section of the config
+file. New bookmarks are tagged by the daemon, the apply command runs the rules
+on the bookmarks already stored in the database.`,
+	Commands: []*cli.Command{
+		applyAutotagCmd,
+	},
+}
+
+var applyAutotagCmd = &cli.Command{
+	Name:  "apply",
+	Usage: "apply the auto-tagging rules to the stored bookmarks",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "dry-run",
+			Aliases: []string{"n"},
+			Usage:   "only show the changes that would be made",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		engine, err := autotag.NewEngine(autotag.Config.Rules)
+		if err != nil {
+			return err
+		}
+		if len(engine.Rules) == 0 {
+			return fmt.Errorf("no auto-tagging rules defined in config")
+		}
+
+		db.Init(ctx, cmd)
+		defer db.DiskDB.Close()
+
+		result, err := db.ListBookmarks(ctx, &db.PaginationParams{Page: 1, Size: -1})
+		if err != nil {
+			return err
+		}
+
+		dryRun := cmd.Bool("dry-run")
+		green := color.New(color.FgGreen).SprintFunc()
+		red := color.New(color.FgRed).SprintFunc()
+		cyan := color.New(color.FgCyan).SprintFunc()
+
+		var changed int
+		for _, bk := range result.Bookmarks {
+			updated := *bk
+			updated.Tags = slices.Clone(bk.Tags)
+			if len(engine.Apply(&updated)) == 0 {
+				continue
+			}
+
+			var diff []string
+			for _, tag := range updated.Tags {
+				if !slices.Contains(bk.Tags, tag) {
+					diff = append(diff, green("+"+tag))
+				}
+			}
+			for _, tag := range bk.Tags {
+				if !slices.Contains(updated.Tags, tag) {
+					diff = append(diff, red("-"+tag))
+				}
+			}
+			if updated.Desc != bk.Desc {
+				diff = append(diff, fmt.Sprintf("desc=%q", updated.Desc))
+			}
+			if len(diff) == 0 {
+				continue
+			}
+
+			fmt.Printf("%s\n  %s\n", cyan(bk.URL), strings.Join(diff, " "))
+			if !dryRun {
+				if err = db.DiskDB.UpdateBookmark(&updated); err != nil {
+					fmt.Fprintf(os.Stderr, "updating bookmark %s: %s\n", bk.URL, err)
+					continue
+				}
+			}
+			changed++
+		}
+
+		if dryRun {
+			fmt.Printf("%d bookmarks would be changed\n", changed)
+		} else {
+			fmt.Printf("changed %d bookmarks\n", changed)
+		}
+
+		return nil
+	},
+}
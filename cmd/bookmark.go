@@ -0,0 +1,119 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+var BookmarkCmds = &cli.Command{
+	Name:    "bookmark",
+	Aliases: []string{"bk"},
+	Usage:   "bookmark reading state and favorite commands",
+	Commands: []*cli.Command{
+		setStateCmd,
+		favoriteCmd,
+	},
+}
+
+var bookmarkURLArg = &cli.StringArg{
+	Name:      "url",
+	UsageText: "URL of the bookmark",
+	Config: cli.StringConfig{
+		TrimSpace: true,
+	},
+}
+
+var setStateCmd = &cli.Command{
+	Name:  "state",
+	Usage: "set the reading state of a bookmark",
+	Description: `Set the reading list state of a bookmark to one of: none, unread, read or
+archived.`,
+	ArgsUsage: "url state",
+	Arguments: []cli.Argument{
+		bookmarkURLArg,
+		&cli.StringArg{
+			Name:      "state",
+			UsageText: "none, unread, read or archived",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		url := cmd.StringArg("url")
+		if url == "" {
+			return errors.New("missing bookmark url")
+		}
+
+		state, err := gosuki.ParseReadState(cmd.StringArg("state"))
+		if err != nil {
+			return err
+		}
+
+		db.Init(ctx, cmd)
+		defer db.DiskDB.Close()
+
+		if err = db.SetBookmarkFlags(url, gosuki.StateMask, gosuki.Flags(0).WithState(state)); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s\n", url, state)
+
+		return nil
+	},
+}
+
+var favoriteCmd = &cli.Command{
+	Name:      "favorite",
+	Aliases:   []string{"fav"},
+	Usage:     "mark a bookmark as favorite",
+	ArgsUsage: "url",
+	Arguments: []cli.Argument{bookmarkURLArg},
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "unset",
+			Aliases: []string{"u"},
+			Usage:   "remove the bookmark from favorites",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		url := cmd.StringArg("url")
+		if url == "" {
+			return errors.New("missing bookmark url")
+		}
+
+		db.Init(ctx, cmd)
+		defer db.DiskDB.Close()
+
+		fav := !cmd.Bool("unset")
+		if err := db.SetBookmarkFlags(url, gosuki.FlagFavorite, gosuki.Flags(0).WithFavorite(fav)); err != nil {
+			return err
+		}
+		fmt.Printf("%s: favorite=%t\n", url, fav)
+
+		return nil
+	},
+}
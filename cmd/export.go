@@ -42,6 +42,7 @@ var ExportCmds = &cli.Command{
 	Commands: []*cli.Command{
 		exportNSHTMLCmd,
 		exportPocketHTMLCmd,
+		exportPocketCSVCmd,
 		exportJSONCmd,
 		exportRSSCmd,
 	},
@@ -126,6 +127,25 @@ var exportPocketHTMLCmd = &cli.Command{
 	Flags: []cli.Flag{overwriteFlag},
 }
 
+// exports to pocket export csv file format
+var exportPocketCSVCmd = &cli.Command{
+	Name:        "pocket-csv",
+	Usage:       "Export bookmarks to Pocket CSV format",
+	Description: `Exports all bookmarks to a file in Pocket CSV format. The reading state is kept in the status column.`,
+	ArgsUsage:   "path/to/export.csv",
+	Action:      exportToFormat(export.PocketCSV),
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name:      "path",
+			UsageText: "Export bookmarks to Pocket CSV format. The exported file can be imported back with `gosuki import pocket`.",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+	},
+	Flags: []cli.Flag{overwriteFlag},
+}
+
 func exportToFormat(format int) cli.ActionFunc {
 	return func(ctx context.Context, cmd *cli.Command) error {
 		var rows *sqlx.Rows
@@ -145,7 +165,7 @@ func exportToFormat(format int) cli.ActionFunc {
 		db.Init(ctx, cmd)
 		if rows, err = db.DiskDB.Handle.QueryxContext(
 			ctx,
-			`SELECT * FROM gskbookmarks`,
+			db.QSelectBookmarksWithNotes,
 		); err != nil {
 			return err
 		}
@@ -155,6 +175,8 @@ func exportToFormat(format int) cli.ActionFunc {
 			exporter = &export.NetscapeHTMLExporter{}
 		case export.PocketHTML:
 			exporter = &export.PocketHTMLExporter{}
+		case export.PocketCSV:
+			exporter = &export.PocketCSVExporter{}
 		case export.JSON:
 			exporter = &export.JSONExporter{}
 		case export.RSS:
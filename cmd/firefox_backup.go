@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/browsers/mozilla"
+)
+
+const (
+	FirefoxBackupImporterID = "firefox-backup"
+)
+
+var importFirefoxBackupCmd = &cli.Command{
+	Name:  "firefox-backup",
+	Usage: "Import bookmarks from a Firefox bookmark backup (.jsonlz4 or .json)",
+	Description: `Import bookmarks from a Firefox bookmark backup file into the gosuki database.
+
+Firefox writes a daily backup of the bookmarks under <profile>/bookmarkbackups.
+This command does not need places.sqlite, it can be used to import a profile
+stored on a read-only or locked disk.
+
+If the path is a profile directory, the newest backup found in its
+bookmarkbackups directory is imported. Folder names and Firefox tags are
+imported as tags.`,
+	Action:    importFromFirefoxBackup,
+	ArgsUsage: "path/to/bookmarks.jsonlz4|path/to/profile",
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name:      "path",
+			UsageText: "Path to the backup file or profile directory",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+	},
+}
+
+func importFromFirefoxBackup(ctx context.Context, c *cli.Command) error {
+	path := c.StringArg("path")
+	if path == "" {
+		return errors.New("missing path to firefox backup")
+	}
+
+	expandedPath, err := utils.ExpandPath(path)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(expandedPath)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if expandedPath, err = mozilla.LatestBookmarkBackup(expandedPath); err != nil {
+			return err
+		}
+	}
+
+	backup, err := mozilla.ParseBookmarkBackup(expandedPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("importing from %s\n", utils.Shorten(expandedPath))
+
+	db.Init(ctx, c)
+	DB := db.DiskDB
+	defer db.DiskDB.Close()
+
+	var bkCount int
+	for _, bookmark := range backup.Bookmarks(FirefoxBackupImporterID) {
+		if err = DB.UpsertBookmark(bookmark); err != nil {
+			fmt.Fprintf(os.Stderr, "inserting bookmark %s: %s\n", bookmark.URL, err)
+			continue
+		} else {
+			bkCount++
+		}
+	}
+	fmt.Printf("imported %d bookmarks\n", bkCount)
+
+	return nil
+}
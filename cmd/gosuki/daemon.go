@@ -26,12 +26,14 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/blob42/gosuki/pkg/archive"
 	"github.com/blob42/gosuki/pkg/browsers"
 	"github.com/blob42/gosuki/pkg/config"
 	"github.com/blob42/gosuki/pkg/events"
 	"github.com/blob42/gosuki/pkg/modules"
 	"github.com/blob42/gosuki/pkg/profiles"
 	"github.com/blob42/gosuki/pkg/watch"
+	"github.com/blob42/gosuki/pkg/webhook"
 
 	"github.com/blob42/gosuki/pkg/manager"
 
@@ -50,13 +52,14 @@ var startDaemonCmd = &cli.Command{
 // creating a browser instance, applying profile configuration if provided, and
 // registering it with the manager for execution. It handles module setup,
 // profile management, and shutdown logic while ensuring proper error handling
-// and event broadcasting for TUI integration.
+// and event broadcasting for TUI integration. The started unit is returned so
+// it can be stopped when the profile goes away.
 func runBrowserModule(m *manager.Manager,
 	ctx context.Context,
 	cmd *cli.Command,
 	browserMod modules.BrowserModule,
 	pfl *profiles.Profile,
-	flav *browsers.BrowserDef) error {
+	flav *browsers.BrowserDef) (*browserUnit, error) {
 	var profileName string
 	mod := browserMod.ModInfo()
 
@@ -69,7 +72,7 @@ func runBrowserModule(m *manager.Manager,
 	//Create a browser instance
 	browser, ok := mod.New().(modules.BrowserModule)
 	if !ok {
-		return fmt.Errorf("module <%s> is not a BrowserModule", mod.ID)
+		return nil, fmt.Errorf("module <%s> is not a BrowserModule", mod.ID)
 	}
 	config := browser.Config()
 	log.Debugf("created browser instance <%s>", config.Name)
@@ -87,18 +90,18 @@ func runBrowserModule(m *manager.Manager,
 			err := fmt.Errorf("<%s> does not implement profiles.ProfileManager",
 				config.Name)
 			log.Error(err)
-			return err
+			return nil, err
 		}
 		if err := bpm.UseProfile(pfl, flav); err != nil {
 			log.Warnf("unable to load profile <%s.%s>: %s", mod.ID, pfl.Name, err)
-			return &modules.ErrModDisabled{Err: err}
+			return nil, &modules.ErrModDisabled{Err: err}
 		}
 		profileName = pfl.Name
 	}
 
 	runner, ok := browser.(watch.WatchRunner)
 	if !ok {
-		return errors.New("must implement watch.WatchRunner interface")
+		return nil, errors.New("must implement watch.WatchRunner interface")
 	}
 
 	go func() {
@@ -108,12 +111,12 @@ func runBrowserModule(m *manager.Manager,
 	// calls the setup logic for each browser instance
 	//PERF:
 	if err := modules.SetupBrowser(browser, modContext, pfl); err != nil {
-		return err
+		return nil, err
 	}
 
 	w := runner.Watch()
 	if w == nil {
-		return errors.New("must return a valid watch descriptor")
+		return nil, errors.New("must return a valid watch descriptor")
 	}
 	log.Debugf("adding watch runner <%s>", runner.Watch().ID)
 
@@ -127,9 +130,9 @@ func runBrowserModule(m *manager.Manager,
 		WatchRunner: runner,
 	}
 
-	m.AddUnit(worker, unitName)
+	wum := m.AddUnit(worker, unitName)
 
-	return nil
+	return &browserUnit{WorkUnitManager: wum, runner: runner}, nil
 }
 
 // bootstrapModules initializes and starts all available modules using the
@@ -145,6 +148,17 @@ func bootstrapModules(ctx context.Context, cmd *cli.Command, mngr *manager.Manag
 		go m.Start()
 	}(mngr)
 
+	// reload the marktab rules on change
+	watchMarktab(mngr)
+
+	// send the webhook events left in the outbox
+	if err := webhook.Start(ctx); err != nil {
+		log.Error("webhooks", "err", err)
+	}
+
+	// archive the bookmarks with the archive tag
+	archive.Start(ctx)
+
 	// Handle generic modules
 	mods := modules.GetModules()
 	for _, mod := range mods {
@@ -251,9 +265,11 @@ func bootstrapModules(ctx context.Context, cmd *cli.Command, mngr *manager.Manag
 						log.Info("no profiles found", "browser", flav.Flavour)
 						continue
 					}
+
+					started := map[*profiles.Profile]*browserUnit{}
 					for _, p := range profs {
 						log.Debug("", "flavour", flav.Flavour, "profile", p.Name)
-						err = runBrowserModule(mngr, ctx, cmd, browserMod, p, &flav)
+						unit, err := runBrowserModule(mngr, ctx, cmd, browserMod, p, &flav)
 						if err != nil {
 							if errDisabled, errDisable := err.(*modules.ErrModDisabled); errDisable {
 								log.Warn(
@@ -271,12 +287,16 @@ func bootstrapModules(ctx context.Context, cmd *cli.Command, mngr *manager.Manag
 							}
 							continue
 						}
+						started[p] = unit
 					}
+
+					// start/stop units when profiles are added or removed
+					watchProfiles(ctx, cmd, mngr, browserMod, bpm, flav, started)
 				}
 			} else {
 				log.Debugf("profile manager <%s> not watching all profiles",
 					browser.Config().Name)
-				err := runBrowserModule(mngr, ctx, cmd, browserMod, nil, nil)
+				_, err := runBrowserModule(mngr, ctx, cmd, browserMod, nil, nil)
 				if err != nil {
 					if _, errDisable := err.(*modules.ErrModDisabled); errDisable {
 						log.Warn("disabling browser", "mod", browserMod.ModInfo().ID)
@@ -290,7 +310,7 @@ func bootstrapModules(ctx context.Context, cmd *cli.Command, mngr *manager.Manag
 		} else {
 			log.Info("not implemented profiles.ProfileManager", "browser",
 				browser.Config().Name)
-			if err := runBrowserModule(mngr, ctx, cmd, browserMod, nil, nil); err != nil {
+			if _, err := runBrowserModule(mngr, ctx, cmd, browserMod, nil, nil); err != nil {
 				if _, errDisable := err.(*modules.ErrModDisabled); errDisable {
 					log.Warn("disabling browser", "mod", browserMod.ModInfo().ID)
 					modules.Disable(browserMod.ModInfo().ID)
@@ -44,6 +44,7 @@ func startDaemon(ctx context.Context, cmd *cli.Command) error {
 
 	// Initialize database and caches
 	db.Init(ctx, cmd)
+	db.StartRelatedIndex(ctx)
 
 	if cmd.Bool("tui") && isatty.IsTerminal(os.Stdout.Fd()) {
 		manager := initManager(true)
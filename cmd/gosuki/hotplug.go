@@ -0,0 +1,271 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki/pkg/browsers"
+	"github.com/blob42/gosuki/pkg/events"
+	"github.com/blob42/gosuki/pkg/manager"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/profiles"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+// Interval to wait for profile index changes to settle before rescanning
+const profileRescanInterval = 2 * time.Second
+
+// browserUnit is a browser module instance running as a manager work unit
+type browserUnit struct {
+	*manager.WorkUnitManager
+	runner watch.WatchRunner
+}
+
+// profileHotPlug watches the profile index files of a browser flavour. When
+// the index changes, units are started for new profiles and stopped for
+// removed ones.
+type profileHotPlug struct {
+	mngr    *manager.Manager
+	pm      profiles.ProfileManager
+	flavour browsers.BrowserDef
+
+	watcher    *watch.WatchDescriptor
+	indexFiles []string
+
+	// index directories watched for the files they contain
+	indexDirs map[string]bool
+
+	// running units by profile path
+	units map[string]*browserUnit
+	mu    sync.Mutex
+
+	// starts the unit of a new profile
+	start func(p *profiles.Profile) (*browserUnit, error)
+}
+
+// profile directory used to identify a profile across rescans. Profile IDs
+// are not stable, mozilla IDs are the profiles.ini section names.
+func profileUnitKey(p *profiles.Profile) string {
+	if p.IsRelative {
+		return filepath.Join(p.BaseDir, p.Path)
+	}
+	return filepath.Clean(p.Path)
+}
+
+func newProfileHotPlug(ctx context.Context,
+	cmd *cli.Command,
+	mngr *manager.Manager,
+	browserMod modules.BrowserModule,
+	pm profiles.ProfileManager,
+	flav browsers.BrowserDef,
+) (*profileHotPlug, error) {
+	indexer, ok := pm.(profiles.ProfileIndexer)
+	if !ok {
+		return nil, fmt.Errorf("<%s> does not implement profiles.ProfileIndexer",
+			browserMod.ModInfo().ID)
+	}
+
+	indexFiles, err := indexer.ProfileIndexFiles(flav.Flavour)
+	if err != nil {
+		return nil, err
+	}
+
+	// watch the parent directories, index files are usually replaced on write
+	watchedDirs := map[string]*watch.Watch{}
+	var watches []*watch.Watch
+	for _, file := range indexFiles {
+		dir := filepath.Dir(file)
+		w, exists := watchedDirs[dir]
+		if !exists {
+			w = &watch.Watch{
+				Path: dir,
+				EventTypes: []fsnotify.Op{
+					fsnotify.Write,
+					fsnotify.Create,
+				},
+			}
+			watchedDirs[dir] = w
+			watches = append(watches, w)
+		}
+		w.EventNames = append(w.EventNames, file)
+	}
+
+	name := fmt.Sprintf("profiles(%s)", flav.Flavour)
+	watcher, err := watch.NewWatcherWithReducer(name, modules.ReducerChanLen, watches...)
+	if err != nil {
+		return nil, err
+	}
+
+	hp := &profileHotPlug{
+		mngr:       mngr,
+		pm:         pm,
+		flavour:    flav,
+		watcher:    watcher,
+		indexFiles: indexFiles,
+		indexDirs:  map[string]bool{},
+		units:      map[string]*browserUnit{},
+	}
+	hp.start = func(p *profiles.Profile) (*browserUnit, error) {
+		return runBrowserModule(mngr, ctx, cmd, browserMod, p, &hp.flavour)
+	}
+	hp.watchIndexDirs()
+
+	return hp, nil
+}
+
+// watchIndexDirs watches the index directories that exist and are not
+// watched yet. Files created in them later trigger a rescan.
+func (hp *profileHotPlug) watchIndexDirs() {
+	for _, file := range hp.indexFiles {
+		if hp.indexDirs[file] {
+			continue
+		}
+		if info, err := os.Stat(file); err != nil || !info.IsDir() {
+			continue
+		}
+
+		err := hp.watcher.AddWatch(&watch.Watch{
+			Path: file,
+			EventTypes: []fsnotify.Op{
+				fsnotify.Write,
+				fsnotify.Create,
+			},
+			EventNames: []string{"*"},
+		})
+		if err != nil {
+			log.Warn("watching profile index", "dir", file, "err", err)
+			continue
+		}
+		hp.indexDirs[file] = true
+	}
+}
+
+// track registers a unit started for the profile `p`
+func (hp *profileHotPlug) track(p *profiles.Profile, unit *browserUnit) {
+	hp.mu.Lock()
+	defer hp.mu.Unlock()
+	hp.units[profileUnitKey(p)] = unit
+}
+
+// Watch implements watch.Watcher
+func (hp *profileHotPlug) Watch() *watch.WatchDescriptor {
+	return hp.watcher
+}
+
+// Run implements watch.Runner. It is called by the reducer when the profile
+// index files change.
+func (hp *profileHotPlug) Run() {
+	for _, unit := range hp.rescan() {
+		hp.mngr.StopUnit(unit.WorkUnitManager)
+
+		go func() {
+			events.TUIBus <- events.RunnerStopped{WatchRunner: unit.runner}
+		}()
+	}
+}
+
+// rescan starts units for new profiles and returns the units of removed
+// profiles, they are stopped by the caller without holding the lock.
+func (hp *profileHotPlug) rescan() []*browserUnit {
+	hp.mu.Lock()
+	defer hp.mu.Unlock()
+
+	hp.watchIndexDirs()
+
+	profs, err := hp.pm.GetProfiles(hp.flavour.Flavour)
+	if err != nil {
+		log.Warn("rescanning profiles", "flavour", hp.flavour.Flavour, "err", err)
+		return nil
+	}
+
+	current := map[string]bool{}
+	for _, p := range profs {
+		key := profileUnitKey(p)
+		current[key] = true
+		if _, running := hp.units[key]; running {
+			continue
+		}
+
+		log.Info("new profile detected", "flavour", hp.flavour.Flavour, "profile", p.Name)
+		unit, err := hp.start(p)
+		if err != nil {
+			// the profile might not be fully created yet, it will be retried
+			// on the next index change
+			log.Warn("starting profile", "flavour", hp.flavour.Flavour, "profile", p.Name, "err", err)
+			continue
+		}
+		hp.units[key] = unit
+	}
+
+	var removed []*browserUnit
+	for key, unit := range hp.units {
+		if current[key] {
+			continue
+		}
+
+		log.Info("profile removed", "flavour", hp.flavour.Flavour, "path", key)
+		removed = append(removed, unit)
+		delete(hp.units, key)
+	}
+
+	return removed
+}
+
+// watchProfiles starts a hot plug unit for the flavour `flav` if the profile
+// manager supports profile indexes. `started` are the units already running
+// for this flavour.
+func watchProfiles(ctx context.Context,
+	cmd *cli.Command,
+	mngr *manager.Manager,
+	browserMod modules.BrowserModule,
+	pm profiles.ProfileManager,
+	flav browsers.BrowserDef,
+	started map[*profiles.Profile]*browserUnit,
+) {
+	if _, ok := pm.(profiles.ProfileIndexer); !ok {
+		return
+	}
+
+	hp, err := newProfileHotPlug(ctx, cmd, mngr, browserMod, pm, flav)
+	if err != nil {
+		log.Warn("watching profiles", "flavour", flav.Flavour, "err", err)
+		return
+	}
+
+	for p, unit := range started {
+		hp.track(p, unit)
+	}
+
+	go watch.ReduceEvents(profileRescanInterval, hp)
+	mngr.AddUnit(watch.WatchWork{WatchRunner: hp}, hp.watcher.ID)
+}
+
+var _ watch.WatchRunner = (*profileHotPlug)(nil)
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blob42/gosuki/pkg/browsers"
+	"github.com/blob42/gosuki/pkg/manager"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+// stubProfiles lists a mutable set of profiles
+type stubProfiles struct {
+	mu    sync.Mutex
+	profs []*profiles.Profile
+}
+
+func (s *stubProfiles) set(names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profs = nil
+	for _, name := range names {
+		s.profs = append(s.profs, &profiles.Profile{
+			ID:   name,
+			Name: name,
+			Path: "/profiles/" + name,
+		})
+	}
+}
+
+func (s *stubProfiles) GetProfiles(string) ([]*profiles.Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*profiles.Profile{}, s.profs...), nil
+}
+
+func (s *stubProfiles) WatchAllProfiles() bool { return true }
+
+func (s *stubProfiles) UseProfile(*profiles.Profile, *profiles.BrowserDef) error { return nil }
+
+func (s *stubProfiles) GetProfile() *profiles.Profile { return nil }
+
+func (s *stubProfiles) ListFlavours() []profiles.BrowserDef { return nil }
+
+func (s *stubProfiles) GetCurFlavour() *profiles.BrowserDef { return nil }
+
+// profileUnit runs until it is stopped
+type profileUnit struct{}
+
+func (profileUnit) Run(um manager.UnitManager) {
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func newTestHotPlug(mngr *manager.Manager, pm profiles.ProfileManager) *profileHotPlug {
+	hp := &profileHotPlug{
+		mngr:      mngr,
+		pm:        pm,
+		flavour:   browsers.BrowserDef{Flavour: "test"},
+		indexDirs: map[string]bool{},
+		units:     map[string]*browserUnit{},
+	}
+	hp.start = func(p *profiles.Profile) (*browserUnit, error) {
+		return &browserUnit{
+			WorkUnitManager: mngr.AddUnit(profileUnit{}, p.Name),
+		}, nil
+	}
+	return hp
+}
+
+func TestProfileHotPlugRescan(t *testing.T) {
+	mngr := manager.NewManager()
+	pm := &stubProfiles{}
+	hp := newTestHotPlug(mngr, pm)
+
+	pm.set("default", "work")
+	hp.Run()
+	if len(hp.units) != 2 || len(mngr.Units()) != 2 {
+		t.Fatalf("expected 2 units, got %d tracked and %d running",
+			len(hp.units), len(mngr.Units()))
+	}
+
+	// a known profile is not started twice
+	pm.set("default", "work", "perso")
+	hp.Run()
+	if len(hp.units) != 3 || len(mngr.Units()) != 3 {
+		t.Fatalf("expected 3 units, got %d tracked and %d running",
+			len(hp.units), len(mngr.Units()))
+	}
+
+	pm.set("perso")
+	hp.Run()
+	if len(hp.units) != 1 || len(mngr.Units()) != 1 {
+		t.Fatalf("expected 1 unit, got %d tracked and %d running",
+			len(hp.units), len(mngr.Units()))
+	}
+	if _, ok := hp.units["/profiles/perso"]; !ok {
+		t.Errorf("expected the perso profile to keep running, got %v", hp.units)
+	}
+}
+
+func TestProfileHotPlugDuringShutdown(t *testing.T) {
+	mngr := manager.NewManager()
+	pm := &stubProfiles{}
+	hp := newTestHotPlug(mngr, pm)
+
+	pm.set("a", "b", "c", "d")
+	hp.Run()
+
+	go mngr.Start()
+
+	// add and remove profiles while the manager shuts down
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				pm.set("a", "e")
+			} else {
+				pm.set("b", "c", "f")
+			}
+			hp.Run()
+		}()
+	}
+	go mngr.Shutdown()
+
+	select {
+	case <-mngr.Quit:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not complete")
+	}
+	wg.Wait()
+}
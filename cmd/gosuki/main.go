@@ -145,6 +145,14 @@ func main() {
 		cmd.ProfileCmds,
 		cmd.ModuleCmds,
 		cmd.ImportCmds,
+		cmd.TabsCmds,
+		cmd.AutotagCmds,
+		cmd.TagsCmds,
+		cmd.BookmarkCmds,
+		cmd.NoteCmd,
+		cmd.MarktabCmds,
+		cmd.HooksCmds,
+		cmd.ArchiveCmds,
 		cmd.ExportCmds,
 		cmd.DebugInfoCmd,
 	}...)
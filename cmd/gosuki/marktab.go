@@ -0,0 +1,102 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/blob42/gosuki/pkg/manager"
+	"github.com/blob42/gosuki/pkg/marktab"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+// Interval to wait for marktab writes to settle before reloading it
+const marktabReloadInterval = time.Second
+
+// marktabWatcher reloads the marktab rules when the file changes
+type marktabWatcher struct {
+	watcher *watch.WatchDescriptor
+}
+
+// Watch implements watch.Watcher
+func (mw *marktabWatcher) Watch() *watch.WatchDescriptor {
+	return mw.watcher
+}
+
+// Run implements watch.Runner. The previous rules are kept when the new
+// marktab is invalid.
+func (mw *marktabWatcher) Run() {
+	if err := marktab.Reload(); err != nil {
+		log.Error("invalid marktab, keeping previous rules", "err", err)
+		return
+	}
+	log.Info("reloaded marktab", "rules", len(marktab.Rules()))
+}
+
+// watchMarktab loads the marktab rules and starts a unit reloading them on
+// change. The parent directory is watched as editors usually replace the file
+// on write.
+func watchMarktab(mngr *manager.Manager) {
+	if err := marktab.PreloadRules(); err != nil {
+		log.Error("loading marktab", "err", err)
+	}
+
+	path, err := marktab.Path()
+	if err != nil {
+		log.Warn("watching marktab", "err", err)
+		return
+	}
+
+	dir := filepath.Dir(path)
+	if _, err = os.Stat(dir); err != nil {
+		log.Debug("not watching marktab", "err", err)
+		return
+	}
+
+	watcher, err := watch.NewWatcherWithReducer("marktab", modules.ReducerChanLen,
+		&watch.Watch{
+			Path: dir,
+			EventTypes: []fsnotify.Op{
+				fsnotify.Write,
+				fsnotify.Create,
+				fsnotify.Remove,
+				fsnotify.Rename,
+			},
+			EventNames: []string{path},
+		},
+	)
+	if err != nil {
+		log.Warn("watching marktab", "err", err)
+		return
+	}
+
+	mw := &marktabWatcher{watcher: watcher}
+	go watch.ReduceEvents(marktabReloadInterval, mw)
+	mngr.AddUnit(watch.WatchWork{WatchRunner: mw}, watcher.ID)
+}
+
+var _ watch.WatchRunner = (*marktabWatcher)(nil)
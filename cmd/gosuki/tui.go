@@ -359,6 +359,36 @@ func setupModProgress(m tuiModel, r watch.WatchRunner) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// removeModProgress stops tracking a browser instance and updates the browser
+// progress without it
+func removeModProgress(m tuiModel, r watch.WatchRunner) (tea.Model, tea.Cmd) {
+	br, ok := r.(modules.BrowserModule)
+	if !ok {
+		return m, nil
+	}
+
+	b, exists := m.browsers[string(br.ModInfo().ID)]
+	if !exists {
+		return m, nil
+	}
+
+	b.instances = slices.DeleteFunc(b.instances, func(inst modules.BrowserModule) bool {
+		return inst == br
+	})
+	delete(b.profileStates, br)
+
+	b.curCount, b.totalCount = 0, 0
+	for _, p := range b.profileStates {
+		b.curCount += p.curCount
+		b.totalCount += p.totalCount
+	}
+
+	if b.totalCount == 0 {
+		return m, b.progress.SetPercent(0)
+	}
+	return m, b.progress.SetPercent(float64(b.curCount) / float64(b.totalCount))
+}
+
 // Update implements tea.Model.
 func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -420,6 +450,10 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case events.RunnerStarted:
 		return setupModProgress(m, msg.WatchRunner)
 
+	// Module instance stopped (removed profile)
+	case events.RunnerStopped:
+		return removeModProgress(m, msg.WatchRunner)
+
 	case events.StartedLoadingMsg:
 		_, isBr := m.browsers[string(msg.ID)]
 
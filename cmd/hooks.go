@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki/hooks"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+var HooksCmds = &cli.Command{
+	Name:  "hooks",
+	Usage: "bookmark hooks commands",
+	Description: `Browsers run the hooks listed in the hooks option of their config section
+and the daemon runs the hooks listed in [hooks] global on the bookmarks written
+to the database. Hook files from ~/.config/gosuki/hooks are listed as well.`,
+	Commands: []*cli.Command{
+		listHooksCmd,
+	},
+}
+
+var listHooksCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list the defined hooks and the modules and profiles using them",
+	Action: func(_ context.Context, _ *cli.Command) error {
+		usedBy := hookUsers()
+
+		names := make([]string, 0, len(hooks.Defined))
+		for name := range hooks.Defined {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		fmt.Printf("%-24s %-24s %-8s %-8s %s\n", "NAME", "KIND", "PRIORITY", "TARGET", "USED BY")
+		for _, name := range names {
+			hook := hooks.Defined[name]
+			kind := hook.Kind().String()
+			if _, ok := hooks.FileHook(name); ok {
+				kind += " (file)"
+			}
+
+			users := usedBy[name]
+			if len(users) == 0 {
+				users = []string{"-"}
+			}
+			fmt.Printf("%-24s %-24s %-8d %-8s %s\n", name, kind, hook.Priority(),
+				hooks.Target(hook), strings.Join(users, ", "))
+		}
+		return nil
+	},
+}
+
+// hookUsers maps hook names to the modules using them, `global` for the hooks
+// run on the database. Browsers watching all their profiles are listed per
+// profile with the hooks of the profile instances.
+func hookUsers() map[string][]string {
+	users := map[string][]string{}
+	use := func(user string, names []string) {
+		for _, name := range names {
+			users[name] = append(users[name], user)
+		}
+	}
+
+	for _, mod := range modules.GetBrowserModules() {
+		id := string(mod.ModInfo().ID)
+		browser, ok := mod.ModInfo().New().(modules.BrowserModule)
+		if !ok {
+			continue
+		}
+
+		pm, isProfileManager := browser.(profiles.ProfileManager)
+		pc, isProfileConfigurer := browser.(modules.ProfileConfigurer)
+		if !isProfileManager || !isProfileConfigurer ||
+			!(pm.WatchAllProfiles() || config.GlobalConfig.WatchAll) {
+			use(id, browser.Config().UseHooks)
+			continue
+		}
+
+		for _, flav := range pm.ListFlavours() {
+			profs, err := pm.GetProfiles(flav.Flavour)
+			if err != nil {
+				continue
+			}
+			for _, p := range profs {
+				use(fmt.Sprintf("%s(%s)", flav.Flavour, p.Name), pc.ProfileConfig().UseHooks)
+			}
+		}
+	}
+
+	for name := range hooks.Defined {
+		if hooks.IsGlobal(name) {
+			users[name] = append(users[name], "global")
+		}
+	}
+	return users
+}
@@ -34,5 +34,7 @@ Use the specific import subcommands to perform migrations.`,
 	Commands: []*cli.Command{
 		importBukuDBCmd,
 		importPocketCmd,
+		importJSONCmd,
+		importFirefoxBackupCmd,
 	},
 }
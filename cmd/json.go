@@ -0,0 +1,106 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/export"
+)
+
+const (
+	JSONImporterID = "json-import"
+)
+
+var importJSONCmd = &cli.Command{
+	Name:  "json",
+	Usage: "Import bookmarks from a JSON export (Pinboard format)",
+	Description: `Import bookmarks from a JSON file in the Pinboard format, as written by
+'gosuki export json', Pinboard or Wallabag.
+
+The reading state is taken from the 'toread' field or from the 'state' and
+'favorite' fields of gosuki exports.`,
+	Action:    importFromJSON,
+	ArgsUsage: "path/to/export.json",
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name:      "path",
+			UsageText: "Path to the JSON export file",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+	},
+}
+
+func importFromJSON(ctx context.Context, c *cli.Command) error {
+	path := c.StringArg("path")
+	if path == "" {
+		return errors.New("missing path to json file")
+	}
+	expandedPath, err := utils.ExpandPath(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(expandedPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fmt.Printf("importing from %s\n", path)
+
+	bookmarks, err := export.ParseJSON(file)
+	if err != nil {
+		return fmt.Errorf("failed to read JSON: %w", err)
+	}
+
+	db.Init(ctx, c)
+	defer db.DiskDB.Close()
+
+	var bkCount int
+	for _, bookmark := range bookmarks {
+		bookmark.Module = JSONImporterID
+		if err = db.DiskDB.UpsertBookmark(bookmark); err != nil {
+			fmt.Fprintf(os.Stderr, "inserting bookmark %s: %s\n", bookmark.URL, err)
+			continue
+		}
+		if bookmark.Notes != "" {
+			if _, err = db.SetNote(bookmark.URL, bookmark.Notes); err != nil {
+				fmt.Fprintf(os.Stderr, "saving notes of %s: %s\n", bookmark.URL, err)
+			}
+		}
+		bkCount++
+	}
+	fmt.Printf("imported %d bookmarks\n", bkCount)
+
+	return nil
+}
@@ -0,0 +1,301 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/marktab"
+)
+
+var MarktabCmds = &cli.Command{
+	Name:  "marktab",
+	Usage: "marktab rules commands",
+	Commands: []*cli.Command{
+		marktabCheckCmd,
+		marktabTestCmd,
+		marktabRunsCmd,
+	},
+}
+
+// marktabPath returns the expanded path argument or the default marktab path
+func marktabPath(path string) (string, error) {
+	if path == "" {
+		return marktab.Path()
+	}
+	return utils.ExpandOnly(path)
+}
+
+var marktabCheckCmd = &cli.Command{
+	Name:  "check",
+	Usage: "validate a marktab file",
+	Description: `Parse the marktab file and report every invalid line with its line number.
+The default file is ~/.config/gosuki/marktab.`,
+	ArgsUsage: "[path]",
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name:      "path",
+			UsageText: "path to the marktab file",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		path, err := marktabPath(cmd.StringArg("path"))
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		mt, err := marktab.Parse(file)
+		var errs marktab.ParseErrors
+		if errors.As(err, &errs) {
+			for _, e := range errs {
+				fmt.Fprintf(os.Stderr, "%s:%d: %s\n\t%s\n", path, e.Line, e.Err, e.Text)
+			}
+			return fmt.Errorf("%d invalid line(s) in %s", len(errs), path)
+		} else if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s: %d valid rule(s)\n", path, len(mt.Rules))
+		return nil
+	},
+}
+
+var marktabTestCmd = &cli.Command{
+	Name:  "test",
+	Usage: "show which marktab rules would fire for a bookmark",
+	Description: `Evaluate every marktab rule against a bookmark given with --url, --title and
+--tags, or read from the database with --from-db. The matching rules are shown
+with their command and GOSUKI_* environment. Nothing is executed unless --exec
+is used.
+
+--exec executes the command of the matching rule at the given line for real,
+with its side effects. It is not sandboxed: the command can use the network
+and the file system. It runs with no input in an empty temporary directory that
+is also its HOME, PATH is reset to /usr/local/bin:/usr/bin:/bin,
+GOSUKI_TEST_RUN=1 is set and it is killed after --timeout.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "url",
+			Usage: "bookmark URL",
+		},
+		&cli.StringFlag{
+			Name:  "title",
+			Usage: "bookmark title",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tags",
+			Usage: "comma separated bookmark tags",
+		},
+		&cli.StringFlag{
+			Name:  "from-db",
+			Usage: "read the bookmark with this `URL` from the database",
+		},
+		&cli.StringFlag{
+			Name:  "file",
+			Usage: "marktab file, defaults to ~/.config/gosuki/marktab",
+		},
+		&cli.IntFlag{
+			Name:  "exec",
+			Usage: "execute the command of the rule at `LINE`, for real",
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "time limit of --exec",
+			Value: marktab.TestRunTimeout,
+		},
+	},
+	Action: testMarktab,
+}
+
+func testMarktab(ctx context.Context, cmd *cli.Command) error {
+	var bookmark *gosuki.Bookmark
+	if url := cmd.String("from-db"); url != "" {
+		db.Init(ctx, cmd)
+		defer db.DiskDB.Close()
+
+		var err error
+		if bookmark, err = db.GetBookmarkByURL(ctx, url); err != nil {
+			return fmt.Errorf("bookmark %s: %w", url, err)
+		}
+	} else {
+		if cmd.String("url") == "" {
+			return errors.New("missing --url or --from-db")
+		}
+		bookmark = &gosuki.Bookmark{
+			URL:   cmd.String("url"),
+			Title: cmd.String("title"),
+		}
+		for _, tag := range cmd.StringSlice("tags") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				bookmark.Tags = append(bookmark.Tags, tag)
+			}
+		}
+	}
+
+	path, err := marktabPath(cmd.String("file"))
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	mt, err := marktab.Parse(file)
+	var errs marktab.ParseErrors
+	if errors.As(err, &errs) {
+		fmt.Fprintf(os.Stderr, "skipping %d invalid line(s), see: gosuki marktab check\n", len(errs))
+	} else if err != nil {
+		return err
+	}
+
+	var run *marktab.Evaluation
+	evals := marktab.Evaluate(mt.Rules, bookmark)
+	for i, ev := range evals {
+		if len(ev.Conds) > 0 {
+			ev.Pattern = strings.Join(append(ev.Conds, ev.Pattern), " ")
+		}
+		if !ev.Matched {
+			fmt.Printf("line %d\t%s\t%s\tno match\n", ev.Line, ev.Trigger, ev.Pattern)
+			continue
+		}
+
+		kind := "MATCH"
+		if ev.Filter {
+			kind = "MATCH (filter)"
+		}
+		fmt.Printf("line %d\t%s\t%s\t%s\n", ev.Line, ev.Trigger, ev.Pattern, kind)
+		fmt.Printf("  command: %s\n", ev.Command)
+		for _, env := range ev.Env {
+			fmt.Printf("  %s\n", env)
+		}
+		if ev.Line == cmd.Int("exec") {
+			run = &evals[i]
+		}
+	}
+
+	if line := cmd.Int("exec"); line != 0 {
+		if run == nil {
+			return fmt.Errorf("no matching rule at line %d", line)
+		}
+
+		fmt.Printf("\nexecuting line %d:\n", line)
+		out, err := run.Rule.TestRun(ctx, bookmark, cmd.Duration("timeout"))
+		os.Stdout.Write(out)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return nil
+}
+
+var marktabRunsCmd = &cli.Command{
+	Name:  "runs",
+	Usage: "list the recent runs of marktab commands",
+	Description: `Marktab commands run in a background queue of the daemon. Every run is
+recorded with its status, exit code and the tail of its output.`,
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "number of runs to show",
+			Value: 20,
+		},
+		&cli.StringFlag{
+			Name:  "status",
+			Usage: "only show runs with `STATUS`: queued, running, ok, failed, timeout or dropped",
+		},
+		&cli.StringFlag{
+			Name:  "show",
+			Usage: "show the output of the run with `ID`",
+		},
+	},
+	Action: listMarktabRuns,
+}
+
+func listMarktabRuns(ctx context.Context, cmd *cli.Command) error {
+	db.Init(ctx, cmd)
+	defer db.DiskDB.Close()
+
+	if id := cmd.String("show"); id != "" {
+		run, err := db.GetMarktabRun(ctx, id)
+		if err != nil {
+			return fmt.Errorf("run %s: %w", id, err)
+		}
+
+		fmt.Printf("id:       %s\n", run.ID)
+		fmt.Printf("rule:     line %d %s %s\n", run.Line, run.Trigger, run.Pattern)
+		fmt.Printf("command:  %s\n", run.Command)
+		fmt.Printf("url:      %s\n", run.URL)
+		fmt.Printf("status:   %s (exit %d, %d attempt(s))\n", run.Status, run.ExitCode, run.Attempts)
+		fmt.Printf("started:  %s\n", time.Unix(run.Started, 0).Format(time.DateTime))
+		fmt.Printf("duration: %s\n", time.Duration(run.Duration)*time.Millisecond)
+		if run.Error != "" {
+			fmt.Printf("error:    %s\n", run.Error)
+		}
+		if run.Stdout != "" {
+			fmt.Printf("\nstdout:\n%s\n", run.Stdout)
+		}
+		if run.Stderr != "" {
+			fmt.Printf("\nstderr:\n%s\n", run.Stderr)
+		}
+		return nil
+	}
+
+	result, err := db.QueryMarktabRuns(ctx, cmd.String("status"), "",
+		&db.PaginationParams{Page: 1, Size: int(cmd.Int("limit"))})
+	if err != nil {
+		return err
+	}
+
+	for _, run := range result.Runs {
+		fmt.Printf("%s  %s  %-8s exit %-3d line %-3d %s\n",
+			run.ID,
+			time.Unix(run.Started, 0).Format(time.DateTime),
+			run.Status,
+			run.ExitCode,
+			run.Line,
+			run.URL,
+		)
+	}
+	return nil
+}
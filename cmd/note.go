@@ -0,0 +1,124 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+var NoteCmd = &cli.Command{
+	Name:  "note",
+	Usage: "edit the Markdown notes of a bookmark",
+	Description: `Open the notes of a bookmark in $VISUAL or $EDITOR. Notes are kept apart
+from the bookmark data and are never overwritten by browser syncs. Saving an
+empty file removes the notes.`,
+	ArgsUsage: "url",
+	Arguments: []cli.Argument{bookmarkURLArg},
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "print",
+			Aliases: []string{"p"},
+			Usage:   "print the notes instead of editing them",
+		},
+	},
+	Action: editNote,
+}
+
+func editor() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := os.Getenv(env); e != "" {
+			return e
+		}
+	}
+	return "vi"
+}
+
+func editNote(ctx context.Context, cmd *cli.Command) error {
+	url := cmd.StringArg("url")
+	if url == "" {
+		return errors.New("missing bookmark url")
+	}
+
+	db.Init(ctx, cmd)
+	defer db.DiskDB.Close()
+
+	if _, err := db.GetBookmarkByURL(ctx, url); err != nil {
+		return fmt.Errorf("bookmark %s: %w", url, err)
+	}
+
+	note, err := db.GetNote(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("print") {
+		fmt.Print(note.Text)
+		return nil
+	}
+
+	tmp, err := os.CreateTemp("", "gosuki-note-*.md")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.WriteString(note.Text); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	args := append(strings.Fields(editor()), tmp.Name())
+	edit := exec.CommandContext(ctx, args[0], args[1:]...)
+	edit.Stdin, edit.Stdout, edit.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err = edit.Run(); err != nil {
+		return fmt.Errorf("running editor: %w", err)
+	}
+
+	text, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return err
+	}
+
+	if string(text) == note.Text {
+		fmt.Println("notes unchanged")
+		return nil
+	}
+
+	if _, err = db.SetNote(url, string(text)); err != nil {
+		return err
+	}
+	fmt.Printf("saved notes of %s\n", url)
+
+	return nil
+}
@@ -68,8 +68,43 @@ maintaining the original metadata including tags and read/unread status.`,
 
 // CSV structure for Pocket import is:
 //
-// title,url,time_added,tags,status
-// Opération Bobcat 1942-1946 - Tahiti Heritage,https://www.tahitiheritage.pf/operation-bobcat-1942-1946/,1689230329,history|military|polynesie|usa,unread
+// title,url,time_added,cursor,tags,status
+// Opération Bobcat 1942-1946 - Tahiti Heritage,https://www.tahitiheritage.pf/operation-bobcat-1942-1946/,1689230329,,history|military|polynesie|usa,unread
+//
+// Columns are looked up by name in the header, older exports have no `cursor`
+// column.
+var pocketColumns = map[string]int{
+	"title":      0,
+	"url":        1,
+	"time_added": 2,
+	"tags":       4,
+	"status":     5,
+}
+
+func pocketHeader(header []string) map[string]int {
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := pocketColumns[name]; ok {
+			columns[name] = i
+		}
+	}
+
+	if _, ok := columns["url"]; !ok {
+		return pocketColumns
+	}
+	return columns
+}
+
+// pocketField returns the named column of the row or an empty string
+func pocketField(row []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
 func importFromPocketCSV(ctx context.Context, c *cli.Command) error {
 	path := c.StringArg("path")
 	if c.StringArg("path") == "" {
@@ -106,32 +141,44 @@ func importFromPocketCSV(ctx context.Context, c *cli.Command) error {
 	defer db.DiskDB.Close()
 
 	var bkCount int
+	var columns map[string]int
 	for i, row := range records {
-		// skip header
 		if i == 0 {
+			columns = pocketHeader(row)
 			continue
 		}
 
-		if len(row) < 5 {
+		url := pocketField(row, columns, "url")
+		if url == "" {
 			continue
 		}
 
-		title := row[0]
-		url := row[1]
-		timeAdded := row[2]
-		tags := row[4]
-
-		modified, err := strconv.ParseUint(string(timeAdded), 10, 64)
+		modified, err := strconv.ParseUint(pocketField(row, columns, "time_added"), 10, 64)
 		if err != nil {
-			panic(err)
+			fmt.Fprintf(os.Stderr, "invalid time_added for %s: %s\n", url, err)
+		}
+
+		var tags []string
+		for tag := range strings.SplitSeq(pocketField(row, columns, "tags"), "|") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+
+		// Pocket items are either unread or archived
+		state := gosuki.StateUnread
+		if pocketField(row, columns, "status") == "archive" {
+			state = gosuki.StateArchived
 		}
 
 		bookmark := &gosuki.Bookmark{
 			URL:      url,
-			Title:    title,
-			Tags:     strings.Split(tags, "|"),
+			Title:    pocketField(row, columns, "title"),
+			Tags:     tags,
 			Module:   PocketImporterID,
 			Modified: modified,
+			// merged into the stored flags, favorites are kept
+			Flags: gosuki.Flags(0).WithState(state),
 		}
 
 		if err = DB.UpsertBookmark(bookmark); err != nil {
@@ -97,6 +97,9 @@ var listProfilesCmd = &cli.Command{
 						fmt.Printf("    %s\n", cyan(p.Name))
 						fmt.Printf("      ID: %s\n", p.ID)
 						fmt.Printf("      Path: %s\n", pPath)
+						if p.Group != "" {
+							fmt.Printf("      Group: %s\n", p.Group)
+						}
 					}
 				}
 				println()
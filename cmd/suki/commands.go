@@ -67,6 +67,40 @@ var TagSearchCmd = &cli.Command{
 	},
 }
 
+var HistoryCmd = &cli.Command{
+	Name:      "history",
+	Aliases:   []string{"h"},
+	Usage:     "search the browsing history",
+	UsageText: "suki history <term> - searches the URL and title of visited pages",
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if !cmd.Args().Present() {
+			return errors.New("missing search term")
+		}
+		return searchHistory(ctx, cmd, strings.Join(cmd.Args().Slice(), " "))
+	},
+}
+
+var RelatedCmd = &cli.Command{
+	Name:      "related",
+	Aliases:   []string{"r"},
+	Usage:     "show the bookmarks similar to a bookmark",
+	UsageText: "suki related <url> - lists the bookmarks closest to <url> across all browsers",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:    "limit",
+			Aliases: []string{"n"},
+			Value:   api.DefaultRelatedLimit,
+			Usage:   "show at most `N` bookmarks",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		if !cmd.Args().Present() {
+			return errors.New("missing bookmark url")
+		}
+		return relatedBookmarks(ctx, cmd, cmd.Args().First())
+	},
+}
+
 func formatMark(format string) (string, error) {
 	outFormat := strings.Clone(format)
 
@@ -82,6 +116,9 @@ func formatMark(format string) (string, error) {
 	// description
 	outFormat = strings.ReplaceAll(outFormat, "%d", `{{.Desc}}`)
 
+	// reading state
+	outFormat = strings.ReplaceAll(outFormat, "%S", `{{.Flags.State}}`)
+
 	r := strings.NewReplacer(`\t`, "\t", `\n`, "\n")
 	outFormat = r.Replace(outFormat)
 
@@ -116,12 +153,29 @@ func formatPrint(_ context.Context, cmd *cli.Command, marks []*gosuki.Bookmark)
 	return nil
 }
 
+// flagsFilter reads the --state and --favorite filters
+func flagsFilter(cmd *cli.Command) (db.FlagsFilter, error) {
+	state, err := gosuki.ParseReadState(cmd.String("state"))
+	return db.FlagsFilter{State: state, Favorite: cmd.Bool("favorite")}, err
+}
+
 func listBookmarks(ctx context.Context, cmd *cli.Command) error {
 	pageParms := db.PaginationParams{
 		Page: 1,
 		Size: -1,
 	}
-	result, err := db.ListBookmarks(ctx, &pageParms)
+
+	filter, err := flagsFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	var result *db.QueryResult
+	if filter.IsZero() {
+		result, err = db.ListBookmarks(ctx, &pageParms)
+	} else {
+		result, err = db.QueryBookmarksByFlags(ctx, "", nil, db.TagAnd, false, filter, &pageParms)
+	}
 	if err != nil {
 		return err
 	}
@@ -145,7 +199,22 @@ func searchBookmarks(ctx context.Context, cmd *cli.Command, opts searchOpts, key
 		Size: -1,
 	}
 
-	if len(query.Tags) > 0 {
+	filter, err := flagsFilter(cmd)
+	if err != nil {
+		return err
+	}
+
+	if !filter.IsZero() {
+		result, err = db.QueryBookmarksByFlags(
+			ctx,
+			query.TextQuery,
+			query.Tags,
+			query.TagCond,
+			opts.fuzzy,
+			filter,
+			&pageParms,
+		)
+	} else if len(query.Tags) > 0 {
 		result, err = db.QueryBookmarksByTags(
 			ctx,
 			query.TextQuery,
@@ -169,3 +238,49 @@ func searchBookmarks(ctx context.Context, cmd *cli.Command, opts searchOpts, key
 
 	return formatPrint(ctx, cmd, result.Bookmarks)
 }
+
+func searchHistory(ctx context.Context, cmd *cli.Command, query string) error {
+	pageParms := db.PaginationParams{
+		Page: 1,
+		Size: -1,
+	}
+
+	result, err := db.QueryHistory(ctx, query, &pageParms)
+	if err != nil {
+		return err
+	}
+
+	var marks []*gosuki.Bookmark
+	for _, entry := range result.Entries {
+		marks = append(marks, &gosuki.Bookmark{
+			URL:    entry.URL,
+			Title:  entry.Title,
+			Module: entry.Module,
+		})
+	}
+
+	return formatPrint(ctx, cmd, marks)
+}
+
+func relatedBookmarks(ctx context.Context, cmd *cli.Command, url string) error {
+	if _, err := db.GetBookmarkByURL(ctx, url); err != nil {
+		return fmt.Errorf("bookmark not found: %s", url)
+	}
+
+	idx, err := db.LoadRelatedIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	related, err := api.FindRelated(ctx, idx, url, int(cmd.Int("limit")))
+	if err != nil {
+		return err
+	}
+
+	var marks []*gosuki.Bookmark
+	for _, r := range related {
+		marks = append(marks, r.Bookmark)
+	}
+
+	return formatPrint(ctx, cmd, marks)
+}
@@ -48,6 +48,7 @@ OUTPUT FORMATTING:
    %u - URL
    %t - Title
    %d - Description
+   %S - Reading state (none, unread, read, archived)
 
 You can combine these placeholders to create a custom output format. For example: "--format "%T, %u: %t"
 
@@ -69,6 +69,14 @@ Usage examples:
 			Usage:   "Format output using a custom template",
 			Aliases: []string{"f"},
 		},
+		&cli.StringFlag{
+			Name:  "state",
+			Usage: "only show bookmarks with reading `STATE` (unread, read, archived)",
+		},
+		&cli.BoolFlag{
+			Name:  "favorite",
+			Usage: "only show favorite bookmarks",
+		},
 	}
 	app.Flags = append(app.Flags, cmd.MainFlags...)
 
@@ -92,6 +100,8 @@ Usage examples:
 	app.Commands = []*cli.Command{
 		FuzzySearchCmd,
 		TagSearchCmd,
+		HistoryCmd,
+		RelatedCmd,
 	}
 
 	app.ExitErrHandler = func(ctx context.Context, cli *cli.Command, err error) {
@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v3"
+
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/tabs"
+)
+
+var tabsModuleFlag = &cli.StringFlag{
+	Name:    "module",
+	Aliases: []string{"m"},
+	Usage:   "only consider tabs of modules starting with `NAME` (ex. firefox_work)",
+}
+
+var TabsCmds = &cli.Command{
+	Name:  "tabs",
+	Usage: "open tabs commands",
+	Description: `Open tabs are read from the session files of the detected browser
+profiles (Firefox recovery.jsonlz4, Chromium SNSS sessions). They are kept
+apart from bookmarks until a snapshot is taken.`,
+	Commands: []*cli.Command{
+		listTabsCmd,
+		snapshotTabsCmd,
+	},
+}
+
+var listTabsCmd = &cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "list open tabs of all profiles",
+	Flags:   []cli.Flag{tabsModuleFlag},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		blue := color.New(color.FgBlue).SprintFunc()
+		cyan := color.New(color.FgCyan).SprintFunc()
+
+		var curModule string
+		for _, t := range filterTabs(tabs.Collect(), cmd.String("module")) {
+			if t.Module != curModule {
+				curModule = t.Module
+				fmt.Printf(" %s %s\n", blue(""), curModule)
+			}
+
+			pinned := ""
+			if t.Pinned {
+				pinned = " (pinned)"
+			}
+			fmt.Printf("    %s%s\n      %s\n", cyan(t.Title), pinned, t.URL)
+		}
+
+		return nil
+	},
+}
+
+var snapshotTabsCmd = &cli.Command{
+	Name:  "snapshot",
+	Usage: "save the open tabs as tagged bookmarks",
+	Description: `Save the currently open tabs as bookmarks tagged with the given tags.
+Tabs that cannot be bookmarked (ex. about:newtab) are skipped.`,
+	Flags: []cli.Flag{
+		tabsModuleFlag,
+		&cli.StringSliceFlag{
+			Name:    "tag",
+			Aliases: []string{"t"},
+			Usage:   "tag the saved bookmarks with `TAG` (can be repeated)",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		var tags []string
+		for _, tag := range cmd.StringSlice("tag") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+
+		bookmarks := tabs.AsBookmarks(filterTabs(tabs.Collect(), cmd.String("module")), tags)
+		if len(bookmarks) == 0 {
+			return errors.New("no open tabs found")
+		}
+
+		db.Init(ctx, cmd)
+		defer db.DiskDB.Close()
+
+		var bkCount int
+		for _, bookmark := range bookmarks {
+			if err := db.DiskDB.UpsertBookmark(bookmark); err != nil {
+				fmt.Fprintf(os.Stderr, "inserting bookmark %s: %s\n", bookmark.URL, err)
+				continue
+			}
+			bkCount++
+		}
+		fmt.Printf("saved %d tabs\n", bkCount)
+
+		return nil
+	},
+}
+
+func filterTabs(list []*tabs.Tab, module string) []*tabs.Tab {
+	if module == "" {
+		return list
+	}
+
+	var result []*tabs.Tab
+	for _, t := range list {
+		if strings.HasPrefix(t.Module, module) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/suggest"
+)
+
+var TagsCmds = &cli.Command{
+	Name:  "tags",
+	Usage: "tag management commands",
+	Commands: []*cli.Command{
+		suggestTagsCmd,
+	},
+}
+
+var suggestTagsCmd = &cli.Command{
+	Name:  "suggest",
+	Usage: "suggest tags for untagged bookmarks",
+	Description: `Tags are suggested by a local model trained on the bookmarks that are
+already tagged. It compares the words of the title, description and URL and the
+domain of bookmarks. Nothing leaves the machine.
+
+Without argument suggestions are made for all the untagged bookmarks, a URL
+can be given to get suggestions for a single bookmark.`,
+	ArgsUsage: "[url]",
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name: "url",
+			Config: cli.StringConfig{
+				TrimSpace: true,
+			},
+		},
+	},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:    "limit",
+			Aliases: []string{"n"},
+			Value:   3,
+			Usage:   "suggest at most `N` tags per bookmark",
+		},
+		&cli.FloatFlag{
+			Name:  "min-score",
+			Value: suggest.DefaultMinScore,
+			Usage: "drop suggestions with a similarity lower than `SCORE` (0-1)",
+		},
+		&cli.BoolFlag{
+			Name:  "apply",
+			Usage: "add the suggested tags to the bookmarks",
+		},
+	},
+	Action: func(ctx context.Context, cmd *cli.Command) error {
+		db.Init(ctx, cmd)
+		defer db.DiskDB.Close()
+
+		result, err := db.ListBookmarks(ctx, &db.PaginationParams{Page: 1, Size: -1})
+		if err != nil {
+			return err
+		}
+
+		rejections, err := db.TagRejections(ctx)
+		if err != nil {
+			return err
+		}
+
+		model := suggest.Train(result.Bookmarks)
+		model.MinScore = cmd.Float("min-score")
+		for url, tags := range rejections {
+			model.Reject(url, tags...)
+		}
+		if model.Tags() == 0 {
+			return errors.New("not enough tagged bookmarks to learn from")
+		}
+
+		var targets []*gosuki.Bookmark
+		url := cmd.StringArg("url")
+		for _, bk := range result.Bookmarks {
+			if url != "" && bk.URL == url || url == "" && len(bk.Tags) == 0 {
+				targets = append(targets, bk)
+			}
+		}
+		if url != "" && len(targets) == 0 {
+			return fmt.Errorf("bookmark not found: %s", url)
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		green := color.New(color.FgGreen).SprintFunc()
+
+		var count int
+		for _, bk := range targets {
+			suggestions := model.Suggest(bk, int(cmd.Int("limit")))
+			if len(suggestions) == 0 {
+				continue
+			}
+
+			var tags, display []string
+			for _, s := range suggestions {
+				tags = append(tags, s.Tag)
+				display = append(display, fmt.Sprintf("%s (%.2f)", green(s.Tag), s.Score))
+			}
+			fmt.Printf("%s\n  %s\n    %s\n", cyan(bk.Title), bk.URL, strings.Join(display, " "))
+
+			if cmd.Bool("apply") {
+				bk.Tags = append(bk.Tags, tags...)
+				if err = db.DiskDB.UpdateBookmark(bk); err != nil {
+					fmt.Fprintf(os.Stderr, "updating bookmark %s: %s\n", bk.URL, err)
+					continue
+				}
+			}
+			count++
+		}
+
+		if cmd.Bool("apply") {
+			fmt.Printf("tagged %d bookmarks\n", count)
+		}
+
+		return nil
+	},
+}
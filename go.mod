@@ -28,8 +28,11 @@ require (
 	github.com/swithek/dotsqlx v1.0.0
 	github.com/urfave/cli/v3 v3.3.8
 	github.com/xlab/treeprint v1.0.0
+	go.starlark.net v0.0.0-20260210143700-b62fd896b91b
+	golang.org/x/net v0.45.0
 	golang.org/x/sys v0.37.0
 	golang.org/x/time v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -65,11 +68,9 @@ require (
 	github.com/yusufpapurcu/wmi v1.2.4 // indirect
 	golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
 	golang.org/x/mod v0.28.0 // indirect
-	golang.org/x/net v0.45.0 // indirect
 	golang.org/x/sync v0.17.0 // indirect
 	golang.org/x/text v0.30.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
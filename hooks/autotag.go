@@ -0,0 +1,83 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package hooks
+
+import (
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/autotag"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+// autotagHook applies the rules of the [autotag] config section. It runs after
+// the tags are parsed from the title so rules can match them.
+func autotagHook(item any) error {
+	engine := autotag.DefaultEngine()
+	if len(engine.Rules) == 0 {
+		return nil
+	}
+
+	switch v := item.(type) {
+	case *tree.Node:
+		bk := &gosuki.Bookmark{
+			URL:    v.URL,
+			Title:  v.Title,
+			Tags:   v.Tags,
+			Desc:   v.Desc,
+			Module: v.Module,
+		}
+		if len(engine.Apply(bk)) > 0 {
+			v.Tags = bk.Tags
+			v.Desc = bk.Desc
+		}
+	case *gosuki.Bookmark:
+		engine.Apply(v)
+	}
+
+	return nil
+}
+
+func NodeAutotag(n *tree.Node) error {
+	return autotagHook(n)
+}
+
+func BkAutotag(b *gosuki.Bookmark) error {
+	return autotagHook(b)
+}
+
+func init() {
+	registerHook(
+		Hook[*tree.Node]{
+			name:     "node_autotag",
+			Func:     NodeAutotag,
+			priority: 3,
+			kind:     BrowserHook,
+		},
+	)
+	registerHook(
+		Hook[*gosuki.Bookmark]{
+			name:     "bk_autotag",
+			Func:     BkAutotag,
+			priority: 3,
+			kind:     BrowserHook,
+		},
+	)
+}
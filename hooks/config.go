@@ -0,0 +1,179 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+// HooksConfig selects the hooks run on bookmarks written to the database.
+// Browsers select their hooks with the `hooks` option of their own section.
+type HooksConfig struct {
+	// Hooks run when bookmarks are inserted, updated or deleted in the
+	// database. Hook files with an insert, update or delete kind always run.
+	Global []string `toml:"global" mapstructure:"global"`
+}
+
+// DefaultGlobal are the global hooks enabled by default
+var DefaultGlobal = []string{
+	"bk_marktab",
+	"bk_webhook_insert",
+	"bk_webhook_update",
+	"bk_webhook_delete",
+	"bk_archive",
+}
+
+var Config = &HooksConfig{
+	Global: slices.Clone(DefaultGlobal),
+}
+
+// GlobalKinds are the kinds of hooks run by the hooks scheduler
+const GlobalKinds = GlobalInsertHook | GlobalUpdateHook | GlobalDeleteHook
+
+// Check returns an error for every name that is not a hook of this kind
+// running on T. The error suggests the hooks that could be used instead.
+func Check[T Hookable](names []string, kind Kind) error {
+	var errs []error
+	for _, name := range names {
+		if err := check[T](name, kind); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func check[T Hookable](name string, kind Kind) error {
+	hook, ok := Defined[name]
+	if !ok {
+		return fmt.Errorf("hook <%s> not defined%s", name, suggest[T](name, kind))
+	}
+	if _, ok := hook.(Hook[T]); !ok {
+		return fmt.Errorf("hook <%s> does not run on %s%s", name, target[T](), suggest[T](name, kind))
+	}
+	if hook.Kind()&kind == 0 {
+		return fmt.Errorf("hook <%s> is a %s hook, expected %s%s", name, hook.Kind(), kind, suggest[T](name, kind))
+	}
+	return nil
+}
+
+// suggest returns the same hook for T if it exists or the list of usable hooks
+func suggest[T Hookable](name string, kind Kind) string {
+	var usable []string
+	for n, h := range Defined {
+		if _, ok := h.(Hook[T]); ok && h.Kind()&kind != 0 {
+			usable = append(usable, n)
+		}
+	}
+	slices.Sort(usable)
+
+	base := strings.TrimPrefix(strings.TrimPrefix(name, "bk_"), "node_")
+	for _, n := range usable {
+		if n == "bk_"+base || n == "node_"+base {
+			return fmt.Sprintf(", did you mean <%s> ?", n)
+		}
+	}
+	if len(usable) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", available %s hooks: %s", kind, strings.Join(usable, ", "))
+}
+
+func target[T Hookable]() string {
+	var zero T
+	switch any(zero).(type) {
+	case *tree.Node:
+		return "nodes"
+	case *gosuki.Bookmark:
+		return "bookmarks"
+	}
+	return "?"
+}
+
+// Target returns what the hook runs on: `node` or `bookmark`
+func Target(h NamedHook) string {
+	switch h.(type) {
+	case Hook[*tree.Node]:
+		return "node"
+	case Hook[*gosuki.Bookmark]:
+		return "bookmark"
+	}
+	return "?"
+}
+
+// IsGlobal returns true if the hook runs on the bookmarks written to the
+// database
+func IsGlobal(name string) bool {
+	hook, ok := Defined[name]
+	if !ok || hook.Kind()&GlobalKinds == 0 {
+		return false
+	}
+	if _, isFile := FileHook(name); isFile {
+		return true
+	}
+	return slices.Contains(Config.Global, name)
+}
+
+// globalHooks returns the enabled global hooks of this kind by priority
+func globalHooks(kind Kind) []Hook[*gosuki.Bookmark] {
+	var result []Hook[*gosuki.Bookmark]
+	for name, hook := range Defined {
+		bkHook, ok := hook.(Hook[*gosuki.Bookmark])
+		if !ok || hook.Kind()&kind == 0 || !IsGlobal(name) {
+			continue
+		}
+		result = append(result, bkHook)
+	}
+	slices.SortStableFunc(result, func(a, b Hook[*gosuki.Bookmark]) int {
+		if a.priority != b.priority {
+			return int(a.priority) - int(b.priority)
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	return result
+}
+
+func init() {
+	config.RegisterConfigurator("hooks", config.AsConfigurator(Config))
+
+	// hook files are loaded first so they can be used by name
+	config.RegisterConfReadyHooks(func(context.Context, *cli.Command) error {
+		if err := LoadHookFiles(HookFilesDir); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Error(err)
+		}
+
+		if err := Check[*gosuki.Bookmark](Config.Global, GlobalKinds); err != nil {
+			return fmt.Errorf("[hooks] global: %w", err)
+		}
+		return nil
+	})
+}
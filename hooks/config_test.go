@@ -0,0 +1,51 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, Check[*tree.Node]([]string{"node_tags_from_name", "node_autotag"}, BrowserHook))
+	assert.NoError(t, Check[*gosuki.Bookmark](DefaultGlobal, GlobalKinds))
+
+	err := Check[*tree.Node]([]string{"bk_autotag"}, BrowserHook)
+	assert.ErrorContains(t, err, "hook <bk_autotag> does not run on nodes, did you mean <node_autotag> ?")
+
+	err = Check[*tree.Node]([]string{"node_autotg"}, BrowserHook)
+	assert.ErrorContains(t, err, "hook <node_autotg> not defined, available browser hooks:")
+	assert.ErrorContains(t, err, "node_autotag")
+
+	err = Check[*gosuki.Bookmark]([]string{"bk_tags_from_name"}, GlobalKinds)
+	assert.ErrorContains(t, err, "hook <bk_tags_from_name> is a browser hook, expected insert,update,delete")
+
+	// all the invalid names are reported
+	err = Check[*tree.Node]([]string{"foo", "node_autotag", "bar"}, BrowserHook)
+	assert.ErrorContains(t, err, "<foo>")
+	assert.ErrorContains(t, err, "<bar>")
+}
+
+func TestGlobalHooks(t *testing.T) {
+	saved := Config.Global
+	t.Cleanup(func() { Config.Global = saved })
+
+	names := func(kind Kind) []string {
+		var result []string
+		for _, h := range globalHooks(kind) {
+			result = append(result, h.Name())
+		}
+		return result
+	}
+
+	// sorted by priority
+	assert.Equal(t, []string{"bk_marktab", "bk_webhook_insert", "bk_archive"}, names(GlobalInsertHook))
+	assert.Equal(t, []string{"bk_webhook_delete"}, names(GlobalDeleteHook))
+
+	Config.Global = []string{"bk_webhook_update"}
+	assert.Empty(t, names(GlobalInsertHook))
+	assert.Equal(t, []string{"bk_webhook_update"}, names(GlobalUpdateHook))
+}
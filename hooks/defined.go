@@ -34,6 +34,7 @@ type HookMap map[string]NamedHook
 type NamedHook interface {
 	Name() string
 	Kind() Kind
+	Priority() uint
 }
 
 var Defined = HookMap{
@@ -30,7 +30,10 @@ package hooks
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
+	"strings"
+	"sync/atomic"
 
 	"github.com/blob42/gosuki"
 	"github.com/blob42/gosuki/pkg/logging"
@@ -67,8 +70,26 @@ const (
 
 	// triggered when bookmarks are updated in the main database
 	GlobalUpdateHook
+
+	// triggered when bookmarks are deleted from the main database
+	GlobalDeleteHook
 )
 
+// String returns the names of the kinds as used in hook files, ex.
+// `browser,insert`
+func (k Kind) String() string {
+	var names []string
+	for _, name := range []string{"browser", "insert", "update", "delete"} {
+		if k&fileHookKinds[name] != 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ",")
+}
+
 // A Hook is a function that takes a Hookable type (*Bookmark or *Node) and
 // performs an arbitrary process. Hooks are executed during bookmark loading or
 // real-time detection of changes.
@@ -102,6 +123,10 @@ func (h Hook[T]) Kind() Kind {
 	return h.kind
 }
 
+func (h Hook[T]) Priority() uint {
+	return h.priority
+}
+
 // SortByPriority sorts a slice of NamedHook by priority, with higher priority
 // (lower uint value) first. This uses reflection to access the priority field
 // of each hook.
@@ -136,16 +161,37 @@ type HookRunner interface {
 	CallHooks(any) error
 }
 
+// BookmarkWriter saves the changes global hooks made from `orig` to `changed`
+type BookmarkWriter func(orig, changed *gosuki.Bookmark) error
+
+var bookmarkWriter atomic.Pointer[BookmarkWriter]
+
+// SetBookmarkWriter sets where bookmarks changed by global hooks are saved
+func SetBookmarkWriter(w BookmarkWriter) {
+	bookmarkWriter.Store(&w)
+}
+
 func processGlobalHooks(hj HookJob) error {
-	for name, hook := range Defined {
-		if bkHook, ok := hook.(Hook[*gosuki.Bookmark]); ok {
-			if (hook.Kind() & hj.Kind) != 0 {
-				if err := bkHook.Func(hj.Book); err != nil {
-					return fmt.Errorf("hook %s error :%w", name, err)
-				}
+	orig := *hj.Book
+	orig.Tags = slices.Clone(hj.Book.Tags)
 
-			}
+	for _, hook := range globalHooks(hj.Kind) {
+		if err := hook.Func(hj.Book); err != nil {
+			return fmt.Errorf("hook %s error :%w", hook.name, err)
+		}
+	}
 
+	// deleted bookmarks are gone, only insert and update changes are saved
+	writer := bookmarkWriter.Load()
+	if hj.Kind == GlobalDeleteHook || writer == nil || *writer == nil {
+		return nil
+	}
+	changed := orig.Title != hj.Book.Title ||
+		orig.Desc != hj.Book.Desc ||
+		!slices.Equal(orig.Tags, hj.Book.Tags)
+	if changed {
+		if err := (*writer)(&orig, hj.Book); err != nil {
+			return fmt.Errorf("saving hooked bookmark %s: %w", hj.Book.URL, err)
 		}
 	}
 	return nil
@@ -119,3 +119,42 @@ func TestHookPriority(t *testing.T) {
 	SortByPriority(Hooks)
 	assert.Equal(t, "SetDefaultModule", Hooks[0].Name())
 }
+
+func TestGlobalHookWriter(t *testing.T) {
+	savedGlobal, savedWriter := Config.Global, bookmarkWriter.Load()
+	Defined["bk_test_writer"] = Hook[*gosuki.Bookmark]{
+		name: "bk_test_writer",
+		Func: func(bk *gosuki.Bookmark) error {
+			if strings.Contains(bk.URL, "github.com") {
+				bk.Tags = append(bk.Tags, "dev")
+			}
+			return nil
+		},
+		kind: GlobalInsertHook | GlobalUpdateHook | GlobalDeleteHook,
+	}
+	Config.Global = []string{"bk_test_writer"}
+	t.Cleanup(func() {
+		Config.Global = savedGlobal
+		bookmarkWriter.Store(savedWriter)
+		delete(Defined, "bk_test_writer")
+	})
+
+	var written []string
+	SetBookmarkWriter(func(orig, changed *gosuki.Bookmark) error {
+		assert.Equal(t, []string{"go"}, orig.Tags)
+		assert.Equal(t, []string{"go", "dev"}, changed.Tags)
+		written = append(written, changed.URL)
+		return nil
+	})
+
+	for _, hj := range []HookJob{
+		{Book: &gosuki.Bookmark{URL: "https://github.com/golang/go", Tags: []string{"go"}}, Kind: GlobalInsertHook},
+		{Book: &gosuki.Bookmark{URL: "https://go.dev", Tags: []string{"go"}}, Kind: GlobalUpdateHook},
+		{Book: &gosuki.Bookmark{URL: "https://github.com/blob42/gosuki", Tags: []string{"go"}}, Kind: GlobalDeleteHook},
+	} {
+		assert.NoError(t, processGlobalHooks(hj))
+	}
+
+	// unchanged and deleted bookmarks are not written
+	assert.Equal(t, []string{"https://github.com/golang/go"}, written)
+}
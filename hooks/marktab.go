@@ -23,14 +23,9 @@
 package hooks
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
 	"strings"
-	"time"
 
 	"github.com/blob42/gosuki"
-	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/pkg/marktab"
 	"github.com/blob42/gosuki/pkg/tree"
 )
@@ -52,14 +47,16 @@ type Rule struct {
 	empty bool // empty is an unexported field indicating whether the rule is empty.
 }
 
-// When a rule matches this bookmark, execute the action in the rule.Command
-// field. A new shell subprocess is spawned.
+// When a rule matches this bookmark, the rule.Command is queued for
+// execution in a new shell subprocess, see [marktab.Enqueue].
 //
 // The child process receives the following exported fields:
-// - $URL
-// - $TITLE
-// - $TAGS
-// - $MODULE
+// - $GOSUKI_URL
+// - $GOSUKI_TITLE
+// - $GOSUKI_TAGS
+// - $GOSUKI_MODULE
+// - $GOSUKI_RUN_ID
+// - $GOSUKI_ATTEMPT
 func marktabHook(item any) error {
 	err := marktab.PreloadRules()
 	if err != nil {
@@ -84,23 +81,17 @@ func marktabHook(item any) error {
 }
 
 func processMtabHook(bk *gosuki.Bookmark) error {
-	var tags []string
-	for _, t := range bk.Tags {
-		if t[0] == '@' {
+	tags := marktab.Tags(bk)
+	for _, rule := range marktab.Rules() {
+		// filters are applied by the database before the sync to disk
+		if rule.Filter {
 			continue
 		}
-		tags = append(tags, t)
-	}
-	for _, rule := range marktab.CachedRules.Rules {
-
-		// Spawn a new shell subprocess with the rule's command, passing in
-		// the bookmark details.
 		if rule.Match(bk) {
-
-			runID := utils.GenStringID(4)
+			runID := marktab.Enqueue(rule, bk)
 
 			log.Debug(
-				"run marktab",
+				"queued marktab",
 				"id",
 				runID,
 				"rule",
@@ -110,22 +101,6 @@ func processMtabHook(bk *gosuki.Bookmark) error {
 				"tags",
 				strings.Join(tags, ","),
 			)
-			cmd := exec.Command("sh", "-c", rule.Command)
-			cmd.Env = append(
-				os.Environ(),
-				"GOSUKI_URL="+bk.URL,
-				"GOSUKI_TITLE="+bk.Title,
-				"GOSUKI_TAGS="+strings.Join(tags, ","),
-				"GOSUKI_MODULE="+bk.Module,
-				"GOSUKI_RUN_ID="+runID,
-			)
-			if err := cmd.Start(); err != nil {
-				return fmt.Errorf("failed to start cmd: %w", err)
-			}
-			cmd.WaitDelay = time.Second * 60
-			if err := cmd.Wait(); err != nil {
-				return fmt.Errorf("failed to wait cmd: %w", err)
-			}
 		}
 	}
 
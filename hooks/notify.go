@@ -87,11 +87,13 @@ func init() {
 			name:     "node_notify_send",
 			Func:     NodeNotifySend,
 			priority: 20,
+			kind:     BrowserHook,
 		})
 	registerHook(
 		Hook[*gosuki.Bookmark]{
 			name:     "bk_notify_send",
 			Func:     BkNotifySend,
 			priority: 20,
+			kind:     BrowserHook,
 		})
 }
@@ -0,0 +1,429 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+// HookFilesDir holds the user defined hook scripts
+const HookFilesDir = "~/.config/gosuki/hooks"
+
+// Default priority of hook scripts, between the builtin tag parsing hooks and
+// the notification hooks
+const DefaultFilePriority = 10
+
+// MaxScriptSteps limits the Starlark computation steps of loading a script
+// and of each hook call
+const MaxScriptSteps = 1_000_000
+
+// HookFile is a user defined hook written in [Starlark] and loaded from a
+// `*.star` file in [HookFilesDir]. The script declares the kinds and priority
+// of the hook and a `hook` function called with the bookmark:
+//
+//	# ~/.config/gosuki/hooks/github.star
+//	kind = ["browser", "insert"]
+//	priority = 5
+//
+//	def hook(bk):
+//	    m = re.match(r"github\.com/([^/]+)/", bk.url)
+//	    if m:
+//	        bk.add_tag("dev")
+//	        bk.add_tag("gh:" + m[1])
+//
+// The bookmark has the read only `url` and `module` attributes, `title` and
+// `desc` can be assigned, `tags` is a copy of the tags changed with
+// `add_tag`, `remove_tag` and checked with `has_tag`. The `re` (`match`,
+// `sub`) and `url` (`parse`) modules are predeclared.
+//
+// Scripts have no file system or network access and cannot load other files.
+// Each call is limited to [MaxScriptSteps].
+//
+// The file registers the hooks `bk_<name>` and `node_<name>`, where name is
+// the file name without extension, which browsers can use by name.
+//
+// [Starlark]: https://github.com/bazelbuild/starlark/blob/master/spec.md
+type HookFile struct {
+	Kind     Kind
+	Priority uint
+
+	// Path of the file
+	Path string
+
+	hook *starlark.Function
+}
+
+var fileHookKinds = map[string]Kind{
+	"browser": BrowserHook,
+	"insert":  GlobalInsertHook,
+	"update":  GlobalUpdateHook,
+	"delete":  GlobalDeleteHook,
+}
+
+// FileHooks are the hook files loaded by name
+var FileHooks = map[string]*HookFile{}
+
+func newThread(name string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			log.Info(msg, "hook", name)
+		},
+	}
+	thread.SetMaxExecutionSteps(MaxScriptSteps)
+	return thread
+}
+
+// ParseHookFile runs the script and validates its declarations
+func ParseHookFile(path string) (*HookFile, error) {
+	name := strings.TrimSuffix(filepath.Base(path), ".star")
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{},
+		newThread(name), path, nil, scriptModules)
+	if err != nil {
+		return nil, err
+	}
+
+	hf := &HookFile{Path: path, Priority: DefaultFilePriority}
+
+	kinds, ok := globals["kind"].(*starlark.List)
+	if !ok || kinds.Len() == 0 {
+		return nil, errors.New("kind must be a non empty list")
+	}
+	for v := range kinds.Elements() {
+		s, _ := starlark.AsString(v)
+		bit, ok := fileHookKinds[s]
+		if !ok {
+			return nil, fmt.Errorf("unknown kind %s", v)
+		}
+		hf.Kind |= bit
+	}
+
+	if v, ok := globals["priority"]; ok {
+		var priority int
+		if err := starlark.AsInt(v, &priority); err != nil || priority < 0 {
+			return nil, fmt.Errorf("invalid priority %s", v)
+		}
+		hf.Priority = uint(priority)
+	}
+
+	hf.hook, ok = globals["hook"].(*starlark.Function)
+	if !ok || hf.hook.NumParams() != 1 {
+		return nil, errors.New("missing hook(bk) function")
+	}
+
+	// the hook is called concurrently, scripts cannot keep state
+	globals.Freeze()
+
+	return hf, nil
+}
+
+// Run calls the hook function of the script on the bookmark
+func (hf *HookFile) Run(name string, bk *gosuki.Bookmark) error {
+	_, err := starlark.Call(newThread(name), hf.hook,
+		starlark.Tuple{&scriptBookmark{bk: bk}}, nil)
+	return err
+}
+
+// LoadHookFiles registers the hooks of the `*.star` files in dir. Invalid
+// files and names taken by builtin hooks are reported and skipped.
+func LoadHookFiles(dir string) error {
+	dir, err := utils.ExpandOnly(dir)
+	if err != nil {
+		return err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.star"))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".star")
+		if err := loadHookFile(name, path); err != nil {
+			errs = append(errs, fmt.Errorf("hook file %s: %w", path, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func loadHookFile(name, path string) error {
+	bkName, nodeName := "bk_"+name, "node_"+name
+	if _, loaded := FileHooks[name]; !loaded {
+		for _, n := range []string{bkName, nodeName} {
+			if _, taken := Defined[n]; taken {
+				return fmt.Errorf("hook <%s> already defined", n)
+			}
+		}
+	}
+
+	hf, err := ParseHookFile(path)
+	if err != nil {
+		return err
+	}
+
+	registerHook(Hook[*gosuki.Bookmark]{
+		name:     bkName,
+		Func:     func(bk *gosuki.Bookmark) error { return hf.Run(bkName, bk) },
+		priority: hf.Priority,
+		kind:     hf.Kind,
+	})
+	registerHook(Hook[*tree.Node]{
+		name: nodeName,
+		Func: func(n *tree.Node) error {
+			bk := &gosuki.Bookmark{
+				URL:    n.URL,
+				Title:  n.Title,
+				Tags:   n.Tags,
+				Desc:   n.Desc,
+				Module: n.Module,
+			}
+			if err := hf.Run(nodeName, bk); err != nil {
+				return err
+			}
+			n.Title, n.Tags, n.Desc = bk.Title, bk.Tags, bk.Desc
+			return nil
+		},
+		priority: hf.Priority,
+		kind:     hf.Kind,
+	})
+	FileHooks[name] = hf
+
+	log.Debug("loaded hook file", "name", name, "kind", hf.Kind, "priority", hf.Priority)
+	return nil
+}
+
+// FileHook returns the hook file defining the hook with this name
+func FileHook(name string) (*HookFile, bool) {
+	for _, prefix := range []string{"bk_", "node_"} {
+		if base, ok := strings.CutPrefix(name, prefix); ok {
+			hf, found := FileHooks[base]
+			return hf, found
+		}
+	}
+	return nil, false
+}
+
+// scriptBookmark is the bookmark passed to hook scripts
+type scriptBookmark struct {
+	bk *gosuki.Bookmark
+}
+
+var _ starlark.HasSetField = (*scriptBookmark)(nil)
+
+func (b *scriptBookmark) String() string        { return fmt.Sprintf("bookmark(%q)", b.bk.URL) }
+func (b *scriptBookmark) Type() string          { return "bookmark" }
+func (b *scriptBookmark) Freeze()               {}
+func (b *scriptBookmark) Truth() starlark.Bool  { return starlark.True }
+func (b *scriptBookmark) Hash() (uint32, error) { return 0, errors.New("unhashable: bookmark") }
+
+var bookmarkMethods = map[string]func(bk *gosuki.Bookmark, tag string) starlark.Value{
+	"add_tag": func(bk *gosuki.Bookmark, tag string) starlark.Value {
+		if tag != "" && !slices.Contains(bk.Tags, tag) {
+			bk.Tags = append(bk.Tags, tag)
+		}
+		return starlark.None
+	},
+	"remove_tag": func(bk *gosuki.Bookmark, tag string) starlark.Value {
+		bk.Tags = slices.DeleteFunc(bk.Tags, func(t string) bool { return t == tag })
+		return starlark.None
+	},
+	"has_tag": func(bk *gosuki.Bookmark, tag string) starlark.Value {
+		return starlark.Bool(slices.Contains(bk.Tags, tag))
+	},
+}
+
+func (b *scriptBookmark) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "url":
+		return starlark.String(b.bk.URL), nil
+	case "title":
+		return starlark.String(b.bk.Title), nil
+	case "desc":
+		return starlark.String(b.bk.Desc), nil
+	case "module":
+		return starlark.String(b.bk.Module), nil
+	case "tags":
+		tags := make([]starlark.Value, 0, len(b.bk.Tags))
+		for _, tag := range b.bk.Tags {
+			tags = append(tags, starlark.String(tag))
+		}
+		return starlark.NewList(tags), nil
+	}
+
+	method, ok := bookmarkMethods[name]
+	if !ok {
+		return nil, nil
+	}
+	return starlark.NewBuiltin(name, func(
+		_ *starlark.Thread,
+		fn *starlark.Builtin,
+		args starlark.Tuple,
+		kwargs []starlark.Tuple,
+	) (starlark.Value, error) {
+		var tag string
+		if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &tag); err != nil {
+			return nil, err
+		}
+		return method(b.bk, tag), nil
+	}), nil
+}
+
+func (b *scriptBookmark) AttrNames() []string {
+	names := []string{"url", "title", "desc", "module", "tags"}
+	for name := range bookmarkMethods {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (b *scriptBookmark) SetField(name string, val starlark.Value) error {
+	s, ok := starlark.AsString(val)
+	if !ok {
+		return fmt.Errorf("bookmark.%s must be a string, got %s", name, val.Type())
+	}
+
+	switch name {
+	case "title":
+		b.bk.Title = s
+	case "desc":
+		b.bk.Desc = s
+	default:
+		return fmt.Errorf("bookmark.%s cannot be assigned", name)
+	}
+	return nil
+}
+
+var (
+	regexps   = map[string]*regexp.Regexp{}
+	regexpsMu sync.Mutex
+)
+
+// compile caches the regexps of scripts, they usually use a few constant
+// patterns
+func compile(pattern string) (*regexp.Regexp, error) {
+	regexpsMu.Lock()
+	defer regexpsMu.Unlock()
+
+	if re, ok := regexps[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(regexps) > 1000 {
+		clear(regexps)
+	}
+	regexps[pattern] = re
+	return re, nil
+}
+
+// reMatch returns the groups of the first match of the pattern, or None
+func reMatch(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var pattern, s string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 2, &pattern, &s); err != nil {
+		return nil, err
+	}
+	re, err := compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := re.FindStringSubmatch(s)
+	if groups == nil {
+		return starlark.None, nil
+	}
+	values := make([]starlark.Value, 0, len(groups))
+	for _, g := range groups {
+		values = append(values, starlark.String(g))
+	}
+	return starlark.NewList(values), nil
+}
+
+// reSub replaces the matches of the pattern, `$1` expands to the groups
+func reSub(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var pattern, repl, s string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 3, &pattern, &repl, &s); err != nil {
+		return nil, err
+	}
+	re, err := compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return starlark.String(re.ReplaceAllString(s, repl)), nil
+}
+
+// urlParse returns the parts of an url
+func urlParse(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var s string
+	if err := starlark.UnpackPositionalArgs(fn.Name(), args, kwargs, 1, &s); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"scheme":   starlark.String(u.Scheme),
+		"host":     starlark.String(u.Hostname()),
+		"port":     starlark.String(u.Port()),
+		"path":     starlark.String(u.Path),
+		"query":    starlark.String(u.RawQuery),
+		"fragment": starlark.String(u.Fragment),
+	}), nil
+}
+
+// scriptModules are predeclared in hook scripts
+var scriptModules = starlark.StringDict{
+	"re": &starlarkstruct.Module{
+		Name: "re",
+		Members: starlark.StringDict{
+			"match": starlark.NewBuiltin("match", reMatch),
+			"sub":   starlark.NewBuiltin("sub", reSub),
+		},
+	},
+	"url": &starlarkstruct.Module{
+		Name: "url",
+		Members: starlark.StringDict{
+			"parse": starlark.NewBuiltin("parse", urlParse),
+		},
+	},
+}
@@ -0,0 +1,179 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+func writeHookFile(t *testing.T, dir, name, text string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0o600))
+}
+
+func TestLoadHookFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeHookFile(t, dir, "github.star", `
+kind = ["browser", "insert"]
+priority = 4
+
+def hook(bk):
+    m = re.match(r"github\.com/([^/]+)/", bk.url)
+    if m:
+        bk.add_tag("dev")
+        bk.add_tag("gh:" + m[1])
+    bk.remove_tag("todo")
+    if url.parse(bk.url).host == "github.com" and not bk.has_tag("todo"):
+        bk.title = re.sub(r"^GitHub - ", "", bk.title)
+`)
+	writeHookFile(t, dir, "badkind.star", `
+kind = ["sometimes"]
+def hook(bk):
+    pass
+`)
+	writeHookFile(t, dir, "nohook.star", `
+kind = ["browser"]
+`)
+	writeHookFile(t, dir, "load.star", `
+load("other.star", "x")
+kind = ["browser"]
+def hook(bk):
+    pass
+`)
+	// taken by the builtin autotag hooks
+	writeHookFile(t, dir, "autotag.star", `
+kind = ["browser"]
+def hook(bk):
+    pass
+`)
+	t.Cleanup(func() {
+		for name := range FileHooks {
+			delete(Defined, "bk_"+name)
+			delete(Defined, "node_"+name)
+			delete(FileHooks, name)
+		}
+	})
+
+	err := LoadHookFiles(dir)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "badkind.star: unknown kind")
+	assert.ErrorContains(t, err, "nohook.star: missing hook(bk) function")
+	assert.ErrorContains(t, err, "load.star:")
+	assert.ErrorContains(t, err, "autotag.star: hook <bk_autotag> already defined")
+
+	require.Contains(t, FileHooks, "github")
+	assert.NotContains(t, FileHooks, "badkind")
+
+	hook, ok := Defined["bk_github"].(Hook[*gosuki.Bookmark])
+	require.True(t, ok)
+	assert.Equal(t, Kind(BrowserHook|GlobalInsertHook), hook.Kind())
+	assert.Equal(t, uint(4), hook.priority)
+
+	bk := &gosuki.Bookmark{
+		URL:   "https://github.com/blob42/gosuki",
+		Title: "GitHub - blob42/gosuki",
+		Tags:  []string{"todo"},
+	}
+	require.NoError(t, hook.Func(bk))
+	assert.Equal(t, []string{"dev", "gh:blob42"}, bk.Tags)
+	assert.Equal(t, "blob42/gosuki", bk.Title)
+
+	nodeHook, ok := Defined["node_github"].(Hook[*tree.Node])
+	require.True(t, ok)
+	node := &tree.Node{URL: "https://github.com/golang/go", Title: "go"}
+	require.NoError(t, nodeHook.Func(node))
+	assert.Equal(t, []string{"dev", "gh:golang"}, node.Tags)
+}
+
+func TestHookFileLimits(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("steps", func(t *testing.T) {
+		writeHookFile(t, dir, "loop.star", `
+kind = ["browser"]
+def hook(bk):
+    n = 0
+    for i in range(100000000):
+        n += i
+`)
+		hf, err := ParseHookFile(filepath.Join(dir, "loop.star"))
+		require.NoError(t, err)
+		err = hf.Run("bk_loop", &gosuki.Bookmark{})
+		assert.ErrorContains(t, err, "too many steps")
+	})
+
+	t.Run("read only fields", func(t *testing.T) {
+		writeHookFile(t, dir, "url.star", `
+kind = ["browser"]
+def hook(bk):
+    bk.url = "https://example.com"
+`)
+		hf, err := ParseHookFile(filepath.Join(dir, "url.star"))
+		require.NoError(t, err)
+		bk := &gosuki.Bookmark{URL: "https://go.dev"}
+		assert.ErrorContains(t, hf.Run("bk_url", bk), "cannot be assigned")
+		assert.Equal(t, "https://go.dev", bk.URL)
+	})
+
+	t.Run("frozen globals", func(t *testing.T) {
+		writeHookFile(t, dir, "state.star", `
+kind = ["browser"]
+seen = []
+def hook(bk):
+    seen.append(bk.url)
+`)
+		hf, err := ParseHookFile(filepath.Join(dir, "state.star"))
+		require.NoError(t, err)
+		assert.ErrorContains(t, hf.Run("bk_state", &gosuki.Bookmark{}), "frozen")
+	})
+
+	t.Run("no file access", func(t *testing.T) {
+		writeHookFile(t, dir, "open.star", `
+kind = ["browser"]
+def hook(bk):
+    open("/etc/passwd")
+`)
+		_, err := ParseHookFile(filepath.Join(dir, "open.star"))
+		assert.ErrorContains(t, err, "undefined: open")
+	})
+}
+
+func TestHookFileParallel(t *testing.T) {
+	dir := t.TempDir()
+	writeHookFile(t, dir, "dev.star", `
+kind = ["browser", "insert"]
+prefixes = {"github.com": "gh", "gitlab.com": "gl"}
+
+def hook(bk):
+    host = url.parse(bk.url).host
+    if host in prefixes:
+        bk.add_tag(prefixes[host])
+        bk.title = re.sub(r"^\w+ - ", "", bk.title)
+`)
+	hf, err := ParseHookFile(filepath.Join(dir, "dev.star"))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bk := &gosuki.Bookmark{
+				URL:   fmt.Sprintf("https://github.com/user/repo%d", i),
+				Title: "GitHub - repo",
+			}
+			assert.NoError(t, hf.Run("bk_dev", bk))
+			assert.Equal(t, []string{"gh"}, bk.Tags)
+			assert.Equal(t, "repo", bk.Title)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,59 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package hooks
+
+import (
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/webhook"
+)
+
+// webhookHook posts the bookmark event to the endpoints of the [webhooks]
+// config section. Events go through the on disk outbox so a slow or
+// unreachable endpoint does not hold the other hooks.
+func webhookHook(event string) func(*gosuki.Bookmark) error {
+	return func(bk *gosuki.Bookmark) error {
+		return webhook.Send(event, bk)
+	}
+}
+
+func init() {
+	registerHook(
+		Hook[*gosuki.Bookmark]{
+			name:     "bk_webhook_insert",
+			Func:     webhookHook(webhook.EventInsert),
+			priority: 30,
+			kind:     GlobalInsertHook,
+		},
+		Hook[*gosuki.Bookmark]{
+			name:     "bk_webhook_update",
+			Func:     webhookHook(webhook.EventUpdate),
+			priority: 30,
+			kind:     GlobalUpdateHook,
+		},
+		Hook[*gosuki.Bookmark]{
+			name:     "bk_webhook_delete",
+			Func:     webhookHook(webhook.EventDelete),
+			priority: 30,
+			kind:     GlobalDeleteHook,
+		},
+	)
+}
@@ -110,8 +110,29 @@ func GetBookmarks(r *http.Request) ([]*gosuki.Bookmark, uint, error) {
 		searchByTag   = tag != ""
 	)
 
-	// Search with query AND tags
-	if searchByQuery && searchByTag {
+	filter, err := GetFlagsFilter(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Reading state and favorite filters
+	if !filter.IsZero() {
+		var tags []string
+		if searchByTag {
+			tags = strings.Split(tag, ",")
+		}
+		qResult, err = db.QueryBookmarksByFlags(
+			r.Context(),
+			query,
+			tags,
+			db.TagAnd,
+			IsFuzzy(r),
+			filter,
+			pageParams,
+		)
+
+		// Search with query AND tags
+	} else if searchByQuery && searchByTag {
 		if strings.Contains(tag, ",") {
 			// Query with multiple tags
 			qResult, err = db.QueryBookmarksByTags(
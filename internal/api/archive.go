@@ -0,0 +1,53 @@
+// Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+// GetAPIArchives lists the snapshots of archived pages, most recent first,
+// filtered with the `query` parameter matching the URL or title.
+func GetAPIArchives(w http.ResponseWriter, r *http.Request) {
+	pageParams := GetPaginationParams(r)
+
+	result, err := db.QuerySnapshots(
+		r.Context(),
+		r.URL.Query().Get("query"),
+		pageParams,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	payload := Payload{
+		Total:   result.Total,
+		Page:    pageParams.Page,
+		PerPage: pageParams.Size,
+		Result:  result.Snapshots,
+	}
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
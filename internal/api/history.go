@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+// PromotedModule is the module of bookmarks promoted from the history
+const PromotedModule = "history"
+
+// GetAPIHistory searches the browsing history. The `query` parameter matches
+// the URL or title of visited pages.
+func GetAPIHistory(w http.ResponseWriter, r *http.Request) {
+	pageParams := GetPaginationParams(r)
+
+	result, err := db.QueryHistory(r.Context(), r.URL.Query().Get("query"), pageParams)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	payload := Payload{
+		Total:   result.Total,
+		Page:    pageParams.Page,
+		PerPage: pageParams.Size,
+		Result:  result.Entries,
+	}
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// PostAPIPromoteHistory promotes the history entry `id` to a bookmark. Tags
+// are passed as a comma separated `tags` parameter.
+func PostAPIPromoteHistory(w http.ResponseWriter, r *http.Request) {
+	bookmark, err := PromoteHistory(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(bookmark); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// PromoteHistory saves the history entry of the request as a bookmark
+func PromoteHistory(r *http.Request) (*gosuki.Bookmark, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid history id: %w", err)
+	}
+
+	entry, err := db.GetHistoryEntry(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("history entry %d: %w", id, err)
+	}
+
+	var tags []string
+	for tag := range strings.SplitSeq(r.FormValue("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	bookmark := &gosuki.Bookmark{
+		URL:    entry.URL,
+		Title:  entry.Title,
+		Tags:   tags,
+		Module: PromotedModule,
+	}
+
+	err = db.LoadBookmarks(func() ([]*gosuki.Bookmark, error) {
+		return []*gosuki.Bookmark{bookmark}, nil
+	}, PromotedModule)
+	if err != nil {
+		return nil, err
+	}
+
+	return bookmark, nil
+}
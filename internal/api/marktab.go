@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/marktab"
+)
+
+type MarktabTestResult struct {
+	Bookmark *gosuki.Bookmark     `json:"bookmark"`
+	Rules    []marktab.Evaluation `json:"rules"`
+}
+
+// MarktabTest evaluates the marktab rules against the bookmark given by the
+// `url`, `title` and `tags` parameters. When `from_db` is set the bookmark
+// is read from the database. Commands are not executed.
+func MarktabTest(r *http.Request) (*MarktabTestResult, error) {
+	url := r.FormValue("url")
+	if url == "" {
+		return nil, errors.New("missing url")
+	}
+
+	bookmark := &gosuki.Bookmark{
+		URL:   url,
+		Title: r.FormValue("title"),
+	}
+	for tag := range strings.SplitSeq(r.FormValue("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			bookmark.Tags = append(bookmark.Tags, tag)
+		}
+	}
+
+	if fromDB, _ := strconv.ParseBool(r.FormValue("from_db")); fromDB {
+		var err error
+		if bookmark, err = db.GetBookmarkByURL(r.Context(), url); err != nil {
+			return nil, fmt.Errorf("bookmark %s: %w", url, err)
+		}
+	}
+
+	if err := marktab.PreloadRules(); err != nil {
+		return nil, err
+	}
+
+	return &MarktabTestResult{
+		Bookmark: bookmark,
+		Rules:    marktab.Evaluate(marktab.Rules(), bookmark),
+	}, nil
+}
+
+func GetAPIMarktabTest(w http.ResponseWriter, r *http.Request) {
+	result, err := MarktabTest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetAPIMarktabRuns lists the runs of marktab commands, most recent first.
+// Runs are filtered with the `status` parameter and the `query` parameter
+// matching the URL or rule trigger.
+func GetAPIMarktabRuns(w http.ResponseWriter, r *http.Request) {
+	pageParams := GetPaginationParams(r)
+
+	result, err := db.QueryMarktabRuns(
+		r.Context(),
+		r.URL.Query().Get("status"),
+		r.URL.Query().Get("query"),
+		pageParams,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	payload := Payload{
+		Total:   result.Total,
+		Page:    pageParams.Page,
+		PerPage: pageParams.Size,
+		Result:  result.Runs,
+	}
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+// BookmarkNote returns the note of the bookmark `id`
+func BookmarkNote(r *http.Request) (*db.Note, error) {
+	bookmark, err := bookmarkFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetNote(r.Context(), bookmark.URL)
+}
+
+// SetBookmarkNote replaces the note of the bookmark `id` with the `notes`
+// parameter. An empty note removes it.
+func SetBookmarkNote(r *http.Request) (*db.Note, error) {
+	bookmark, err := bookmarkFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.SetNote(bookmark.URL, r.FormValue("notes"))
+}
+
+func GetAPINote(w http.ResponseWriter, r *http.Request) {
+	note, err := BookmarkNote(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(note); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func PostAPINote(w http.ResponseWriter, r *http.Request) {
+	note, err := SetBookmarkNote(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(note); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/related"
+)
+
+// Number of related bookmarks when the `limit` parameter is not set
+const DefaultRelatedLimit = 10
+
+type RelatedBookmark struct {
+	*gosuki.Bookmark
+	Score float64 `json:"score"`
+}
+
+// FindRelated returns the bookmarks most similar to the given URL. Indexed
+// bookmarks that are no longer in the database are skipped.
+func FindRelated(ctx context.Context, idx *related.Index, url string, limit int) ([]*RelatedBookmark, error) {
+	if idx == nil {
+		return nil, errors.New("related index not loaded")
+	}
+
+	res := []*RelatedBookmark{}
+	for _, match := range idx.Related(url, 0) {
+		bookmark, err := db.GetBookmarkByURL(ctx, match.URL)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		res = append(res, &RelatedBookmark{bookmark, match.Score})
+		if limit > 0 && len(res) >= limit {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// RelatedBookmarks returns the bookmarks related to the bookmark `id` of the
+// request. The number of results is set with the `limit` parameter.
+func RelatedBookmarks(r *http.Request) ([]*RelatedBookmark, error) {
+	bookmark, err := bookmarkFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := DefaultRelatedLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err = strconv.Atoi(limitStr); err != nil {
+			return nil, fmt.Errorf("invalid limit: %w", err)
+		}
+	}
+
+	return FindRelated(r.Context(), db.RelatedIndex, bookmark.URL, limit)
+}
+
+// GetAPIRelated returns the bookmarks related to the bookmark `id`
+func GetAPIRelated(w http.ResponseWriter, r *http.Request) {
+	bookmarks, err := RelatedBookmarks(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payload := Payload{
+		Total:   uint(len(bookmarks)),
+		Page:    1,
+		PerPage: len(bookmarks),
+		Result:  bookmarks,
+	}
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
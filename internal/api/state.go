@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+// GetFlagsFilter reads the `state` and `favorite` filters of the request
+func GetFlagsFilter(r *http.Request) (db.FlagsFilter, error) {
+	var filter db.FlagsFilter
+	var err error
+
+	if filter.State, err = gosuki.ParseReadState(r.URL.Query().Get("state")); err != nil {
+		return filter, err
+	}
+
+	if fav := r.URL.Query().Get("favorite"); fav != "" {
+		if filter.Favorite, err = strconv.ParseBool(fav); err != nil {
+			return filter, fmt.Errorf("invalid favorite: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// SetBookmarkState changes the reading state and favorite flag of the
+// bookmark `id`. The `state` parameter is a state name, `favorite` is a
+// boolean or `toggle`. Missing parameters leave the flag unchanged.
+func SetBookmarkState(r *http.Request) (*gosuki.Bookmark, error) {
+	bookmark, err := bookmarkFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed gosuki.Flags
+	flags := bookmark.Flags
+	if state := r.FormValue("state"); state != "" {
+		s, err := gosuki.ParseReadState(state)
+		if err != nil {
+			return nil, err
+		}
+		flags = flags.WithState(s)
+		changed |= gosuki.StateMask
+	}
+
+	fav := r.FormValue("favorite")
+	if fav != "" {
+		changed |= gosuki.FlagFavorite
+	}
+	switch fav {
+	case "":
+	case "toggle":
+		flags = flags.WithFavorite(!flags.Favorite())
+	default:
+		v, err := strconv.ParseBool(fav)
+		if err != nil {
+			return nil, fmt.Errorf("invalid favorite: %w", err)
+		}
+		flags = flags.WithFavorite(v)
+	}
+
+	if changed == 0 {
+		return nil, fmt.Errorf("missing state or favorite")
+	}
+
+	if err = db.SetBookmarkFlags(bookmark.URL, changed, flags&changed); err != nil {
+		return nil, err
+	}
+
+	bookmark.Flags = flags
+	return bookmark, nil
+}
+
+// PostAPIBookmarkState sets the reading state and favorite flag of the
+// bookmark `id`
+func PostAPIBookmarkState(w http.ResponseWriter, r *http.Request) {
+	bookmark, err := SetBookmarkState(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(bookmark); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
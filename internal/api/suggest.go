@@ -0,0 +1,220 @@
+// Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/suggest"
+)
+
+// Number of suggested tags when the `limit` parameter is not set
+const DefaultSuggestLimit = 5
+
+type TagSuggestions struct {
+	Bookmark    *gosuki.Bookmark     `json:"bookmark"`
+	Suggestions []suggest.Suggestion `json:"suggestions"`
+}
+
+// suggestion model trained on the disk database at `version`
+var suggestModel struct {
+	sync.Mutex
+	model   *suggest.Model
+	version uint64
+}
+
+// TrainSuggestModel trains a tag suggestion model on the bookmarks stored on
+// disk. Rejected suggestions are loaded in the model.
+func TrainSuggestModel(ctx context.Context) (*suggest.Model, error) {
+	result, err := db.ListBookmarks(ctx, &db.PaginationParams{Page: 1, Size: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	rejections, err := db.TagRejections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	model := suggest.Train(result.Bookmarks)
+	for url, tags := range rejections {
+		model.Reject(url, tags...)
+	}
+	return model, nil
+}
+
+// SuggestModel returns the tag suggestion model, it is trained again when the
+// disk database changed since the last call.
+func SuggestModel(ctx context.Context) (*suggest.Model, error) {
+	suggestModel.Lock()
+	defer suggestModel.Unlock()
+
+	version := db.DiskVersion()
+	if suggestModel.model != nil && suggestModel.version == version {
+		return suggestModel.model, nil
+	}
+
+	model, err := TrainSuggestModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestModel.model = model
+	suggestModel.version = version
+	return model, nil
+}
+
+func bookmarkFromRequest(r *http.Request) (*gosuki.Bookmark, error) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bookmark id: %w", err)
+	}
+
+	bookmark, err := db.GetBookmark(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("bookmark %d: %w", id, err)
+	}
+
+	return bookmark, nil
+}
+
+// SuggestTags suggests tags for the bookmark `id` of the request. The number
+// of suggestions is set with the `limit` parameter.
+func SuggestTags(r *http.Request) (*TagSuggestions, error) {
+	bookmark, err := bookmarkFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := DefaultSuggestLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err = strconv.Atoi(limitStr); err != nil {
+			return nil, fmt.Errorf("invalid limit: %w", err)
+		}
+	}
+
+	model, err := SuggestModel(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return &TagSuggestions{
+		Bookmark:    bookmark,
+		Suggestions: model.Suggest(bookmark, limit),
+	}, nil
+}
+
+// GetAPISuggestTags returns the suggested tags for the bookmark `id`
+func GetAPISuggestTags(w http.ResponseWriter, r *http.Request) {
+	suggestions, err := SuggestTags(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(suggestions); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// AddTags adds the comma separated `tags` parameter to the tags of the
+// bookmark `id`. This is how suggested tags are accepted.
+func AddTags(r *http.Request) (*gosuki.Bookmark, error) {
+	bookmark, err := bookmarkFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for tag := range strings.SplitSeq(r.FormValue("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tags given")
+	}
+
+	bookmark.Tags = utils.Extends(bookmark.Tags, tags...)
+
+	err = db.LoadBookmarks(func() ([]*gosuki.Bookmark, error) {
+		return []*gosuki.Bookmark{bookmark}, nil
+	}, bookmark.Module)
+	if err != nil {
+		return nil, err
+	}
+
+	return bookmark, nil
+}
+
+// PostAPIAddTags adds tags to the bookmark `id`
+func PostAPIAddTags(w http.ResponseWriter, r *http.Request) {
+	bookmark, err := AddTags(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(bookmark); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// RejectTag stops suggesting the `tag` parameter for the bookmark `id`
+func RejectTag(r *http.Request) (*gosuki.Bookmark, error) {
+	bookmark, err := bookmarkFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := strings.TrimSpace(r.FormValue("tag"))
+	if err := db.RejectTag(bookmark.URL, tag); err != nil {
+		return nil, err
+	}
+
+	suggestModel.Lock()
+	defer suggestModel.Unlock()
+	if suggestModel.model != nil {
+		suggestModel.model.Reject(bookmark.URL, tag)
+	}
+
+	return bookmark, nil
+}
+
+// PostAPIRejectTag rejects a suggested tag of the bookmark `id`
+func PostAPIRejectTag(w http.ResponseWriter, r *http.Request) {
+	if _, err := RejectTag(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/blob42/gosuki/pkg/tabs"
+)
+
+// GetAPITabs returns the open tabs of all detected profiles. The `module`
+// parameter keeps the tabs of modules starting with the given name.
+func GetAPITabs(w http.ResponseWriter, r *http.Request) {
+	module := r.URL.Query().Get("module")
+
+	result := []*tabs.Tab{}
+	for _, t := range tabs.Collect() {
+		if strings.HasPrefix(t.Module, module) {
+			result = append(result, t)
+		}
+	}
+
+	payload := Payload{
+		Total:   uint(len(result)),
+		Page:    1,
+		PerPage: len(result),
+		Result:  result,
+	}
+
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
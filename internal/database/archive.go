@@ -0,0 +1,175 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blob42/gosuki/pkg/archive"
+)
+
+// Snapshots of archived pages, the files are in the archive directory
+const QCreateArchivesSchema = `
+	CREATE TABLE IF NOT EXISTS gskarchives (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		URL TEXT NOT NULL,
+		title TEXT DEFAULT '',
+		path TEXT NOT NULL,
+		format TEXT DEFAULT '',
+		size INTEGER DEFAULT 0,
+		created INTEGER DEFAULT 0
+	);
+
+	CREATE INDEX IF NOT EXISTS gskarchives_url ON gskarchives(URL)
+`
+
+const (
+	QInsertSnapshot = `
+	INSERT INTO gskarchives (URL, title, path, format, size, created)
+	VALUES (:URL, :title, :path, :format, :size, :created)
+	`
+
+	QSelectSnapshots = `
+	SELECT * FROM gskarchives
+	WHERE URL LIKE ? OR title LIKE ?
+	ORDER BY created DESC, id DESC
+	`
+
+	QCountSnapshots = `
+	SELECT COUNT(*) FROM gskarchives
+	WHERE URL LIKE ? OR title LIKE ?
+	`
+)
+
+type SnapshotsResult struct {
+	Snapshots []*archive.Snapshot
+	Total     uint
+}
+
+// SaveSnapshot records the snapshot and sets its ID
+func (db *DB) SaveSnapshot(snap *archive.Snapshot) error {
+	res, err := db.Handle.NamedExec(QInsertSnapshot, snap)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	if snap.ID, err = res.LastInsertId(); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// HasSnapshot returns true if the URL was archived
+func (db *DB) HasSnapshot(url string) (bool, error) {
+	var count int
+	err := db.Handle.Get(&count, "SELECT COUNT(*) FROM gskarchives WHERE URL = ?", url)
+	if err != nil {
+		return false, DBError{DBName: db.Name, Err: err}
+	}
+	return count > 0, nil
+}
+
+// QuerySnapshots lists the snapshots on disk, most recent first, filtered by
+// `query` in the URL or title
+func QuerySnapshots(
+	ctx context.Context,
+	query string,
+	pagination *PaginationParams,
+) (*SnapshotsResult, error) {
+	return DiskDB.QuerySnapshots(ctx, query, pagination)
+}
+
+func (db *DB) QuerySnapshots(
+	ctx context.Context,
+	query string,
+	pagination *PaginationParams,
+) (*SnapshotsResult, error) {
+	pattern := fmt.Sprintf("%%%s%%", query)
+	args := []any{pattern, pattern}
+
+	sqlQuery := QSelectSnapshots
+	if pagination != nil && pagination.Size > 0 {
+		sqlQuery += fmt.Sprintf(QQueryPaginate,
+			pagination.Size,
+			(pagination.Page-1)*pagination.Size,
+		)
+	}
+
+	snapshots := []*archive.Snapshot{}
+	if err := db.Handle.SelectContext(ctx, &snapshots, sqlQuery, args...); err != nil {
+		return nil, err
+	}
+
+	var total uint
+	if err := db.Handle.GetContext(ctx, &total, QCountSnapshots, args...); err != nil {
+		return nil, err
+	}
+
+	return &SnapshotsResult{snapshots, total}, nil
+}
+
+// GetSnapshot returns the snapshot with this id from disk
+func GetSnapshot(ctx context.Context, id int64) (*archive.Snapshot, error) {
+	snap := &archive.Snapshot{}
+	err := DiskDB.Handle.GetContext(ctx, snap, "SELECT * FROM gskarchives WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// LatestSnapshot returns the most recent snapshot of the URL in this format
+func (db *DB) LatestSnapshot(url, format string) (*archive.Snapshot, error) {
+	snap := &archive.Snapshot{}
+	err := db.Handle.Get(snap, `
+		SELECT * FROM gskarchives WHERE URL = ? AND format = ?
+		ORDER BY created DESC, id DESC LIMIT 1`,
+		url, format,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// archiveStore records the snapshots taken by the daemon in the L2 cache which
+// is written to disk with the bookmarks
+type archiveStore struct{}
+
+func (archiveStore) SaveSnapshot(snap *archive.Snapshot) error {
+	if L2Cache.DB == nil {
+		return fmt.Errorf("L2 cache not initialized")
+	}
+	if err := L2Cache.SaveSnapshot(snap); err != nil {
+		return err
+	}
+	ScheduleBackupToDisk()
+	return nil
+}
+
+func (archiveStore) HasSnapshot(url string) (bool, error) {
+	if L2Cache.DB == nil {
+		return false, fmt.Errorf("L2 cache not initialized")
+	}
+	return L2Cache.HasSnapshot(url)
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki/pkg/archive"
+)
+
+func TestSnapshots(t *testing.T) {
+	ctx := context.Background()
+	buffer, err := NewBuffer("test_snapshots")
+	require.NoError(t, err)
+	defer buffer.Close()
+
+	for i := range 3 {
+		snap := &archive.Snapshot{
+			URL:     fmt.Sprintf("https://example.com/%d", i),
+			Title:   fmt.Sprintf("page %d", i),
+			Path:    fmt.Sprintf("example.com/%d.html", i),
+			Format:  archive.FormatHTML,
+			Size:    100,
+			Created: int64(100 + i),
+		}
+		require.NoError(t, buffer.SaveSnapshot(snap))
+		assert.EqualValues(t, i+1, snap.ID)
+	}
+
+	archived, err := buffer.HasSnapshot("https://example.com/1")
+	require.NoError(t, err)
+	assert.True(t, archived)
+	archived, err = buffer.HasSnapshot("https://example.com/9")
+	require.NoError(t, err)
+	assert.False(t, archived)
+
+	result, err := buffer.QuerySnapshots(ctx, "", nil)
+	require.NoError(t, err)
+	require.Len(t, result.Snapshots, 3)
+	assert.EqualValues(t, 3, result.Total)
+	assert.Equal(t, "https://example.com/2", result.Snapshots[0].URL)
+
+	result, err = buffer.QuerySnapshots(ctx, "page 1", &PaginationParams{Page: 1, Size: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Snapshots, 1)
+	assert.Equal(t, "example.com/1.html", result.Snapshots[0].Path)
+}
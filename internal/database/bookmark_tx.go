@@ -22,6 +22,9 @@
 package database
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"html"
 
 	"github.com/blob42/gosuki"
@@ -80,6 +83,7 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 		SET
 			metadata = CASE WHEN ? != '' THEN ? ELSE metadata END,
 			desc = CASE WHEN ? != '' THEN ? ELSE desc END,
+			flags = (flags & ~?) | ?,
 			tags=?,
 			modified=strftime('%s'),
 			xhsum=?
@@ -129,7 +133,7 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 		bk.Title, //metadata
 		tagListText,
 		bk.Desc,
-		0,         // flags
+		bk.Flags,
 		bk.Module, // source module that created this mark
 
 		// empty xhash: it will be calculated in the cache
@@ -154,16 +158,21 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 	if err != nil && sqlite3Err.Code == sqlite3.ErrConstraint {
 		log.Tracef("Updating bookmark %s", bk.URL)
 
-		// Get existing xhashsum of bookmark
+		// Get existing xhashsum and flags of bookmark
 		var targetXHSum string
-		err = tx.QueryRowx("SELECT xhsum FROM gskbookmarks WHERE url = ?", bk.URL).Scan(&targetXHSum)
+		var targetFlags gosuki.Flags
+		err = tx.QueryRowx(
+			"SELECT xhsum, flags FROM gskbookmarks WHERE url = ?", bk.URL,
+		).Scan(&targetXHSum, &targetFlags)
 		if err != nil {
 			log.Error("%s", err, "url", bk.URL)
 			return err
 		}
 
-		// We will only update the bookmark if the xhsum changed
-		if targetXHSum == xhsum(bk.URL, bk.Title, tagListText, bk.Desc) {
+		// We will only update the bookmark if the xhsum changed. The flags of
+		// the bookmark are merged into the stored flags.
+		if targetXHSum == xhsum(bk.URL, bk.Title, tagListText, bk.Desc,
+			targetFlags.Merge(bk.Flags)) {
 			log.Trace("upsert: same hash skipping", "url", bk.URL)
 			return tx.Rollback()
 		}
@@ -202,6 +211,8 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 			bk.Title,
 			bk.Desc,
 			bk.Desc,
+			bk.Flags.Mask(),
+			bk.Flags,
 			tagListText,
 
 			// xhsum calculated in cache
@@ -219,3 +230,50 @@ func (db *DB) UpsertBookmark(bk *Bookmark) error {
 
 	return tx.Commit()
 }
+
+// UpdateBookmark replaces the title, tags and description of an existing
+// bookmark. Unlike [DB.UpsertBookmark] the tags are not merged with the stored
+// tags, which allows removing tags.
+func (db *DB) UpdateBookmark(bk *Bookmark) error {
+	tags := NewTags(bk.Tags, TagSep).PreSanitize().Sort()
+	tagListText := tags.String(true)
+
+	tx, err := db.Handle.Beginx()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	defer tx.Rollback()
+
+	var flags gosuki.Flags
+	err = tx.Get(&flags, "SELECT flags FROM gskbookmarks WHERE url = ?", bk.URL)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("bookmark not found: %s", bk.URL)
+	} else if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	_, err = tx.Exec(
+		`UPDATE gskbookmarks
+		SET
+			metadata = ?,
+			tags = ?,
+			desc = ?,
+			modified = strftime('%s'),
+			xhsum = ?
+		WHERE url = ?`,
+		bk.Title,
+		tagListText,
+		bk.Desc,
+		xhsum(bk.URL, bk.Title, tagListText, bk.Desc, flags),
+		bk.URL,
+	)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	return nil
+}
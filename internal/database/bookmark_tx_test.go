@@ -0,0 +1,40 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func TestUpdateBookmark(t *testing.T) {
+	buffer, err := NewBuffer("test_update")
+	require.NoError(t, err)
+	defer buffer.Close()
+
+	require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{
+		URL:   "https://example.com/",
+		Title: "Example",
+		Tags:  []string{"a", "b"},
+	}))
+
+	require.NoError(t, buffer.UpdateBookmark(&gosuki.Bookmark{
+		URL:   "https://example.com/",
+		Title: "Example",
+		Tags:  []string{"a", "c"},
+		Desc:  "desc",
+	}))
+
+	var row RawBookmark
+	require.NoError(t, buffer.Handle.Get(&row,
+		"SELECT * FROM gskbookmarks WHERE URL = ?", "https://example.com/"))
+	assert.Equal(t, ",a,c,", row.Tags, "tags are replaced")
+	assert.Equal(t, "desc", row.Desc)
+	assert.Equal(t, xhsum(row.URL, row.Metadata, row.Tags, row.Desc, 0), row.XHSum,
+		"the hash matches the stored row")
+
+	err = buffer.UpdateBookmark(&gosuki.Bookmark{URL: "https://missing.example.com/"})
+	assert.Error(t, err)
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/OneOfOne/xxhash"
 	"github.com/lithammer/fuzzysearch/fuzzy"
 
+	"github.com/blob42/gosuki"
 	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/pkg/config"
 	"github.com/blob42/gosuki/pkg/logging"
@@ -375,8 +376,9 @@ func SQLxxHash(in string) string {
 	return fmt.Sprintf("%d", xxhash.ChecksumString64(in))
 }
 
-// Calculates xxhash sum for a bookmark
-func xhsum(url, metadata, tags, desc string) string {
+// Calculates xxhash sum for a bookmark. Flags are only part of the sum when
+// set, which keeps the sums of bookmarks without flags unchanged.
+func xhsum(url, metadata, tags, desc string, flags gosuki.Flags) string {
 	input := fmt.Sprintf(
 		"%s+%s+%s+%s",
 		url,
@@ -384,6 +386,9 @@ func xhsum(url, metadata, tags, desc string) string {
 		tags,
 		desc,
 	)
+	if flags != 0 {
+		input += fmt.Sprintf("+%d", flags)
+	}
 	return SQLxxHash(input)
 }
 
@@ -0,0 +1,176 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blob42/gosuki"
+)
+
+// FlagsFilter restricts a query to a reading state and/or to favorites. The
+// zero value matches all bookmarks.
+type FlagsFilter struct {
+	// StateNone matches any state
+	State    gosuki.ReadState
+	Favorite bool
+}
+
+func (f FlagsFilter) IsZero() bool {
+	return f.State == gosuki.StateNone && !f.Favorite
+}
+
+func (f FlagsFilter) where() string {
+	conditions := []string{"1=1"}
+	if f.State != gosuki.StateNone {
+		conditions = append(conditions,
+			fmt.Sprintf("(flags & %d) = %d", gosuki.StateMask, f.State))
+	}
+	if f.Favorite {
+		conditions = append(conditions,
+			fmt.Sprintf("(flags & %d) != 0", gosuki.FlagFavorite))
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+// QueryBookmarksByFlags searches the bookmarks matching the flags filter. The
+// text query and tags are optional.
+func QueryBookmarksByFlags(
+	ctx context.Context,
+	query string,
+	tags []string,
+	cond TagCond,
+	fuzzy bool,
+	filter FlagsFilter,
+	pagination *PaginationParams,
+) (*QueryResult, error) {
+	if pagination == nil {
+		return nil, errors.New("nil: *PaginationParams")
+	}
+
+	whereClause := fmt.Sprintf("%s AND %s",
+		buildWhereClauseForManyTags(query, tags, cond, fuzzy),
+		filter.where(),
+	)
+	log.Trace(whereClause)
+
+	rawBooks := RawBookmarks{}
+	err := DiskDB.Handle.SelectContext(ctx, &rawBooks, fmt.Sprintf(
+		"SELECT * FROM gskbookmarks WHERE %s "+QQueryPaginate,
+		whereClause,
+		pagination.Size,
+		(pagination.Page-1)*pagination.Size,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var total uint
+	err = DiskDB.Handle.GetContext(ctx, &total,
+		fmt.Sprintf("SELECT COUNT(*) FROM gskbookmarks WHERE %s", whereClause))
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryResult{rawBooks.AsBookmarks(), total}, nil
+}
+
+// UpdateFlags clears then sets bits of the flags of a bookmark. The checksum
+// of the bookmark is updated and its version set to the given clock value.
+func (db *DB) UpdateFlags(url string, clear, set gosuki.Flags, version uint64) error {
+	tx, err := db.Handle.Beginx()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	defer tx.Rollback()
+
+	row := RawBookmark{}
+	err = tx.Get(&row,
+		"SELECT URL, metadata, tags, desc, flags FROM gskbookmarks WHERE URL = ?",
+		url,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("bookmark not found: %s", url)
+	} else if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	flags := gosuki.Flags(row.Flags)&^clear | set
+	_, err = tx.Exec(
+		`UPDATE gskbookmarks
+		SET
+			flags = ?,
+			modified = strftime('%s'),
+			xhsum = ?,
+			version = ?
+		WHERE URL = ?`,
+		flags,
+		xhsum(row.URL, row.Metadata, row.Tags, row.Desc, flags),
+		version,
+		url,
+	)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	return nil
+}
+
+// SetBookmarkFlags updates the flags of a bookmark in the caches and on disk.
+// The change ticks the clock so the new version reaches peers. Clearing flags
+// is only possible here, syncs between levels merge flags.
+func SetBookmarkFlags(url string, clear, set gosuki.Flags) error {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	version := Clock.LocalTick()
+
+	var found bool
+	for _, db := range []*DB{Cache.DB, L2Cache.DB, DiskDB} {
+		if db == nil || db.Handle == nil {
+			continue
+		}
+
+		err := db.UpdateFlags(url, clear, set, version)
+		if err == nil {
+			found = true
+		} else if _, isDBErr := err.(DBError); isDBErr {
+			return err
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("bookmark not found: %s", url)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/hooks"
+)
+
+func TestBookmarkFlags(t *testing.T) {
+	buffer, err := NewBuffer("test_flags")
+	require.NoError(t, err)
+	defer buffer.Close()
+
+	unread := gosuki.Flags(0).WithState(gosuki.StateUnread)
+	require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{
+		URL:   "https://example.com/article",
+		Title: "Article",
+		Flags: unread,
+	}))
+	require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{
+		URL:   "https://example.com/other",
+		Title: "Other",
+	}))
+
+	flagsOf := func(url string) gosuki.Flags {
+		var flags gosuki.Flags
+		require.NoError(t, buffer.Handle.Get(&flags,
+			"SELECT flags FROM gskbookmarks WHERE URL = ?", url))
+		return flags
+	}
+
+	t.Run("upsert without flags keeps them", func(t *testing.T) {
+		require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{
+			URL:   "https://example.com/article",
+			Title: "Article renamed",
+		}))
+		assert.Equal(t, unread, flagsOf("https://example.com/article"))
+	})
+
+	t.Run("update flags", func(t *testing.T) {
+		set := gosuki.Flags(0).WithState(gosuki.StateRead).WithFavorite(true)
+		require.NoError(t, buffer.UpdateFlags("https://example.com/article",
+			gosuki.StateMask|gosuki.FlagFavorite, set, 1))
+		flags := flagsOf("https://example.com/article")
+		assert.Equal(t, gosuki.StateRead, flags.State())
+		assert.True(t, flags.Favorite())
+
+		// clearing the favorite bit keeps the state
+		require.NoError(t, buffer.UpdateFlags("https://example.com/article",
+			gosuki.FlagFavorite, 0, 2))
+		flags = flagsOf("https://example.com/article")
+		assert.Equal(t, gosuki.StateRead, flags.State())
+		assert.False(t, flags.Favorite())
+
+		assert.Error(t, buffer.UpdateFlags("https://example.com/missing", 0, 0, 3))
+
+		row := RawBookmark{}
+		require.NoError(t, buffer.Handle.Get(&row,
+			"SELECT * FROM gskbookmarks WHERE URL = ?", "https://example.com/article"))
+		assert.Equal(t, uint64(2), row.Version)
+		assert.Equal(t,
+			xhsum(row.URL, row.Metadata, row.Tags, row.Desc, gosuki.Flags(row.Flags)),
+			row.XHSum)
+	})
+
+	t.Run("upsert state keeps favorite", func(t *testing.T) {
+		require.NoError(t, buffer.UpdateFlags("https://example.com/other",
+			0, gosuki.FlagFavorite, 4))
+		require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{
+			URL:   "https://example.com/other",
+			Title: "Other",
+			Flags: gosuki.Flags(0).WithState(gosuki.StateArchived),
+		}))
+		flags := flagsOf("https://example.com/other")
+		assert.Equal(t, gosuki.StateArchived, flags.State())
+		assert.True(t, flags.Favorite())
+
+		require.NoError(t, buffer.UpdateFlags("https://example.com/other",
+			gosuki.StateMask|gosuki.FlagFavorite, 0, 5))
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		var urls []string
+		require.NoError(t, buffer.Handle.Select(&urls,
+			"SELECT URL FROM gskbookmarks WHERE "+
+				FlagsFilter{State: gosuki.StateRead}.where()))
+		assert.Equal(t, []string{"https://example.com/article"}, urls)
+
+		urls = nil
+		require.NoError(t, buffer.Handle.Select(&urls,
+			"SELECT URL FROM gskbookmarks WHERE "+
+				FlagsFilter{Favorite: true}.where()))
+		assert.Empty(t, urls)
+
+		assert.True(t, FlagsFilter{}.IsZero())
+	})
+}
+
+func TestSyncFlags(t *testing.T) {
+	savedHooks := hooksQueue
+	hooksQueue = make(chan hooks.HookJob, 10)
+	defer func() { hooksQueue = savedHooks }()
+	Clock = &LamportClock{}
+
+	src, err := NewBuffer("test_flags_src")
+	require.NoError(t, err)
+	defer src.Close()
+	dst, err := NewDB("test_flags_cache", "", DBTypeCacheDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, dst.InitSchema(context.Background()))
+	defer dst.Close()
+
+	url := "https://example.com/article"
+	require.NoError(t, src.UpsertBookmark(&gosuki.Bookmark{URL: url, Title: "Article"}))
+	src.SyncTo(dst)
+
+	flagsOf := func(url string) gosuki.Flags {
+		var flags gosuki.Flags
+		require.NoError(t, dst.Handle.Get(&flags,
+			"SELECT flags FROM gskbookmarks WHERE URL = ?", url))
+		return flags
+	}
+
+	// a flag change alone is synced
+	require.NoError(t, src.UpdateFlags(url, 0, gosuki.FlagFavorite, 1))
+	src.SyncTo(dst)
+	assert.Equal(t, gosuki.FlagFavorite, flagsOf(url))
+
+	// the state is merged and the favorite bit is kept
+	require.NoError(t, src.UpdateFlags(url, gosuki.FlagFavorite,
+		gosuki.Flags(0).WithState(gosuki.StateRead), 2))
+	src.SyncTo(dst)
+	assert.Equal(t, gosuki.Flags(0).WithState(gosuki.StateRead).WithFavorite(true),
+		flagsOf(url))
+}
@@ -0,0 +1,206 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Visited pages are kept once per URL and module. visit_count and
+// last_visit are copied from the browser history.
+const QCreateHistorySchema = `
+	CREATE TABLE IF NOT EXISTS gskhistory (
+		id INTEGER PRIMARY KEY,
+		URL TEXT NOT NULL,
+		title TEXT DEFAULT '',
+		module TEXT DEFAULT '',
+		visit_count INTEGER DEFAULT 0,
+		last_visit INTEGER DEFAULT 0,
+		UNIQUE(URL, module)
+	);
+
+	CREATE INDEX IF NOT EXISTS gskhistory_last_visit ON gskhistory(last_visit)
+`
+
+const (
+	QUpsertHistory = `
+	INSERT INTO gskhistory (URL, title, module, visit_count, last_visit)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(URL, module) DO UPDATE SET
+		title = CASE WHEN excluded.title != '' THEN excluded.title ELSE title END,
+		visit_count = excluded.visit_count,
+		last_visit = MAX(last_visit, excluded.last_visit)
+	`
+
+	// History entries are grouped by URL across modules
+	QSelectHistory = `
+	SELECT MIN(id) AS id, URL, MAX(title) AS title,
+		GROUP_CONCAT(module) AS module,
+		SUM(visit_count) AS visit_count, MAX(last_visit) AS last_visit
+	FROM gskhistory
+	WHERE URL LIKE ? OR title LIKE ?
+	GROUP BY URL
+	ORDER BY last_visit DESC
+	`
+
+	QCountHistory = `
+	SELECT COUNT(DISTINCT URL) FROM gskhistory
+	WHERE URL LIKE ? OR title LIKE ?
+	`
+)
+
+// HistoryEntry is a visited page indexed by the history module
+type HistoryEntry struct {
+	ID         int64  `db:"id" json:"id"`
+	URL        string `db:"URL" json:"url"`
+	Title      string `db:"title" json:"title"`
+	Module     string `db:"module" json:"module"`
+	VisitCount int    `db:"visit_count" json:"visit_count"`
+
+	// unix timestamp of the last visit
+	LastVisit int64 `db:"last_visit" json:"last_visit"`
+}
+
+type HistoryResult struct {
+	Entries []*HistoryEntry
+	Total   uint
+}
+
+// UpsertHistory inserts or updates history entries
+func (db *DB) UpsertHistory(entries []*HistoryEntry) error {
+	tx, err := db.Handle.Beginx()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	stmt, err := tx.Preparex(QUpsertHistory)
+	if err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+	defer stmt.Close()
+
+	for _, e := range entries {
+		_, err = stmt.Exec(e.URL, e.Title, e.Module, e.VisitCount, e.LastVisit)
+		if err != nil {
+			tx.Rollback()
+			return DBError{DBName: db.Name, Err: err}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	return nil
+}
+
+// PruneHistory removes entries last visited before `before` when it is not
+// the zero time and keeps at most `maxEntries` entries when it is positive.
+// Returns the number of removed entries.
+func (db *DB) PruneHistory(before time.Time, maxEntries int) (int64, error) {
+	var removed int64
+	if !before.IsZero() {
+		res, err := db.Handle.Exec(
+			"DELETE FROM gskhistory WHERE last_visit < ?",
+			before.Unix(),
+		)
+		if err != nil {
+			return 0, DBError{DBName: db.Name, Err: err}
+		}
+		removed, _ = res.RowsAffected()
+	}
+
+	if maxEntries <= 0 {
+		return removed, nil
+	}
+
+	res, err := db.Handle.Exec(`
+		DELETE FROM gskhistory WHERE id NOT IN (
+			SELECT id FROM gskhistory ORDER BY last_visit DESC LIMIT ?
+		)`,
+		maxEntries,
+	)
+	if err != nil {
+		return removed, DBError{DBName: db.Name, Err: err}
+	}
+	n, _ := res.RowsAffected()
+
+	return removed + n, nil
+}
+
+// QueryHistory searches the history on disk for `query` in the URL or title
+func QueryHistory(
+	ctx context.Context,
+	query string,
+	pagination *PaginationParams,
+) (*HistoryResult, error) {
+	return DiskDB.QueryHistory(ctx, query, pagination)
+}
+
+func (db *DB) QueryHistory(
+	ctx context.Context,
+	query string,
+	pagination *PaginationParams,
+) (*HistoryResult, error) {
+	pattern := fmt.Sprintf("%%%s%%", query)
+
+	sqlQuery := QSelectHistory
+	if pagination != nil && pagination.Size > 0 {
+		sqlQuery += fmt.Sprintf(QQueryPaginate,
+			pagination.Size,
+			(pagination.Page-1)*pagination.Size,
+		)
+	}
+
+	entries := []*HistoryEntry{}
+	err := db.Handle.SelectContext(ctx, &entries, sqlQuery, pattern, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var total uint
+	err = db.Handle.GetContext(ctx, &total, QCountHistory, pattern, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistoryResult{entries, total}, nil
+}
+
+// GetHistoryEntry returns the history entry with `id` from disk
+func GetHistoryEntry(ctx context.Context, id int64) (*HistoryEntry, error) {
+	entry := &HistoryEntry{}
+	err := DiskDB.Handle.GetContext(ctx, entry, `
+		SELECT id, URL, title, module, visit_count, last_visit
+		FROM gskhistory WHERE id = ?`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
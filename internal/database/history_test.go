@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDB("test_history", "", DBTypeInMemoryDSN).Init()
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.InitSchema(ctx))
+
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+
+	require.NoError(t, db.UpsertHistory([]*HistoryEntry{
+		{URL: "https://go.dev/doc/", Title: "Go docs", Module: "firefox_default", VisitCount: 3, LastVisit: now.Unix()},
+		{URL: "https://go.dev/doc/", Title: "Documentation", Module: "chrome_Default", VisitCount: 2, LastVisit: old.Unix()},
+		{URL: "https://example.com/", Title: "Example", Module: "firefox_default", VisitCount: 1, LastVisit: old.Unix()},
+	}))
+
+	t.Run("upsert", func(t *testing.T) {
+		// empty titles keep the previous one, counts are replaced
+		require.NoError(t, db.UpsertHistory([]*HistoryEntry{
+			{URL: "https://go.dev/doc/", Module: "firefox_default", VisitCount: 5, LastVisit: old.Unix()},
+		}))
+
+		var entry HistoryEntry
+		require.NoError(t, db.Handle.Get(&entry,
+			"SELECT * FROM gskhistory WHERE URL = ? AND module = ?",
+			"https://go.dev/doc/", "firefox_default"))
+		assert.Equal(t, "Go docs", entry.Title)
+		assert.Equal(t, 5, entry.VisitCount)
+		assert.Equal(t, now.Unix(), entry.LastVisit)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		res, err := db.QueryHistory(ctx, "go.dev", DefaultPagination())
+		require.NoError(t, err)
+		require.Len(t, res.Entries, 1)
+		assert.Equal(t, uint(1), res.Total)
+
+		entry := res.Entries[0]
+		assert.Equal(t, 7, entry.VisitCount)
+		assert.Equal(t, now.Unix(), entry.LastVisit)
+		assert.Contains(t, entry.Module, "firefox_default")
+		assert.Contains(t, entry.Module, "chrome_Default")
+
+		res, err = db.QueryHistory(ctx, "", DefaultPagination())
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), res.Total)
+		assert.Equal(t, "https://go.dev/doc/", res.Entries[0].URL, "most recent first")
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		removed, err := db.PruneHistory(time.Time{}, 0)
+		require.NoError(t, err)
+		assert.Zero(t, removed, "zero time keeps all entries")
+
+		removed, err = db.PruneHistory(now.Add(-24*time.Hour), 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), removed)
+
+		require.NoError(t, db.UpsertHistory([]*HistoryEntry{
+			{URL: "https://a.example.com/", Module: "m", LastVisit: now.Unix() - 1},
+			{URL: "https://b.example.com/", Module: "m", LastVisit: now.Unix() - 2},
+		}))
+
+		removed, err = db.PruneHistory(old, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+
+		res, err := db.QueryHistory(ctx, "", DefaultPagination())
+		require.NoError(t, err)
+		require.Len(t, res.Entries, 2)
+		assert.Equal(t, "https://go.dev/doc/", res.Entries[0].URL)
+		assert.Equal(t, "https://a.example.com/", res.Entries[1].URL)
+	})
+}
@@ -0,0 +1,410 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/hooks"
+	"github.com/blob42/gosuki/pkg/marktab"
+)
+
+// Runs of marktab commands, keyed by their GOSUKI_RUN_ID
+const QCreateMarktabRunsSchema = `
+	CREATE TABLE IF NOT EXISTS gskmarktabruns (
+		run_id TEXT PRIMARY KEY,
+		rule TEXT DEFAULT '',
+		pattern TEXT DEFAULT '',
+		command TEXT DEFAULT '',
+		line INTEGER DEFAULT 0,
+		URL TEXT DEFAULT '',
+		status TEXT DEFAULT '',
+		exit_code INTEGER DEFAULT 0,
+		attempts INTEGER DEFAULT 0,
+		started INTEGER DEFAULT 0,
+		duration INTEGER DEFAULT 0,
+		stdout TEXT DEFAULT '',
+		stderr TEXT DEFAULT '',
+		error TEXT DEFAULT ''
+	);
+
+	CREATE INDEX IF NOT EXISTS gskmarktabruns_started ON gskmarktabruns(started)
+`
+
+const (
+	QUpsertMarktabRun = `
+	INSERT INTO gskmarktabruns (run_id, rule, pattern, command, line, URL,
+		status, exit_code, attempts, started, duration, stdout, stderr, error)
+	VALUES (:run_id, :rule, :pattern, :command, :line, :URL,
+		:status, :exit_code, :attempts, :started, :duration, :stdout, :stderr, :error)
+	ON CONFLICT(run_id) DO UPDATE SET
+		status = excluded.status,
+		exit_code = excluded.exit_code,
+		attempts = excluded.attempts,
+		duration = excluded.duration,
+		stdout = excluded.stdout,
+		stderr = excluded.stderr,
+		error = excluded.error
+	`
+
+	QSelectMarktabRuns = `
+	SELECT * FROM gskmarktabruns
+	WHERE (? = '' OR status = ?) AND (URL LIKE ? OR rule LIKE ?)
+	ORDER BY started DESC, rowid DESC
+	`
+
+	QCountMarktabRuns = `
+	SELECT COUNT(*) FROM gskmarktabruns
+	WHERE (? = '' OR status = ?) AND (URL LIKE ? OR rule LIKE ?)
+	`
+)
+
+type MarktabRunsResult struct {
+	Runs  []*marktab.Run
+	Total uint
+}
+
+// SaveMarktabRun inserts or updates a run
+func (db *DB) SaveMarktabRun(run *marktab.Run) error {
+	if _, err := db.Handle.NamedExec(QUpsertMarktabRun, run); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// PruneMarktabRuns keeps the `maxRuns` most recent runs
+func (db *DB) PruneMarktabRuns(maxRuns int) error {
+	if maxRuns <= 0 {
+		return nil
+	}
+
+	_, err := db.Handle.Exec(`
+		DELETE FROM gskmarktabruns WHERE run_id NOT IN (
+			SELECT run_id FROM gskmarktabruns ORDER BY started DESC LIMIT ?
+		)`,
+		maxRuns,
+	)
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// QueryMarktabRuns lists the runs on disk, most recent first. Runs are
+// filtered by `status` when set and by `query` in the URL or rule trigger.
+func QueryMarktabRuns(
+	ctx context.Context,
+	status string,
+	query string,
+	pagination *PaginationParams,
+) (*MarktabRunsResult, error) {
+	return DiskDB.QueryMarktabRuns(ctx, status, query, pagination)
+}
+
+func (db *DB) QueryMarktabRuns(
+	ctx context.Context,
+	status string,
+	query string,
+	pagination *PaginationParams,
+) (*MarktabRunsResult, error) {
+	pattern := fmt.Sprintf("%%%s%%", query)
+	args := []any{status, status, pattern, pattern}
+
+	sqlQuery := QSelectMarktabRuns
+	if pagination != nil && pagination.Size > 0 {
+		sqlQuery += fmt.Sprintf(QQueryPaginate,
+			pagination.Size,
+			(pagination.Page-1)*pagination.Size,
+		)
+	}
+
+	runs := []*marktab.Run{}
+	if err := db.Handle.SelectContext(ctx, &runs, sqlQuery, args...); err != nil {
+		return nil, err
+	}
+
+	var total uint
+	if err := db.Handle.GetContext(ctx, &total, QCountMarktabRuns, args...); err != nil {
+		return nil, err
+	}
+
+	return &MarktabRunsResult{runs, total}, nil
+}
+
+// GetMarktabRun returns the run with the GOSUKI_RUN_ID `id` from disk
+func GetMarktabRun(ctx context.Context, id string) (*marktab.Run, error) {
+	run := &marktab.Run{}
+	err := DiskDB.Handle.GetContext(ctx, run,
+		"SELECT * FROM gskmarktabruns WHERE run_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// marktabRunStore saves runs in the L2 cache which is written to disk with
+// the bookmarks
+type marktabRunStore struct{}
+
+func (marktabRunStore) SaveRun(run *marktab.Run) error {
+	if L2Cache.DB == nil {
+		return fmt.Errorf("L2 cache not initialized")
+	}
+
+	if err := L2Cache.SaveMarktabRun(run); err != nil {
+		return err
+	}
+
+	switch run.Status {
+	case marktab.RunQueued, marktab.RunRunning:
+	default:
+		if err := L2Cache.PruneMarktabRuns(marktab.Config.MaxRuns); err != nil {
+			return err
+		}
+	}
+
+	ScheduleBackupToDisk()
+	return nil
+}
+
+var (
+	marktabFilters = marktab.NewFilters()
+
+	// urls of changed bookmarks waiting for the filters, with the sequence
+	// number of their last change. Pending bookmarks are held in the L1 cache,
+	// they reach the L2 cache, the global hooks and the disk once filtered.
+	filterPending   = map[string]uint64{}
+	filterSeq       uint64
+	filterPendingMu sync.Mutex
+	filterQueue     chan struct{}
+)
+
+// marktabFiltersEnabled reports whether the marktab rules have filters
+func marktabFiltersEnabled() bool {
+	if filterQueue == nil {
+		return false
+	}
+	if err := marktab.PreloadRules(); err != nil {
+		log.Error("marktab filters", "err", err)
+	}
+	return marktab.HasFilters()
+}
+
+// queueMarktabFilters schedules the filters for the changed bookmarks. The
+// filters run in [marktabFilterWorker] so they never hold the sync.
+func queueMarktabFilters(urls []string) {
+	if len(urls) == 0 || !marktabFiltersEnabled() {
+		return
+	}
+
+	keepPendingFilters(urls)
+	scheduleMarktabFilters()
+}
+
+func scheduleMarktabFilters() {
+	select {
+	case filterQueue <- struct{}{}:
+	default:
+		// a round is already scheduled
+	}
+}
+
+// marktabFilterWorker runs the filters on the pending bookmarks. A round is
+// limited to [marktab.MarktabConfig.FilterDeadline], bookmarks left are
+// filtered in the next round.
+func marktabFilterWorker(input <-chan struct{}) {
+	for range input {
+		deadline := marktab.Config.FilterDeadline
+		if deadline <= 0 {
+			deadline = marktab.DefaultFilterDeadline
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), deadline)
+		applyMarktabFilters(ctx)
+		cancel()
+	}
+}
+
+// applyMarktabFilters runs the marktab filters on the pending bookmarks of
+// the L1 cache. Changes are applied to the caches with [applyFilterChanges],
+// dropped bookmarks are deleted from the caches. Filtered bookmarks are
+// released to the L2 cache with the next backup.
+func applyMarktabFilters(ctx context.Context) {
+	filterPendingMu.Lock()
+	round := maps.Clone(filterPending)
+	filterPendingMu.Unlock()
+	if len(round) == 0 {
+		return
+	}
+
+	bookmarks, err := Cache.bookmarksByURL(ctx, slices.Collect(maps.Keys(round)))
+	if err != nil {
+		log.Error("marktab filters", "err", err)
+		return
+	}
+	defer ScheduleBackupToDisk()
+
+	// bookmarks deleted since they were queued
+	for url, seq := range round {
+		if !slices.ContainsFunc(bookmarks, func(bk *gosuki.Bookmark) bool { return bk.URL == url }) {
+			releaseFiltered(url, seq)
+		}
+	}
+
+	for i, bk := range bookmarks {
+		if ctx.Err() != nil {
+			// bookmarks left stay pending for the next round
+			log.Warn("marktab filters deadline exceeded", "left", len(bookmarks)-i)
+			scheduleMarktabFilters()
+			return
+		}
+
+		orig := *bk
+		orig.Tags = slices.Clone(bk.Tags)
+
+		changed, drop := marktabFilters.Apply(ctx, bk)
+		switch {
+		case drop:
+			log.Info("marktab filter dropped bookmark", "url", bk.URL)
+			synced, err := dropBookmark(bk.URL)
+			if err != nil {
+				log.Error("dropping bookmark", "url", bk.URL, "err", err)
+				continue
+			}
+			if synced {
+				queueHook(hooks.HookJob{Book: bk, Kind: hooks.GlobalDeleteHook})
+			}
+		case changed:
+			if err := applyFilterChanges(&orig, bk); err != nil {
+				log.Error("marktab filter update", "url", bk.URL, "err", err)
+				continue
+			}
+		}
+		releaseFiltered(bk.URL, round[bk.URL])
+	}
+}
+
+// keepPendingFilters adds urls to the next round of filters
+func keepPendingFilters(urls []string) {
+	filterPendingMu.Lock()
+	defer filterPendingMu.Unlock()
+	for _, url := range urls {
+		filterSeq++
+		filterPending[url] = filterSeq
+	}
+}
+
+// releaseFiltered removes a filtered url from the pending bookmarks unless it
+// changed again while the filters were running
+func releaseFiltered(url string, seq uint64) {
+	filterPendingMu.Lock()
+	defer filterPendingMu.Unlock()
+	if filterPending[url] == seq {
+		delete(filterPending, url)
+	}
+}
+
+// filterIsPending reports whether the bookmark `url` waits for the filters
+func filterIsPending(url string) bool {
+	filterPendingMu.Lock()
+	defer filterPendingMu.Unlock()
+	_, pending := filterPending[url]
+	return pending
+}
+
+// applyFilterChanges applies the changes a filter or a global hook made from
+// `orig` to `filtered` on the bookmark in the caches. Only the changed fields are
+// written and tags are added and removed explicitly, so changes synced while
+// the filter was running are kept.
+func applyFilterChanges(orig, filtered *gosuki.Bookmark) error {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	for _, db := range []*DB{Cache.DB, L2Cache.DB} {
+		if db == nil || db.Handle == nil {
+			continue
+		}
+
+		row := RawBookmark{}
+		err := db.Handle.Get(&row, "SELECT * FROM gskbookmarks WHERE URL = ?", filtered.URL)
+		if errors.Is(err, sql.ErrNoRows) {
+			// not synced to this level yet
+			continue
+		} else if err != nil {
+			return DBError{DBName: db.Name, Err: err}
+		}
+
+		bk := row.AsBookmark()
+		if filtered.Title != orig.Title {
+			bk.Title = filtered.Title
+		}
+		if filtered.Desc != orig.Desc {
+			bk.Desc = filtered.Desc
+		}
+		bk.Tags = slices.DeleteFunc(bk.Tags, func(tag string) bool {
+			return slices.Contains(orig.Tags, tag) && !slices.Contains(filtered.Tags, tag)
+		})
+		for _, tag := range filtered.Tags {
+			if !slices.Contains(bk.Tags, tag) {
+				bk.Tags = append(bk.Tags, tag)
+			}
+		}
+
+		if err = db.UpdateBookmark(bk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropBookmark deletes a bookmark from the caches, the disk database follows
+// on the next backup of the L2 cache. It reports whether the bookmark was in
+// the L2 cache, global hooks only saw those.
+func dropBookmark(url string) (synced bool, err error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	for _, db := range []*DB{Cache.DB, L2Cache.DB} {
+		if db == nil || db.Handle == nil {
+			continue
+		}
+		res, err := db.Handle.Exec("DELETE FROM gskbookmarks WHERE URL = ?", url)
+		if err != nil {
+			return false, DBError{DBName: db.Name, Err: err}
+		}
+		if db == L2Cache.DB {
+			n, _ := res.RowsAffected()
+			synced = n > 0
+		}
+	}
+
+	unindexRelated(url)
+	return synced, nil
+}
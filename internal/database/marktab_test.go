@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/hooks"
+	"github.com/blob42/gosuki/pkg/marktab"
+)
+
+func TestMarktabRuns(t *testing.T) {
+	ctx := context.Background()
+	buffer, err := NewBuffer("test_marktab_runs")
+	require.NoError(t, err)
+	defer buffer.Close()
+
+	for i := range 5 {
+		require.NoError(t, buffer.SaveMarktabRun(&marktab.Run{
+			ID:      fmt.Sprintf("run%d", i),
+			Trigger: "archive",
+			URL:     fmt.Sprintf("https://example.com/%d", i),
+			Status:  marktab.RunQueued,
+			Started: int64(100 + i),
+		}))
+	}
+
+	// updates keep the original fields
+	require.NoError(t, buffer.SaveMarktabRun(&marktab.Run{
+		ID:       "run4",
+		Status:   marktab.RunFailed,
+		ExitCode: 2,
+		Attempts: 3,
+		Stderr:   "boom",
+	}))
+
+	result, err := buffer.QueryMarktabRuns(ctx, "", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint(5), result.Total)
+	run := result.Runs[0]
+	assert.Equal(t, "run4", run.ID)
+	assert.Equal(t, "https://example.com/4", run.URL)
+	assert.Equal(t, marktab.RunFailed, run.Status)
+	assert.Equal(t, 2, run.ExitCode)
+	assert.Equal(t, "boom", run.Stderr)
+
+	result, err = buffer.QueryMarktabRuns(ctx, marktab.RunFailed, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), result.Total)
+
+	result, err = buffer.QueryMarktabRuns(ctx, "", "example.com/2", nil)
+	require.NoError(t, err)
+	require.Len(t, result.Runs, 1)
+	assert.Equal(t, "run2", result.Runs[0].ID)
+
+	require.NoError(t, buffer.PruneMarktabRuns(2))
+	result, err = buffer.QueryMarktabRuns(ctx, "", "", &PaginationParams{Page: 1, Size: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Runs, 2)
+	assert.Equal(t, "run4", result.Runs[0].ID)
+	assert.Equal(t, "run3", result.Runs[1].ID)
+}
+
+func TestApplyFilterChanges(t *testing.T) {
+	cache, err := NewDB("test_filter_cache", "", DBTypeCacheDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, cache.InitSchema(context.Background()))
+	defer cache.Close()
+
+	savedL1, savedL2 := Cache.DB, L2Cache.DB
+	Cache.DB, L2Cache.DB = cache, nil
+	defer func() { Cache.DB, L2Cache.DB = savedL1, savedL2 }()
+
+	url := "https://arxiv.org/abs/1234"
+	require.NoError(t, cache.UpsertBookmark(&gosuki.Bookmark{
+		URL:   url,
+		Title: "paper",
+		Tags:  []string{"@doi", "todo"},
+	}))
+
+	orig := &gosuki.Bookmark{URL: url, Title: "paper", Tags: []string{"@doi", "todo"}}
+	filtered := &gosuki.Bookmark{URL: url, Title: "A paper", Tags: []string{"@doi", "ml"}}
+
+	// tag synced while the filter was running
+	require.NoError(t, cache.UpsertBookmark(&gosuki.Bookmark{
+		URL:  url,
+		Tags: []string{"firefox"},
+	}))
+
+	require.NoError(t, applyFilterChanges(orig, filtered))
+
+	bookmarks, err := cache.bookmarksByURL(context.Background(), []string{url})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 1)
+	assert.Equal(t, "A paper", bookmarks[0].Title)
+	assert.ElementsMatch(t, []string{"@doi", "ml", "firefox"}, bookmarks[0].Tags)
+}
+
+func TestMarktabFiltersDeadline(t *testing.T) {
+	cache, err := NewDB("test_filter_deadline_cache", "", DBTypeCacheDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, cache.InitSchema(context.Background()))
+	defer cache.Close()
+
+	saved := Cache.DB
+	Cache.DB = cache
+	defer func() { Cache.DB = saved }()
+	defer clear(filterPending)
+
+	url := "https://example.com/"
+	require.NoError(t, cache.UpsertBookmark(&gosuki.Bookmark{URL: url, Title: "example"}))
+	keepPendingFilters([]string{url})
+
+	// bookmarks left after the deadline wait for the next round
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	applyMarktabFilters(ctx)
+	assert.Contains(t, filterPending, url)
+}
+
+func TestMarktabFilterDropBeforeDisk(t *testing.T) {
+	ctx := context.Background()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	marktabFile := filepath.Join(home, ".config", "gosuki", "marktab")
+	require.NoError(t, os.MkdirAll(filepath.Dir(marktabFile), 0o755))
+	require.NoError(t, os.WriteFile(marktabFile,
+		[]byte("@spam .* | echo '{\"drop\": true}'\n"), 0o644))
+	require.NoError(t, marktab.Reload())
+	t.Cleanup(func() {
+		os.Remove(marktabFile)
+		marktab.Reload()
+	})
+
+	l1, err := NewDB(CacheName, "", DBTypeCacheDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, l1.InitSchema(ctx))
+	defer l1.Close()
+	l2, err := NewDB(L2CacheName, "", DBTypeCacheDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, l2.InitSchema(ctx))
+	defer l2.Close()
+
+	savedL1, savedL2 := Cache.DB, L2Cache.DB
+	Cache.DB, L2Cache.DB = l1, l2
+	savedHooks, savedFilters, savedSync := hooksQueue, filterQueue, syncQueue
+	hooksQueue = make(chan hooks.HookJob, 10)
+	filterQueue = make(chan struct{}, 1)
+	syncQueue = make(chan any, 10)
+	defer func() {
+		Cache.DB, L2Cache.DB = savedL1, savedL2
+		hooksQueue, filterQueue, syncQueue = savedHooks, savedFilters, savedSync
+		clear(filterPending)
+	}()
+
+	buffer, err := NewBuffer("test_filter_drop_buffer")
+	require.NoError(t, err)
+	defer buffer.Close()
+
+	spam := "https://spam.example/"
+	kept := "https://example.com/"
+	require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{URL: spam, Tags: []string{"@spam"}}))
+	require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{URL: kept, Tags: []string{"go"}}))
+
+	buffer.SyncTo(Cache.DB)
+	require.Contains(t, filterPending, spam)
+
+	// a backup before the filters ran does not carry the pending bookmarks
+	Cache.SyncTo(L2Cache.DB)
+	bookmarks, err := l2.bookmarksByURL(ctx, []string{spam, kept})
+	require.NoError(t, err)
+	assert.Empty(t, bookmarks)
+
+	applyMarktabFilters(ctx)
+	assert.Empty(t, filterPending)
+	Cache.SyncTo(L2Cache.DB)
+
+	diskPath := filepath.Join(t.TempDir(), "gosuki.db")
+	require.NoError(t, L2Cache.BackupToDisk(diskPath))
+	disk, err := NewDB("test_filter_drop_disk", diskPath, DBTypeFileDSN, DsnOptions{}).Init()
+	require.NoError(t, err)
+	defer disk.Close()
+
+	bookmarks, err = disk.bookmarksByURL(ctx, []string{spam, kept})
+	require.NoError(t, err)
+	require.Len(t, bookmarks, 1)
+	assert.Equal(t, kept, bookmarks[0].URL)
+
+	close(hooksQueue)
+	var hooked []string
+	for job := range hooksQueue {
+		hooked = append(hooked, job.Book.URL)
+	}
+	assert.Equal(t, []string{kept}, hooked)
+}
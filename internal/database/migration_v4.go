@@ -0,0 +1,45 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+// Performs the database schema migration from version 3 to version 4.
+// This migration adds the `gskhistory` table used by the history module to
+// index visited pages apart from bookmarks.
+func (db *DB) migrateToVersion4() error {
+	log.Debug("DB schema: migrating to v4")
+	tx, err := db.Handle.Begin()
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if _, err = tx.Exec(QCreateHistorySchema); err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Notes are owned by gosuki: they are kept apart from gskbookmarks so that
+// browser syncs never overwrite them. They are keyed by URL and survive the
+// removal of the bookmark.
+const QCreateNotesSchema = `
+	CREATE TABLE IF NOT EXISTS gsknotes (
+		URL TEXT PRIMARY KEY,
+		note TEXT DEFAULT '',
+		modified INTEGER DEFAULT (strftime('%s'))
+	)
+`
+
+const (
+	QUpsertNote = `
+	INSERT INTO gsknotes (URL, note, modified)
+	VALUES (?, ?, ?)
+	ON CONFLICT(URL) DO UPDATE SET
+		note = excluded.note,
+		modified = excluded.modified
+	`
+
+	// Bookmarks with their notes, used by exporters
+	QSelectBookmarksWithNotes = `
+	SELECT gskbookmarks.*, COALESCE(gsknotes.note, '') AS notes
+	FROM gskbookmarks
+	LEFT JOIN gsknotes ON gsknotes.URL = gskbookmarks.URL
+	`
+
+	// Matches bookmarks whose notes contain the first argument of the
+	// format string
+	WhereNotesMatch = `URL IN (SELECT URL FROM gsknotes WHERE note LIKE '%%%[1]s%%')`
+)
+
+// Note is the Markdown note of a bookmark
+type Note struct {
+	URL  string `db:"URL" json:"url"`
+	Text string `db:"note" json:"notes"`
+
+	// unix timestamp of the last edit, zero for bookmarks without note
+	Modified int64 `db:"modified" json:"modified"`
+}
+
+// GetNote returns the note of the bookmark at url. An empty note is
+// returned for bookmarks without one.
+func (db *DB) GetNote(ctx context.Context, url string) (*Note, error) {
+	note := &Note{}
+	err := db.Handle.GetContext(ctx, note,
+		"SELECT URL, note, modified FROM gsknotes WHERE URL = ?", url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Note{URL: url}, nil
+	} else if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+
+	return note, nil
+}
+
+func GetNote(ctx context.Context, url string) (*Note, error) {
+	return DiskDB.GetNote(ctx, url)
+}
+
+// writeNote stores the note, a blank text removes it
+func (db *DB) writeNote(note *Note) error {
+	var err error
+	if strings.TrimSpace(note.Text) == "" {
+		_, err = db.Handle.Exec("DELETE FROM gsknotes WHERE URL = ?", note.URL)
+	} else {
+		_, err = db.Handle.Exec(QUpsertNote, note.URL, note.Text, note.Modified)
+	}
+	if err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	return nil
+}
+
+func (db *DB) hasBookmark(url string) (bool, error) {
+	var count int
+	err := db.Handle.Get(&count,
+		"SELECT COUNT(*) FROM gskbookmarks WHERE URL = ?", url)
+	if err != nil {
+		return false, DBError{DBName: db.Name, Err: err}
+	}
+	return count > 0, nil
+}
+
+// SetNote replaces the note of the bookmark at url in the caches and on
+// disk, a blank text removes it. Notes are kept in their own table, they are
+// not part of the bookmark checksum and are written to every level.
+func SetNote(url, text string) (*Note, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	dbs := []*DB{}
+	var found bool
+	for _, db := range []*DB{Cache.DB, L2Cache.DB, DiskDB} {
+		if db == nil || db.Handle == nil {
+			continue
+		}
+		dbs = append(dbs, db)
+
+		if !found {
+			ok, err := db.hasBookmark(url)
+			if err != nil {
+				return nil, err
+			}
+			found = ok
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("bookmark not found: %s", url)
+	}
+
+	note := &Note{URL: url, Text: text, Modified: time.Now().Unix()}
+	if strings.TrimSpace(text) == "" {
+		note = &Note{URL: url}
+	}
+	for _, db := range dbs {
+		if err := db.writeNote(note); err != nil {
+			return nil, err
+		}
+	}
+
+	return note, nil
+}
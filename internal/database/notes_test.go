@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func TestNotes(t *testing.T) {
+	ctx := context.Background()
+	buffer, err := NewBuffer("test_notes")
+	require.NoError(t, err)
+	defer buffer.Close()
+
+	url := "https://example.com/article"
+	require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{
+		URL:   url,
+		Title: "Article",
+		Desc:  "browser description",
+	}))
+	require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{
+		URL:   "https://example.com/other",
+		Title: "Other",
+	}))
+
+	note, err := buffer.GetNote(ctx, url)
+	require.NoError(t, err)
+	assert.Empty(t, note.Text)
+
+	require.NoError(t, buffer.writeNote(&Note{URL: url, Text: "# Summary\nquasar stuff", Modified: 42}))
+
+	t.Run("browser sync keeps notes", func(t *testing.T) {
+		require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{
+			URL:   url,
+			Title: "Article renamed",
+			Desc:  "new description",
+		}))
+
+		note, err := buffer.GetNote(ctx, url)
+		require.NoError(t, err)
+		assert.Equal(t, "# Summary\nquasar stuff", note.Text)
+		assert.Equal(t, int64(42), note.Modified)
+	})
+
+	t.Run("search matches notes", func(t *testing.T) {
+		var urls []string
+		require.NoError(t, buffer.Handle.Select(&urls, fmt.Sprintf(
+			"SELECT URL FROM gskbookmarks WHERE "+WhereQueryBookmarks,
+			"quasar", "quasar", "quasar",
+		)))
+		assert.Equal(t, []string{url}, urls)
+
+		urls = nil
+		require.NoError(t, buffer.Handle.Select(&urls,
+			"SELECT URL FROM gskbookmarks WHERE "+
+				buildWhereClauseForManyTags("quasar", nil, TagAnd, false)))
+		assert.Equal(t, []string{url}, urls)
+	})
+
+	t.Run("bookmarks with notes", func(t *testing.T) {
+		raws := RawBookmarks{}
+		require.NoError(t, buffer.Handle.Select(&raws,
+			QSelectBookmarksWithNotes+" ORDER BY gskbookmarks.URL"))
+		require.Len(t, raws, 2)
+		assert.Equal(t, "# Summary\nquasar stuff", raws[0].AsBookmark().Notes)
+		assert.Empty(t, raws[1].Notes)
+	})
+
+	t.Run("blank note removes it", func(t *testing.T) {
+		require.NoError(t, buffer.writeNote(&Note{URL: url, Text: "  \n"}))
+		note, err := buffer.GetNote(ctx, url)
+		require.NoError(t, err)
+		assert.Empty(t, note.Text)
+		assert.Zero(t, note.Modified)
+	})
+}
@@ -0,0 +1,154 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// Readable text of bookmarked pages indexed by the fulltext module. Pages are
+// keyed by URL and removed with their bookmark.
+const QCreatePagesSchema = `
+	CREATE TABLE IF NOT EXISTS gskpages (
+		URL TEXT PRIMARY KEY,
+		title TEXT DEFAULT '',
+		content TEXT DEFAULT '',
+		source TEXT DEFAULT '',
+		status TEXT DEFAULT '',
+		error TEXT DEFAULT '',
+		fetched INTEGER DEFAULT 0
+	)
+`
+
+const (
+	QUpsertPage = `
+	INSERT INTO gskpages (URL, title, content, source, status, error, fetched)
+	VALUES (:URL, :title, :content, :source, :status, :error, :fetched)
+	ON CONFLICT(URL) DO UPDATE SET
+		title = excluded.title,
+		content = excluded.content,
+		source = excluded.source,
+		status = excluded.status,
+		error = excluded.error,
+		fetched = excluded.fetched
+	`
+
+	// Bookmarks never indexed first, then the oldest pages due again
+	QSelectPagesToIndex = `
+	SELECT gskbookmarks.URL FROM gskbookmarks
+	LEFT JOIN gskpages ON gskpages.URL = gskbookmarks.URL
+	WHERE (gskbookmarks.URL LIKE 'http://%' OR gskbookmarks.URL LIKE 'https://%')
+	AND (
+		gskpages.URL IS NULL
+		OR (gskpages.status = 'failed' AND gskpages.fetched < ?)
+		OR (gskpages.status = 'ok' AND gskpages.fetched < ?)
+	)
+	ORDER BY gskpages.fetched IS NOT NULL, gskpages.fetched, gskbookmarks.id DESC
+	LIMIT ?
+	`
+
+	// Matches bookmarks whose page text contains the first argument of the
+	// format string
+	WherePagesMatch = `URL IN (SELECT URL FROM gskpages WHERE content LIKE '%%%[1]s%%')`
+)
+
+// Status of indexed pages
+const (
+	PageOK       = "ok"
+	PageFailed   = "failed"
+	PageExcluded = "excluded"
+)
+
+// Page is the readable text of a bookmarked page
+type Page struct {
+	URL     string `db:"URL" json:"url"`
+	Title   string `db:"title" json:"title"`
+	Content string `db:"content" json:"content"`
+
+	// web or archive
+	Source string `db:"source" json:"source"`
+	Status string `db:"status" json:"status"`
+	Error  string `db:"error" json:"error,omitempty"`
+
+	// unix timestamp of the last fetch
+	Fetched int64 `db:"fetched" json:"fetched"`
+}
+
+// UpsertPage inserts or replaces the indexed page
+func (db *DB) UpsertPage(page *Page) error {
+	if _, err := db.Handle.NamedExec(QUpsertPage, page); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// PagesToIndex returns at most `limit` bookmark URLs to index: bookmarks
+// without page, failed pages fetched before `retry` and pages fetched before
+// `refetch`.
+func (db *DB) PagesToIndex(retry, refetch time.Time, limit int) ([]string, error) {
+	urls := []string{}
+	err := db.Handle.Select(&urls, QSelectPagesToIndex, retry.Unix(), refetch.Unix(), limit)
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+	return urls, nil
+}
+
+// PageURLs returns the URLs of the pages with this status
+func (db *DB) PageURLs(status string) ([]string, error) {
+	urls := []string{}
+	if err := db.Handle.Select(&urls, "SELECT URL FROM gskpages WHERE status = ?", status); err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+	return urls, nil
+}
+
+// DeletePage removes the indexed page of the URL
+func (db *DB) DeletePage(url string) error {
+	if _, err := db.Handle.Exec("DELETE FROM gskpages WHERE URL = ?", url); err != nil {
+		return DBError{DBName: db.Name, Err: err}
+	}
+	return nil
+}
+
+// PrunePages removes the pages of deleted bookmarks and returns their number
+func (db *DB) PrunePages() (int64, error) {
+	res, err := db.Handle.Exec(
+		"DELETE FROM gskpages WHERE URL NOT IN (SELECT URL FROM gskbookmarks)")
+	if err != nil {
+		return 0, DBError{DBName: db.Name, Err: err}
+	}
+	removed, _ := res.RowsAffected()
+	return removed, nil
+}
+
+// GetPage returns the indexed page of the URL from disk
+func GetPage(ctx context.Context, url string) (*Page, error) {
+	page := &Page{}
+	err := DiskDB.Handle.GetContext(ctx, page, "SELECT * FROM gskpages WHERE URL = ?", url)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func TestPages(t *testing.T) {
+	buffer, err := NewBuffer("test_pages")
+	require.NoError(t, err)
+	defer buffer.Close()
+
+	for _, u := range []string{
+		"https://example.com/new",
+		"https://example.com/indexed",
+		"https://example.com/stale",
+		"https://example.com/failed",
+		"file:///home/user/doc.html",
+	} {
+		require.NoError(t, buffer.UpsertBookmark(&gosuki.Bookmark{URL: u, Title: "page"}))
+	}
+
+	now := time.Now()
+	old := now.Add(-48 * time.Hour).Unix()
+	pages := []*Page{
+		{URL: "https://example.com/indexed", Content: "photosynthesis in leaves", Status: PageOK, Fetched: now.Unix()},
+		{URL: "https://example.com/stale", Content: "old text", Status: PageOK, Fetched: old - 60},
+		{URL: "https://example.com/failed", Status: PageFailed, Error: "404", Fetched: old},
+		{URL: "https://example.com/deleted", Content: "photosynthesis", Status: PageOK, Fetched: old},
+	}
+	for _, p := range pages {
+		require.NoError(t, buffer.UpsertPage(p))
+	}
+
+	t.Run("pages to index", func(t *testing.T) {
+		day := now.Add(-24 * time.Hour)
+		urls, err := buffer.PagesToIndex(day, day, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"https://example.com/new",
+			"https://example.com/stale",
+			"https://example.com/failed",
+		}, urls)
+
+		// no refetch of indexed pages
+		urls, err = buffer.PagesToIndex(day, time.Time{}, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/new", "https://example.com/failed"}, urls)
+
+		urls, err = buffer.PagesToIndex(day, day, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/new"}, urls)
+	})
+
+	t.Run("search matches page text", func(t *testing.T) {
+		var urls []string
+		require.NoError(t, buffer.Handle.Select(&urls, fmt.Sprintf(
+			"SELECT URL FROM gskbookmarks WHERE "+WhereQueryBookmarks,
+			"photosynthesis", "photosynthesis", "photosynthesis",
+		)))
+		assert.Equal(t, []string{"https://example.com/indexed"}, urls)
+
+		urls = nil
+		require.NoError(t, buffer.Handle.Select(&urls,
+			"SELECT URL FROM gskbookmarks WHERE "+
+				buildWhereClauseForManyTags("photosynthesis", nil, TagAnd, false)))
+		assert.Equal(t, []string{"https://example.com/indexed"}, urls)
+	})
+
+	t.Run("prune pages of deleted bookmarks", func(t *testing.T) {
+		removed, err := buffer.PrunePages()
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, removed)
+
+		urls, err := buffer.PageURLs(PageFailed)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/failed"}, urls)
+
+		require.NoError(t, buffer.DeletePage("https://example.com/failed"))
+		urls, err = buffer.PageURLs(PageFailed)
+		require.NoError(t, err)
+		assert.Empty(t, urls)
+	})
+}
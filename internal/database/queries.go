@@ -31,8 +31,9 @@ import (
 )
 
 const (
-	WhereQueryBookmarks = `
-	URL like '%%%s%%' OR metadata like '%%%s%%' OR LOWER(tags) like '%%%s%%'
+	// Notes and page text are matched by the plain text searches
+	WhereQueryBookmarks = WhereNotesMatch + ` OR ` + WherePagesMatch + `
+	OR URL like '%%%[1]s%%' OR metadata like '%%%s%%' OR LOWER(tags) like '%%%s%%'
 	`
 
 	WhereQueryBookmarksFuzzy = `
@@ -40,7 +41,8 @@ const (
 	`
 
 	WhereQueryBookmarksByTag = `
-		(URL LIKE '%%%s%%' OR metadata LIKE '%%%s%%') AND LOWER(tags) LIKE '%%%s%%'
+		(` + WhereNotesMatch + ` OR ` + WherePagesMatch + ` OR URL LIKE '%%%[1]s%%' OR metadata LIKE '%%%s%%')
+		AND LOWER(tags) LIKE '%%%s%%'
 	`
 	WhereQueryBookmarksByTagFuzzy = `
 		(fuzzy('%s', URL) OR fuzzy('%s', metadata)) AND LOWER(tags) LIKE '%%%s%%'
@@ -120,7 +122,7 @@ func QueryBookmarksByTags(
 	log.Trace(whereClause)
 
 	sqlQuery := fmt.Sprintf(
-		"SELECT URL, metadata, tags, module FROM gskbookmarks WHERE %s "+QQueryPaginate,
+		"SELECT id, URL, metadata, tags, flags, module FROM gskbookmarks WHERE %s "+QQueryPaginate,
 		whereClause,
 		pagination.Size,
 		(pagination.Page-1)*pagination.Size,
@@ -295,6 +297,28 @@ func ListBookmarks(
 	return &QueryResult{rawBooks.AsBookmarks(), total}, nil
 }
 
+// GetBookmark returns the bookmark with the given row id from disk
+func GetBookmark(ctx context.Context, id uint64) (*gosuki.Bookmark, error) {
+	raw := RawBookmark{}
+	err := DiskDB.Handle.GetContext(ctx, &raw, "SELECT * FROM gskbookmarks WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+
+	return raw.AsBookmark(), nil
+}
+
+// GetBookmarkByURL returns the bookmark with the given URL from disk
+func GetBookmarkByURL(ctx context.Context, url string) (*gosuki.Bookmark, error) {
+	raw := RawBookmark{}
+	err := DiskDB.Handle.GetContext(ctx, &raw, "SELECT * FROM gskbookmarks WHERE URL = ?", url)
+	if err != nil {
+		return nil, err
+	}
+
+	return raw.AsBookmark(), nil
+}
+
 // CountTotalBookmarks counts total bookmarks from disk
 func CountTotalBookmarks(ctx context.Context) (uint, error) {
 	return DiskDB.TotalBookmarks(ctx)
@@ -328,7 +352,7 @@ func buildSelectQuery(
 	}
 
 	sqlPrelude := `
-		SELECT URL, metadata, tags, module
+		SELECT id, URL, metadata, tags, flags, module
 		FROM gskbookmarks
 		WHERE 
 	`
@@ -402,7 +426,7 @@ func buildWhereClauseForManyTags(
 			conditions = append(
 				conditions,
 				fmt.Sprintf(
-					"URL like '%%%s%%' OR metadata like '%%%s%%'",
+					"URL like '%%%s%%' OR metadata like '%%%s%%' OR "+WhereNotesMatch+" OR "+WherePagesMatch,
 					trimmedQuery,
 					trimmedQuery,
 				),
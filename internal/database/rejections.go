@@ -0,0 +1,94 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Suggested tags rejected for a bookmark, keyed by URL. They are not
+// suggested again for this bookmark.
+const QCreateTagRejectionsSchema = `
+	CREATE TABLE IF NOT EXISTS gsktagrejections (
+		URL TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		modified INTEGER DEFAULT (strftime('%s')),
+		PRIMARY KEY (URL, tag)
+	)
+`
+
+const QInsertTagRejection = `
+	INSERT INTO gsktagrejections (URL, tag, modified)
+	VALUES (?, ?, ?)
+	ON CONFLICT(URL, tag) DO NOTHING
+`
+
+// TagRejections returns the rejected tags by bookmark URL
+func (db *DB) TagRejections(ctx context.Context) (map[string][]string, error) {
+	var rows []struct {
+		URL string `db:"URL"`
+		Tag string `db:"tag"`
+	}
+	err := db.Handle.SelectContext(ctx, &rows,
+		"SELECT URL, tag FROM gsktagrejections ORDER BY URL, tag")
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+
+	res := map[string][]string{}
+	for _, row := range rows {
+		res[row.URL] = append(res[row.URL], row.Tag)
+	}
+	return res, nil
+}
+
+func TagRejections(ctx context.Context) (map[string][]string, error) {
+	return DiskDB.TagRejections(ctx)
+}
+
+// RejectTag records that the suggested tag was rejected for the bookmark at
+// url. Like notes, rejections are written to the caches and on disk.
+func RejectTag(url, tag string) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return fmt.Errorf("no tag given")
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	modified := time.Now().Unix()
+	for _, db := range []*DB{Cache.DB, L2Cache.DB, DiskDB} {
+		if db == nil || db.Handle == nil {
+			continue
+		}
+		if _, err := db.Handle.Exec(QInsertTagRejection, url, tag, modified); err != nil {
+			return DBError{DBName: db.Name, Err: err}
+		}
+	}
+
+	return nil
+}
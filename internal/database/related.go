@@ -0,0 +1,182 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/related"
+)
+
+// The related bookmarks index is saved next to the database file
+const RelatedIndexFile = "related.idx"
+
+var (
+	// Similarity index of the bookmarks. It is updated every time a module
+	// syncs to the cache and saved along with the database.
+	RelatedIndex *related.Index
+
+	relatedDirty atomic.Bool
+)
+
+func RelatedIndexPath() (string, error) {
+	dbpath, err := utils.ExpandOnly(config.DBPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(dbpath), RelatedIndexFile), nil
+}
+
+func (db *DB) allBookmarks(ctx context.Context) ([]*gosuki.Bookmark, error) {
+	rawBooks := RawBookmarks{}
+	err := db.Handle.SelectContext(ctx, &rawBooks, "SELECT * FROM gskbookmarks")
+	if err != nil {
+		return nil, DBError{DBName: db.Name, Err: err}
+	}
+	return rawBooks.AsBookmarks(), nil
+}
+
+// bookmarksByURL returns the bookmarks stored under the given urls
+func (db *DB) bookmarksByURL(ctx context.Context, urls []string) ([]*gosuki.Bookmark, error) {
+	const chunk = 500
+
+	var result []*gosuki.Bookmark
+	for batch := range slices.Chunk(urls, chunk) {
+		query, args, err := sqlx.In("SELECT * FROM gskbookmarks WHERE URL IN (?)", batch)
+		if err != nil {
+			return nil, err
+		}
+
+		rawBooks := RawBookmarks{}
+		if err = db.Handle.SelectContext(ctx, &rawBooks, query, args...); err != nil {
+			return nil, DBError{DBName: db.Name, Err: err}
+		}
+		result = append(result, rawBooks.AsBookmarks()...)
+	}
+
+	return result, nil
+}
+
+// LoadRelatedIndex reads the saved related bookmarks index. If it is missing
+// or corrupted the index is rebuilt from the disk database.
+func LoadRelatedIndex(ctx context.Context) (*related.Index, error) {
+	idx, _, err := loadRelatedIndex(ctx)
+	return idx, err
+}
+
+// loadRelatedIndex also returns true when the index was rebuilt
+func loadRelatedIndex(ctx context.Context) (*related.Index, bool, error) {
+	path, err := RelatedIndexPath()
+	if err != nil {
+		return nil, false, err
+	}
+
+	idx, err := related.Load(path)
+	if err == nil {
+		return idx, false, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		log.Warnf("rebuilding related index: %s", err)
+	}
+
+	bookmarks, err := DiskDB.allBookmarks(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	idx = related.NewIndex()
+	idx.Add(bookmarks...)
+	return idx, true, nil
+}
+
+// StartRelatedIndex loads the related bookmarks index, it is then updated on
+// every sync to the cache. Only the daemon keeps the index up to date, other
+// commands use [LoadRelatedIndex].
+func StartRelatedIndex(ctx context.Context) {
+	idx, rebuilt, err := loadRelatedIndex(ctx)
+	if err != nil {
+		log.Errorf("loading related index: %s", err)
+		idx, rebuilt = related.NewIndex(), false
+	}
+	RelatedIndex = idx
+	relatedDirty.Store(rebuilt)
+}
+
+// indexRelated indexes the bookmarks of the buffer as they are stored in the
+// cache, a url bookmarked in several modules is indexed with the merged tags.
+func (src *DB) indexRelated() {
+	if RelatedIndex == nil {
+		return
+	}
+
+	ctx := context.Background()
+	var urls []string
+	err := src.Handle.SelectContext(ctx, &urls, "SELECT URL FROM gskbookmarks")
+	if err != nil {
+		log.Errorf("indexing related bookmarks: %s", err)
+		return
+	}
+
+	bookmarks, err := Cache.bookmarksByURL(ctx, urls)
+	if err != nil {
+		log.Errorf("indexing related bookmarks: %s", err)
+		return
+	}
+
+	RelatedIndex.Add(bookmarks...)
+	relatedDirty.Store(true)
+}
+
+// unindexRelated removes a deleted bookmark from the related index
+func unindexRelated(url string) {
+	if RelatedIndex == nil {
+		return
+	}
+
+	RelatedIndex.Remove(url)
+	relatedDirty.Store(true)
+}
+
+func saveRelatedIndex() {
+	if RelatedIndex == nil || !relatedDirty.Swap(false) {
+		return
+	}
+
+	path, err := RelatedIndexPath()
+	if err == nil {
+		err = RelatedIndex.Save(path)
+	}
+	if err != nil {
+		log.Errorf("saving related index: %s", err)
+	}
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/hooks"
+	"github.com/blob42/gosuki/pkg/related"
+)
+
+func TestIndexRelated(t *testing.T) {
+	cache, err := NewDB("test_related_cache", "", DBTypeCacheDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, cache.InitSchema(context.Background()))
+	defer cache.Close()
+
+	saved := Cache.DB
+	Cache.DB = cache
+	defer func() { Cache.DB = saved }()
+
+	RelatedIndex = related.NewIndex()
+	defer func() { RelatedIndex = nil }()
+
+	// updated bookmarks are sent to the global hooks
+	savedHooks := hooksQueue
+	hooksQueue = make(chan hooks.HookJob, 10)
+	defer func() { hooksQueue = savedHooks }()
+
+	buffer, err := NewBuffer("test_related")
+	require.NoError(t, err)
+	defer buffer.Close()
+
+	for _, bk := range []*gosuki.Bookmark{
+		{URL: "https://go.dev/blog/generics", Title: "Generics in Go", Tags: []string{"golang"}},
+		{URL: "https://go.dev/doc/effective_go", Title: "Effective Go", Tags: []string{"golang"}},
+		{URL: "https://example.com/", Title: "Example"},
+	} {
+		require.NoError(t, buffer.UpsertBookmark(bk))
+	}
+
+	require.NoError(t, buffer.SyncToCache())
+	assert.Equal(t, 3, RelatedIndex.Len())
+	assert.True(t, relatedDirty.Load())
+
+	res := RelatedIndex.Related("https://go.dev/blog/generics", 0)
+	require.Len(t, res, 1)
+	assert.Equal(t, "https://go.dev/doc/effective_go", res[0].URL)
+
+	t.Run("merged cache rows", func(t *testing.T) {
+		other, err := NewBuffer("test_related_other")
+		require.NoError(t, err)
+		defer other.Close()
+
+		for _, bk := range []*gosuki.Bookmark{
+			{URL: "https://example.com/", Title: "Example", Tags: []string{"reference"}},
+			{URL: "https://www.iana.org/", Title: "IANA", Tags: []string{"reference"}},
+		} {
+			require.NoError(t, other.UpsertBookmark(bk))
+		}
+		require.NoError(t, other.SyncToCache())
+
+		// the first buffer syncs again without the tag of the other module
+		require.NoError(t, buffer.SyncToCache())
+
+		res := RelatedIndex.Related("https://www.iana.org/", 0)
+		require.Len(t, res, 1)
+		assert.Equal(t, "https://example.com/", res[0].URL)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		unindexRelated("https://go.dev/doc/effective_go")
+		assert.Empty(t, RelatedIndex.Related("https://go.dev/blog/generics", 0))
+	})
+}
@@ -98,6 +98,7 @@ type RawBookmarks []*RawBookmark
 
 func (raw RawBookmark) AsBookmark() *gosuki.Bookmark {
 	return &gosuki.Bookmark{
+		ID:       raw.ID,
 		URL:      raw.URL,
 		Title:    raw.Metadata,
 		Tags:     tagsFromString(raw.Tags, TagSep).Get(),
@@ -105,6 +106,8 @@ func (raw RawBookmark) AsBookmark() *gosuki.Bookmark {
 		Module:   raw.Module,
 		Modified: raw.Modified,
 		Xhsum:    raw.XHSum,
+		Flags:    gosuki.Flags(raw.Flags),
+		Notes:    raw.Notes,
 	}
 }
 
@@ -129,7 +132,7 @@ type RawBookmark struct {
 	// Last modified
 	Modified uint64
 
-	// kept for buku compat, not used for now
+	// reading state and favorite, see [gosuki.Flags]
 	Flags int
 
 	Module string
@@ -142,4 +145,7 @@ type RawBookmark struct {
 
 	// Node that made the change
 	NodeID UUID `db:"node_id"`
+
+	// Joined from gsknotes, see [QSelectBookmarksWithNotes]
+	Notes string
 }
@@ -37,9 +37,15 @@ import (
 	  - Added version column to gskbookmarks table
 	  - Added node_id column to gskbookmarks table
 	  - Created sync_nodes table for node synchronization management
+  - Version 4: Added gskhistory table for the history module
+  - Version 5: Added gsknotes table for bookmark notes
+  - Version 6: Added gskmarktabruns table for the marktab run history
+  - Version 7: Added gskarchives table for the page snapshots
+  - Version 8: Added gskpages table for the full-text index of pages
+  - Version 9: Added gsktagrejections table for the rejected tag suggestions
 */
 
-const CurrentSchemaVersion = 3
+const CurrentSchemaVersion = 9
 
 const (
 
@@ -225,6 +231,36 @@ func checkDBVersion(db *DB) error {
 					return err
 				}
 				version = 3
+			case 3:
+				if err = db.migrateToVersion4(); err != nil {
+					return err
+				}
+				version = 4
+			case 4:
+				if err = db.migrateToVersion5(); err != nil {
+					return err
+				}
+				version = 5
+			case 5:
+				if err = db.migrateToVersion6(); err != nil {
+					return err
+				}
+				version = 6
+			case 6:
+				if err = db.migrateToVersion7(); err != nil {
+					return err
+				}
+				version = 7
+			case 7:
+				if err = db.migrateToVersion8(); err != nil {
+					return err
+				}
+				version = 8
+			case 8:
+				if err = db.migrateToVersion9(); err != nil {
+					return err
+				}
+				version = 9
 			}
 		}
 	}
@@ -253,6 +289,36 @@ func (db *DB) InitSchema(ctx context.Context) error {
 		return DBError{DBName: db.Name, Err: err}
 	}
 
+	if _, err = tx.ExecContext(ctx, QCreateHistorySchema); err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if _, err = tx.ExecContext(ctx, QCreateNotesSchema); err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if _, err = tx.ExecContext(ctx, QCreateMarktabRunsSchema); err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if _, err = tx.ExecContext(ctx, QCreateArchivesSchema); err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if _, err = tx.ExecContext(ctx, QCreatePagesSchema); err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
+	if _, err = tx.ExecContext(ctx, QCreateTagRejectionsSchema); err != nil {
+		tx.Rollback()
+		return DBError{DBName: db.Name, Err: err}
+	}
+
 	if _, err = tx.ExecContext(ctx, QCreateView); err != nil {
 		tx.Rollback()
 		return DBError{DBName: db.Name, Err: err}
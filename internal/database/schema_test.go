@@ -34,7 +34,7 @@ func TestSchemaInitialization(t *testing.T) {
 	require.Equal(t, CurrentSchemaVersion, version, "schema version mismatch")
 
 	// Verify that the required tables exist
-	tables := []string{"gskbookmarks"}
+	tables := []string{"gskbookmarks", "gskhistory", "gsknotes", "gskmarktabruns", "gskarchives", "gskpages", "gsktagrejections"}
 	for _, table := range tables {
 		var name string
 		err = db.Handle.QueryRow(fmt.Sprintf(
@@ -67,15 +67,26 @@ import (
 
 	"github.com/blob42/gosuki"
 	"github.com/blob42/gosuki/hooks"
+	"github.com/blob42/gosuki/pkg/archive"
 	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/marktab"
 )
 
 var (
 	diskDBmu    sync.Mutex
 	cacheMu     sync.Mutex
 	SyncTrigger = atomic.Bool{}
+
+	// incremented every time the cache is saved to disk
+	diskVersion atomic.Uint64
 )
 
+// DiskVersion changes every time the cache is saved to the disk database.
+// Data derived from the disk database is stale when it changed.
+func DiskVersion() uint64 {
+	return diskVersion.Load()
+}
+
 /*
 SyncTo synchronizes bookmarks from the source database to the destination
 database using the current Lamport clock value.
@@ -112,7 +123,8 @@ It performs the following steps:
  2. Attempts to insert each entry into dst's gskbookmarks table
  3. For existing entries (due to URL constraints), captures their hashes and
     processes them in a second transaction for potential updates
- 4. Updates existing entries only if there are changes in metadata, tags, or description
+ 4. Updates existing entries only if there are changes in metadata, tags,
+    description or flags
  5. Commits transactions for both insert and update phases
  6. If dst is a memcache, schedules a disk backup after completion
 
@@ -129,9 +141,13 @@ Behavior:
 - When syncing to L2 cache, increments the version field on successful inserts
 - Merges tags from both source and destination when updating existing entries
 - Normalizes merged tags by sorting them alphabetically
-- Only updates entries when there are actual changes in metadata, tags, or
-description
+- Merges the reading state and favorite flags of the source into the
+destination, see [gosuki.Flags.Merge]
+- Only updates entries when there are actual changes in metadata, tags,
+description or flags
 - Schedules disk backup when syncing to memcache (CacheName)
+- Bookmarks waiting for the marktab filters are not synced to the L2 cache
+and their global hooks are held until they are filtered
 - Uses Lamport clock for p2p synchronization to maintain causal ordering
 */
 func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
@@ -140,6 +156,13 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 	var isSqlErr bool
 	var existingUrls = make(map[uint64]*RawBookmark)
 
+	// inserted and updated urls
+	var changed []string
+
+	// global hooks of bookmarks changed in the L1 cache wait for the marktab
+	// filters, they run when the filtered bookmark reaches the L2 cache
+	holdHooks := dst.Name == CacheName && marktabFiltersEnabled()
+
 	log.Debugf("syncing <%s> to <%s>", src.Name, dst.Name)
 	cacheMu.Lock()
 	defer cacheMu.Unlock()
@@ -199,7 +222,7 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			?,
 			CASE WHEN ? != '' THEN ? ELSE desc END,
 			strftime('%s'),
-			?,
+			(flags & ~?) | ?,
 			?,
 			?,
 			?,
@@ -232,7 +255,7 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 	}
 
 	getDstTagsStmt, err := dst.Handle.Preparex(
-		`SELECT tags FROM gskbookmarks WHERE url=? LIMIT 1`,
+		`SELECT tags, flags FROM gskbookmarks WHERE url=? LIMIT 1`,
 	)
 
 	// Start syncing all entries from source table
@@ -246,6 +269,11 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			continue
 		}
 
+		// bookmarks waiting for the marktab filters stay in the L1 cache
+		if dst.Name == L2CacheName && filterIsPending(scan.URL) {
+			continue
+		}
+
 		// Try to insert to row in dst table
 		_, err = dstTx.Stmtx(tryInsertDstRow).Exec(
 			scan.URL,
@@ -259,6 +287,7 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 				scan.Metadata,
 				scan.Tags,
 				scan.Desc,
+				gosuki.Flags(scan.Flags),
 			),
 			remoteClock,
 			scan.NodeID,
@@ -274,6 +303,10 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			continue
 		}
 
+		if err == nil {
+			changed = append(changed, scan.URL)
+		}
+
 		// Record already existing bookmarks in `dst` then proceed to UPDATE.
 		if isSqlErr && sqlite3Err.Code == sqlite3.ErrConstraint {
 
@@ -291,10 +324,10 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			// insertion success on l2 cache, update clock
 		} else if err == nil && dst.Name == L2CacheName {
 			log.Trace("inserted", "url", scan.URL, "tags", scan.Tags)
-			hooksQueue <- hooks.HookJob{
+			queueHook(hooks.HookJob{
 				Book: scan.AsBookmark(),
 				Kind: hooks.GlobalInsertHook,
-			}
+			})
 
 			_, err = dstTx.Exec("UPDATE gskbookmarks SET version = ? WHERE URL = ?",
 				Clock.Tick(remoteClock), scan.URL)
@@ -318,9 +351,10 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 	// Loop performing the update for each existing bookmark
 	for hash, scan := range existingUrls {
 		var tags string
+		var dstFlags gosuki.Flags
 		//log.Debugf("updating existing %s", scan.Url)
 
-		if err = dstTx.Stmtx(getDstTagsStmt).Get(&tags, scan.URL); err != nil {
+		if err = dstTx.Stmtx(getDstTagsStmt).QueryRow(scan.URL).Scan(&tags, &dstFlags); err != nil {
 			log.Error("get tags query", "err", err)
 		}
 
@@ -340,7 +374,9 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			newTags.Add(k)
 		}
 		newTagsStr := newTags.Sort().StringWrap()
-		newHash := xhsum(scan.URL, scan.Metadata, newTagsStr, scan.Desc)
+		srcFlags := gosuki.Flags(scan.Flags)
+		newFlags := dstFlags.Merge(srcFlags)
+		newHash := xhsum(scan.URL, scan.Metadata, newTagsStr, scan.Desc, newFlags)
 
 		if strconv.FormatUint(hash, 10) == newHash {
 			continue
@@ -358,7 +394,8 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			newTagsStr,
 			scan.Desc,
 			scan.Desc,
-			0, //flags
+			srcFlags.Mask(),
+			srcFlags,
 			scan.Module,
 			newHash,
 			clock,
@@ -370,16 +407,20 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 			log.Errorf("%s: %s", err, scan.URL)
 			// update success
 		} else {
+			changed = append(changed, scan.URL)
 			log.Trace("updated", "url", scan.URL, "tags", newTagsStr)
-			hooksQueue <- hooks.HookJob{
-				Book: &gosuki.Bookmark{
-					URL:    scan.URL,
-					Title:  scan.Metadata,
-					Tags:   newTags.tags,
-					Desc:   scan.Desc,
-					Module: scan.Module,
-				},
-				Kind: hooks.GlobalUpdateHook,
+			if !holdHooks {
+				queueHook(hooks.HookJob{
+					Book: &gosuki.Bookmark{
+						URL:    scan.URL,
+						Title:  scan.Metadata,
+						Tags:   newTags.tags,
+						Desc:   scan.Desc,
+						Module: scan.Module,
+						Flags:  newFlags,
+					},
+					Kind: hooks.GlobalUpdateHook,
+				})
 			}
 		}
 
@@ -394,6 +435,7 @@ func (src *DB) SyncToClock(dst *DB, remoteClock uint64) {
 
 	// If we are syncing to memcache, schedule a write to disk
 	if dst.Name == CacheName {
+		queueMarktabFilters(changed)
 		ScheduleBackupToDisk()
 	}
 }
@@ -403,6 +445,15 @@ var (
 	hooksQueue chan hooks.HookJob
 )
 
+// queueHook sends a job to the hooks scheduler. Jobs are dropped when the
+// schedulers are not started, as in tests using the caches directly.
+func queueHook(job hooks.HookJob) {
+	if hooksQueue == nil {
+		return
+	}
+	hooksQueue <- job
+}
+
 // cacheSyncScheduler starts a scheduler that debounces cache sync operations to
 // disk. it uses a two-level caching strategy: first syncing the main cache to
 // an l2 cache, then backing up the l2 cache to disk. the scheduler processes
@@ -447,7 +498,9 @@ func cacheSyncScheduler(input <-chan any) {
 					log.Fatalf("failed to sync l2 cache to disk: %s", err)
 				} else {
 					SyncTrigger.Store(true)
+					diskVersion.Add(1)
 				}
+				saveRelatedIndex()
 
 				// empty the queue
 				for len(queue) > 0 {
@@ -458,18 +511,39 @@ func cacheSyncScheduler(input <-chan any) {
 	}
 }
 
+// writeHookedBookmark saves the changes global hooks made to a bookmark in the
+// caches, they reach the disk with the next backup. Hooks run while syncs hold
+// cacheMu and wait on the hooks queue, so the changes are applied outside of
+// the hooks scheduler.
+func writeHookedBookmark(orig, changed *gosuki.Bookmark) error {
+	go func() {
+		if err := applyFilterChanges(orig, changed); err != nil {
+			log.Error("saving hooked bookmark", "url", changed.URL, "err", err)
+			return
+		}
+		ScheduleBackupToDisk()
+	}()
+	return nil
+}
+
 func ScheduleBackupToDisk() {
+	queue := syncQueue
 	go func() {
 		log.Debug("received sync to disk request")
-		syncQueue <- true
+		queue <- true
 	}()
 }
 
 func startSchedulers() {
 	syncQueue = make(chan any)
 	hooksQueue = make(chan hooks.HookJob, 100)
+	filterQueue = make(chan struct{}, 1)
+	hooks.SetBookmarkWriter(writeHookedBookmark)
 	go cacheSyncScheduler(syncQueue)
 	go hooks.HooksScheduler(hooksQueue)
+	go marktabFilterWorker(filterQueue)
+	marktab.SetRunStore(marktabRunStore{})
+	archive.SetStore(archiveStore{})
 }
 
 // BackupToDisk copies the `src` database contents to a file on disk.
@@ -640,9 +714,17 @@ func (src *DB) SyncToCache() error {
 	if empty || (isSQL3Err && sql3err.Code == sqlite3.ErrError) {
 		log.Debugf("cache is empty, copying <%s> to <%s>", src.Name, CacheName)
 		src.CopyTo(Cache.DB, "main", "main")
+
+		var urls []string
+		if err = src.Handle.Select(&urls, "SELECT URL FROM gskbookmarks"); err != nil {
+			log.Errorf("listing copied bookmarks: %s", err)
+		}
+		queueMarktabFilters(urls)
 	} else {
 		log.Debugf("syncing <%s> to cache", src.Name)
 		src.SyncTo(Cache.DB)
 	}
+
+	src.indexRelated()
 	return nil
 }
@@ -6,12 +6,15 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
 )
 
 var testBookmarks = []RawBookmark{
@@ -124,6 +127,7 @@ func TestSyncToCache(t *testing.T) {
 			bm.Title,
 			NewTags(bm.Tags, TagSep).PreSanitize().Sort().String(true),
 			bm.Desc,
+			bm.Flags,
 		)
 		err := buffer.UpsertBookmark(&bm)
 		require.NoError(t, err)
@@ -173,6 +177,7 @@ func TestSyncToCache(t *testing.T) {
 			bm.Title,
 			tagStr,
 			bm.Desc,
+			bm.Flags,
 		)
 		err := buffer.UpsertBookmark(&bm)
 		require.NoError(t, err)
@@ -220,6 +225,7 @@ func TestSyncToCache(t *testing.T) {
 			bm.Title,
 			tagStr,
 			bm.Desc,
+			bm.Flags,
 		)
 		err := buffer.UpsertBookmark(&bm)
 		require.NoError(t, err)
@@ -287,6 +293,7 @@ func TestSyncToDisk(t *testing.T) {
 				"title"+strconv.Itoa(i),
 				"tag"+strconv.Itoa(i),
 				"description"+strconv.Itoa(i),
+				0,
 			),
 		)
 		if err != nil {
@@ -370,6 +377,7 @@ func getCache(t *testing.T, name string) *DB {
 				rbk.Metadata,
 				rbk.Tags,
 				rbk.Desc,
+				gosuki.Flags(rbk.Flags),
 			),
 			clock,
 		)
@@ -409,3 +417,44 @@ func setupSyncToDiskDBs(t *testing.T) (*DB, *DB) {
 	})
 	return srcDB, dstDB
 }
+
+func TestWriteHookedBookmark(t *testing.T) {
+	ctx := context.Background()
+	l1, err := NewDB("test_hooked_l1", "", DBTypeCacheDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, l1.InitSchema(ctx))
+	defer l1.Close()
+	l2, err := NewDB("test_hooked_l2", "", DBTypeCacheDSN).Init()
+	require.NoError(t, err)
+	require.NoError(t, l2.InitSchema(ctx))
+	defer l2.Close()
+
+	savedL1, savedL2 := Cache.DB, L2Cache.DB
+	Cache.DB, L2Cache.DB = l1, l2
+	defer func() { Cache.DB, L2Cache.DB = savedL1, savedL2 }()
+
+	url := "https://github.com/blob42/gosuki"
+	for _, db := range []*DB{l1, l2} {
+		require.NoError(t, db.UpsertBookmark(&gosuki.Bookmark{
+			URL:   url,
+			Title: "gosuki",
+			Tags:  []string{"todo", "go"},
+		}))
+	}
+
+	orig := &gosuki.Bookmark{URL: url, Title: "gosuki", Tags: []string{"todo", "go"}}
+	hooked := &gosuki.Bookmark{URL: url, Title: "gosuki", Tags: []string{"go", "dev"}}
+	require.NoError(t, writeHookedBookmark(orig, hooked))
+
+	for _, db := range []*DB{l1, l2} {
+		require.Eventually(t, func() bool {
+			bookmarks, err := db.bookmarksByURL(ctx, []string{url})
+			if err != nil || len(bookmarks) != 1 {
+				return false
+			}
+			tags := bookmarks[0].Tags
+			slices.Sort(tags)
+			return slices.Equal([]string{"dev", "go"}, tags)
+		}, time.Second, 10*time.Millisecond, db.Name)
+	}
+}
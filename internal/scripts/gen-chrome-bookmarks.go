@@ -89,6 +89,7 @@ const QuantKnob = 5000
 
 type Bookmark struct {
 	DateAdded    string            `json:"date_added"`
+	GUID         string            `json:"guid,omitempty"`
 	ID           string            `json:"id"`
 	MetaInfo     map[string]string `json:"meta_info,omitempty"`
 	Name         string            `json:"name"`
@@ -98,6 +99,24 @@ type Bookmark struct {
 	DateModified string            `json:"date_modified,omitempty"`
 }
 
+// ids 1 and 2 are used by the root folders
+var lastID = 2
+
+func nextID() string {
+	lastID++
+	return fmt.Sprintf("%d", lastID)
+}
+
+func randomGUID() string {
+	return fmt.Sprintf("%08x-%04x-4%03x-%04x-%012x",
+		rand.Uint32(),
+		rand.Intn(1<<16),
+		rand.Intn(1<<12),
+		rand.Intn(1<<14)|0x8000,
+		rand.Int63n(1<<48),
+	)
+}
+
 func randomString(n int) string {
 	letters := "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
 	b := make([]byte, n)
@@ -110,7 +129,8 @@ func randomString(n int) string {
 func randomBookmark() Bookmark {
 	bookmark := Bookmark{
 		DateAdded: fmt.Sprintf("%d", time.Now().UnixNano()),
-		ID:        fmt.Sprintf("%d", rand.Intn(10000)),
+		GUID:      randomGUID(),
+		ID:        nextID(),
 		Name:      randomString(10),
 	}
 
@@ -156,6 +176,7 @@ func main() {
 		"roots": map[string]interface{}{
 			"bookmark_bar": Bookmark{
 				DateAdded:    "13152359615589278",
+				GUID:         "0bc5d13f-2cba-5d74-951f-3f233fe6c908",
 				ID:           "1",
 				Name:         "Bookmarks bar",
 				Type:         "folder",
@@ -164,6 +185,7 @@ func main() {
 			},
 			"other_bookmarks": Bookmark{
 				DateAdded:    "13152359615589283",
+				GUID:         "82b081ec-3dd3-529c-8475-ab6c344590dd",
 				ID:           "2",
 				Name:         "Other bookmarks",
 				Type:         "folder",
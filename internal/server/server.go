@@ -76,12 +76,40 @@ func NewWebUIServer(tuiMode bool) *WebUIServer {
 
 	apiRoute := chi.NewRouter()
 	apiRoute.Get("/bookmarks", api.GetAPIBookmarks)
+	apiRoute.Get("/tabs", api.GetAPITabs)
+	apiRoute.Get("/history", api.GetAPIHistory)
+	apiRoute.Post("/history/{id}/promote", api.PostAPIPromoteHistory)
+	apiRoute.Get("/bookmarks/{id}/suggest-tags", api.GetAPISuggestTags)
+	apiRoute.Post("/bookmarks/{id}/tags", api.PostAPIAddTags)
+	apiRoute.Post("/bookmarks/{id}/reject-tag", api.PostAPIRejectTag)
+	apiRoute.Get("/bookmarks/{id}/related", api.GetAPIRelated)
+	apiRoute.Post("/bookmarks/{id}/state", api.PostAPIBookmarkState)
+	apiRoute.Get("/bookmarks/{id}/notes", api.GetAPINote)
+	apiRoute.Post("/bookmarks/{id}/notes", api.PostAPINote)
+	apiRoute.Get("/marktab/test", api.GetAPIMarktabTest)
+	apiRoute.Get("/marktab/runs", api.GetAPIMarktabRuns)
+	apiRoute.Get("/archives", api.GetAPIArchives)
 
 	router.Mount("/api", apiRoute)
 
 	router.Get("/greet", greet)
 	router.Get("/bookmarks", webui.ListBookmarks)
 	router.Get("/bookmarks/{tag}", webui.ListBookmarks)
+	router.Get("/bookmarks/{id}/suggest-tags", webui.SuggestTags)
+	router.Post("/bookmarks/{id}/tags", webui.AcceptTag)
+	router.Post("/bookmarks/{id}/reject-tag", webui.RejectTag)
+	router.Get("/bookmarks/{id}/related", webui.RelatedBookmarks)
+	router.Post("/bookmarks/{id}/state", webui.SetBookmarkState)
+	router.Get("/bookmarks/{id}/notes", webui.BookmarkNote)
+	router.Post("/bookmarks/{id}/notes", webui.SaveBookmarkNote)
+	router.Get("/history", webui.HistoryView)
+	router.Get("/history/entries", webui.ListHistory)
+	router.Post("/history/{id}/promote", webui.PromoteHistory)
+	router.Get("/marktab", webui.MarktabView)
+	router.Get("/marktab/runs", webui.ListMarktabRuns)
+	router.Get("/archives", webui.ArchivesView)
+	router.Get("/archives/list", webui.ListArchives)
+	router.Get("/archives/{id}", webui.ServeSnapshot)
 	router.Get("/kill", func(w http.ResponseWriter, r *http.Request) {
 		panic("quit")
 	})
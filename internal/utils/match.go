@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// CheckPattern returns an error if the domain or module pattern is not a
+// valid glob
+func CheckPattern(pattern string) error {
+	_, err := path.Match(pattern, "")
+	return err
+}
+
+// MatchDomain matches the host of the URL against a glob such as
+// `*.github.com`. A domain without wildcards also matches its subdomains.
+func MatchDomain(pattern, rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	pattern = strings.TrimPrefix(pattern, ".")
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		return host == pattern || strings.HasSuffix(host, "."+pattern)
+	}
+	ok, _ := path.Match(pattern, host)
+	return ok
+}
+
+// MatchAnyDomain returns true if the URL matches one of the domain patterns
+func MatchAnyDomain(patterns []string, rawURL string) bool {
+	for _, p := range patterns {
+		if MatchDomain(p, rawURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchModule matches a `name[:profile]` glob against a module ID such as
+// `firefox_work` or `firefox_zen_default`. Without a profile all the profiles
+// of the module match.
+func MatchModule(pattern, module string) bool {
+	name, profile, hasProfile := strings.Cut(pattern, ":")
+
+	var globs []string
+	if hasProfile {
+		globs = []string{name + "_" + profile, name + "_*_" + profile}
+	} else {
+		globs = []string{name, name + "_*"}
+	}
+
+	for _, g := range globs {
+		if ok, _ := path.Match(g, module); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchDomain(t *testing.T) {
+	assert.True(t, MatchDomain("github.com", "https://github.com/blob42"))
+	assert.True(t, MatchDomain("github.com", "https://gist.github.com/"))
+	assert.True(t, MatchDomain(".GitHub.com", "https://github.com/"))
+	assert.True(t, MatchDomain("*.github.com", "https://gist.github.com/"))
+	assert.True(t, MatchDomain("git*.com", "https://gitlab.com/"))
+	assert.False(t, MatchDomain("*.github.com", "https://github.com/"))
+	assert.False(t, MatchDomain("github.com", "https://notgithub.com/"))
+	assert.False(t, MatchDomain("github.com", "://bad"))
+
+	domains := []string{"bank.com", "*.corp.example"}
+	assert.True(t, MatchAnyDomain(domains, "https://www.bank.com/"))
+	assert.True(t, MatchAnyDomain(domains, "https://intranet.corp.example/"))
+	assert.False(t, MatchAnyDomain(domains, "https://corp.example/"))
+}
+
+func TestMatchModule(t *testing.T) {
+	assert.True(t, MatchModule("firefox", "firefox_work"))
+	assert.True(t, MatchModule("firefox", "firefox_zen_default"))
+	assert.True(t, MatchModule("firefox:work", "firefox_work"))
+	assert.True(t, MatchModule("firefox:work", "firefox_zen_work"))
+	assert.True(t, MatchModule("*:work", "chrome_work"))
+	assert.True(t, MatchModule("chrome", "chrome"))
+	assert.False(t, MatchModule("firefox:work", "firefox_home"))
+	assert.False(t, MatchModule("chrome", "firefox_work"))
+	assert.False(t, MatchModule("fire", "firefox_work"))
+}
+
+func TestCheckPattern(t *testing.T) {
+	assert.NoError(t, CheckPattern("*.github.com"))
+	assert.NoError(t, CheckPattern("firefox:work"))
+	assert.Error(t, CheckPattern("["))
+}
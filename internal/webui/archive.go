@@ -0,0 +1,185 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package webui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/archive"
+)
+
+// Snapshots are shown without scripts, network access or same origin access
+const snapshotCSP = "sandbox; default-src 'none'; img-src data:; media-src data:; " +
+	"font-src data:; style-src 'unsafe-inline' data:"
+
+type UISnapshot struct {
+	*archive.Snapshot
+	CreatedAt string
+	HumanSize string
+}
+
+func NewUISnapshot(snap *archive.Snapshot) *UISnapshot {
+	return &UISnapshot{
+		Snapshot:  snap,
+		CreatedAt: time.Unix(snap.Created, 0).Format(historyDateFormat),
+		HumanSize: humanSize(snap.Size),
+	}
+}
+
+func humanSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+// ArchivesContext shadows the bookmarks of MarksContext with snapshots
+type ArchivesContext struct {
+	MarksContext
+	Bookmarks []*UISnapshot
+}
+
+func archivesContext(r *http.Request) (*ArchivesContext, error) {
+	r = preprocessQuery(r)
+	queryParams := fillQueryParms(r)
+	queryParams.ViewPath = "/archives"
+	queryParams.SearchPath = "/archives/list"
+
+	result, err := db.QuerySnapshots(
+		r.Context(),
+		queryParams.Query,
+		queryParams.PaginationParams,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []*UISnapshot{}
+	for _, snap := range result.Snapshots {
+		snapshots = append(snapshots, NewUISnapshot(snap))
+	}
+
+	return &ArchivesContext{
+		MarksContext: MarksContext{
+			Total:       int(result.Total),
+			Pages:       int(math.Ceil(float64(result.Total) / float64(queryParams.Size))),
+			QueryParams: queryParams,
+		},
+		Bookmarks: snapshots,
+	}, nil
+}
+
+// ArchivesView is the page listing the snapshots of archived pages
+func ArchivesView(w http.ResponseWriter, r *http.Request) {
+	v, err := templates.ParseFS(
+		Views,
+		"views/archives.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "parsing template: %s", err)
+		return
+	}
+
+	ctx, err := archivesContext(r)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "getting snapshots: %s", err)
+		return
+	}
+
+	v.Execute(w, ctx)
+}
+
+// ListArchives renders the snapshots search results
+func ListArchives(w http.ResponseWriter, r *http.Request) {
+	ctx, err := archivesContext(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(
+			"fetching snapshots: %s",
+			err,
+		), http.StatusInternalServerError)
+		return
+	}
+
+	templates.ExecuteTemplate(w, "archives.html", ctx)
+}
+
+// ServeSnapshot serves the snapshot file. HTML snapshots are sandboxed, WARC
+// files are downloaded.
+func ServeSnapshot(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid snapshot id", http.StatusBadRequest)
+		return
+	}
+
+	snap, err := db.GetSnapshot(r.Context(), id)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("snapshot %d not found", id), http.StatusNotFound)
+		return
+	}
+
+	// only serve files from the archive directory
+	path, err := snap.File()
+	if errors.Is(err, archive.ErrOutsideDir) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("opening snapshot: %s", err), http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+
+	switch snap.Format {
+	case archive.FormatWARC:
+		w.Header().Set("Content-Type", "application/warc")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", filepath.Base(snap.Path)))
+	default:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", snapshotCSP)
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(w, r, "", time.Unix(snap.Created, 0), file)
+}
@@ -0,0 +1,129 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package webui
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/blob42/gosuki/internal/api"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+const historyDateFormat = "2006-01-02 15:04"
+
+type UIHistoryEntry struct {
+	*db.HistoryEntry
+	LastVisited string
+}
+
+func NewUIHistoryEntry(e *db.HistoryEntry) *UIHistoryEntry {
+	e.Title = template.HTMLEscapeString(e.Title)
+
+	return &UIHistoryEntry{
+		HistoryEntry: e,
+		LastVisited:  time.Unix(e.LastVisit, 0).Format(historyDateFormat),
+	}
+}
+
+// HistoryContext shadows the bookmarks of MarksContext with history entries
+type HistoryContext struct {
+	MarksContext
+	Bookmarks []*UIHistoryEntry
+}
+
+func historyContext(r *http.Request) (*HistoryContext, error) {
+	r = preprocessQuery(r)
+	queryParams := fillQueryParms(r)
+	queryParams.ViewPath = "/history"
+	queryParams.SearchPath = "/history/entries"
+
+	result, err := db.QueryHistory(r.Context(), queryParams.Query, queryParams.PaginationParams)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*UIHistoryEntry{}
+	for _, e := range result.Entries {
+		entries = append(entries, NewUIHistoryEntry(e))
+	}
+
+	return &HistoryContext{
+		MarksContext: MarksContext{
+			Total:       int(result.Total),
+			Pages:       int(math.Ceil(float64(result.Total) / float64(queryParams.Size))),
+			QueryParams: queryParams,
+		},
+		Bookmarks: entries,
+	}, nil
+}
+
+// HistoryView is the browsing history search page
+func HistoryView(w http.ResponseWriter, r *http.Request) {
+	v, err := templates.ParseFS(
+		Views,
+		"views/history.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "parsing template: %s", err)
+		return
+	}
+
+	ctx, err := historyContext(r)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "getting history: %s", err)
+		return
+	}
+
+	v.Execute(w, ctx)
+}
+
+// ListHistory renders the history search results
+func ListHistory(w http.ResponseWriter, r *http.Request) {
+	ctx, err := historyContext(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(
+			"fetching history: %s",
+			err,
+		), http.StatusInternalServerError)
+		return
+	}
+
+	templates.ExecuteTemplate(w, "history.html", ctx)
+}
+
+// PromoteHistory saves a history entry as bookmark and replaces the promote
+// button with the result
+func PromoteHistory(w http.ResponseWriter, r *http.Request) {
+	if _, err := api.PromoteHistory(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fmt.Fprint(w, `<button class="secondary promote" disabled>bookmarked</button>`)
+}
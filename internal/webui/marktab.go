@@ -0,0 +1,120 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package webui
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/marktab"
+)
+
+type UIMarktabRun struct {
+	*marktab.Run
+	StartedAt string
+	Took      string
+}
+
+func NewUIMarktabRun(run *marktab.Run) *UIMarktabRun {
+	return &UIMarktabRun{
+		Run:       run,
+		StartedAt: time.Unix(run.Started, 0).Format(historyDateFormat),
+		Took:      (time.Duration(run.Duration) * time.Millisecond).String(),
+	}
+}
+
+// MarktabRunsContext shadows the bookmarks of MarksContext with marktab runs
+type MarktabRunsContext struct {
+	MarksContext
+	Bookmarks []*UIMarktabRun
+}
+
+func marktabRunsContext(r *http.Request) (*MarktabRunsContext, error) {
+	r = preprocessQuery(r)
+	queryParams := fillQueryParms(r)
+	queryParams.ViewPath = "/marktab"
+	queryParams.SearchPath = "/marktab/runs"
+
+	result, err := db.QueryMarktabRuns(
+		r.Context(),
+		r.URL.Query().Get("status"),
+		queryParams.Query,
+		queryParams.PaginationParams,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []*UIMarktabRun{}
+	for _, run := range result.Runs {
+		runs = append(runs, NewUIMarktabRun(run))
+	}
+
+	return &MarktabRunsContext{
+		MarksContext: MarksContext{
+			Total:       int(result.Total),
+			Pages:       int(math.Ceil(float64(result.Total) / float64(queryParams.Size))),
+			QueryParams: queryParams,
+		},
+		Bookmarks: runs,
+	}, nil
+}
+
+// MarktabView is the page listing the runs of marktab commands
+func MarktabView(w http.ResponseWriter, r *http.Request) {
+	v, err := templates.ParseFS(
+		Views,
+		"views/marktab.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "parsing template: %s", err)
+		return
+	}
+
+	ctx, err := marktabRunsContext(r)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "getting marktab runs: %s", err)
+		return
+	}
+
+	v.Execute(w, ctx)
+}
+
+// ListMarktabRuns renders the marktab runs search results
+func ListMarktabRuns(w http.ResponseWriter, r *http.Request) {
+	ctx, err := marktabRunsContext(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf(
+			"fetching marktab runs: %s",
+			err,
+		), http.StatusInternalServerError)
+		return
+	}
+
+	templates.ExecuteTemplate(w, "marktab.html", ctx)
+}
@@ -0,0 +1,74 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package webui
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/blob42/gosuki/internal/api"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/markdown"
+)
+
+type noteView struct {
+	ID   string
+	Note *db.Note
+	HTML string
+	Edit bool
+}
+
+func renderNote(w http.ResponseWriter, id string, note *db.Note, edit bool) {
+	templates.ExecuteTemplate(w, "notes.html", noteView{
+		ID:   id,
+		Note: note,
+		HTML: markdown.ToHTML(note.Text),
+		Edit: edit,
+	})
+}
+
+// BookmarkNote renders the note of a bookmark, or its editor when the `edit`
+// parameter is set
+func BookmarkNote(w http.ResponseWriter, r *http.Request) {
+	note, err := api.BookmarkNote(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	edit, _ := strconv.ParseBool(r.URL.Query().Get("edit"))
+	renderNote(w, chi.URLParam(r, "id"), note, edit)
+}
+
+// SaveBookmarkNote saves the note of a bookmark and renders it
+func SaveBookmarkNote(w http.ResponseWriter, r *http.Request) {
+	note, err := api.SetBookmarkNote(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	renderNote(w, chi.URLParam(r, "id"), note, false)
+}
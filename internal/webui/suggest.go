@@ -0,0 +1,68 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package webui
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/blob42/gosuki/internal/api"
+)
+
+// SuggestTags renders the suggested tags of a bookmark. Each suggestion can be
+// accepted, which adds the tag to the bookmark, or rejected.
+func SuggestTags(w http.ResponseWriter, r *http.Request) {
+	suggestions, err := api.SuggestTags(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	templates.ExecuteTemplate(w, "suggest.html", suggestions)
+}
+
+// AcceptTag adds a suggested tag to the bookmark and replaces the suggestion
+// with the new tag
+func AcceptTag(w http.ResponseWriter, r *http.Request) {
+	if _, err := api.AddTags(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tag := r.FormValue("tags")
+	fmt.Fprintf(w,
+		`<button class="secondary pico-background-sand-100"><a href="/?tag=%s">%s</a></button>`,
+		template.URLQueryEscaper(tag),
+		template.HTMLEscapeString(tag),
+	)
+}
+
+// RejectTag rejects a suggested tag, it is not suggested again for the
+// bookmark. The suggestion is removed.
+func RejectTag(w http.ResponseWriter, r *http.Request) {
+	if _, err := api.RejectTag(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+}
@@ -61,10 +61,23 @@ type QueryParams struct {
 	Fuzzy       bool
 	NoHighlight bool
 	*db.PaginationParams
+
+	// Reading state and favorite filters
+	State    string
+	Favorite bool
+
+	// ViewPath is the page the search form submits to, SearchPath the
+	// endpoint returning the search results fragment
+	ViewPath   string
+	SearchPath string
 }
 
 func DefaultQueryParams() QueryParams {
-	return QueryParams{PaginationParams: db.DefaultPagination()}
+	return QueryParams{
+		PaginationParams: db.DefaultPagination(),
+		ViewPath:         "/",
+		SearchPath:       "/bookmarks",
+	}
 }
 
 type MarksContext struct {
@@ -97,6 +110,13 @@ func fillQueryParms(r *http.Request) QueryParams {
 		res.Fuzzy = true
 	}
 
+	if filter, err := api.GetFlagsFilter(r); err == nil {
+		if filter.State != gosuki.StateNone {
+			res.State = filter.State.String()
+		}
+		res.Favorite = filter.Favorite
+	}
+
 	res.PaginationParams = api.GetPaginationParams(r)
 
 	return res
@@ -0,0 +1,81 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package fulltext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki/cmd"
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+var fulltextCmds = &cli.Command{
+	Name:  "fulltext",
+	Usage: "full-text index of bookmarked pages",
+	Commands: []*cli.Command{
+		{
+			Name:      "show",
+			Usage:     "show the indexed text of a bookmarked page",
+			ArgsUsage: "URL",
+			Action:    showPage,
+		},
+	},
+}
+
+func showPage(ctx context.Context, c *cli.Command) error {
+	if c.NArg() != 1 {
+		return errors.New("missing URL")
+	}
+
+	db.Init(ctx, c)
+	defer db.DiskDB.Close()
+
+	page, err := db.GetPage(ctx, c.Args().First())
+	if err != nil {
+		return fmt.Errorf("page %s not indexed: %w", c.Args().First(), err)
+	}
+
+	fmt.Printf("url:     %s\n", page.URL)
+	fmt.Printf("title:   %s\n", page.Title)
+	fmt.Printf("status:  %s\n", page.Status)
+	if page.Source != "" {
+		fmt.Printf("source:  %s\n", page.Source)
+	}
+	fmt.Printf("fetched: %s\n", time.Unix(page.Fetched, 0).Format(time.DateTime))
+	if page.Error != "" {
+		fmt.Printf("error:   %s\n", page.Error)
+	}
+	if page.Content != "" {
+		fmt.Printf("\n%s\n", page.Content)
+	}
+	return nil
+}
+
+func init() {
+	cmd.RegisterModCommand(ModID, fulltextCmds)
+}
@@ -0,0 +1,402 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package fulltext indexes the readable text of bookmarked pages so that plain
+// text searches match the content of pages and not only their title and URL.
+// The module is opt-in: it only runs when `enabled = true` is set in the
+// [fulltext] config section.
+//
+// Pages are read from the latest HTML snapshot of the archiver when there is
+// one, otherwise they are fetched. The text is extracted with
+// [readability.Extract].
+package fulltext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/archive"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/readability"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+const (
+	ModID = "fulltext"
+
+	DefaultInterval        = 10 * time.Minute
+	DefaultBatch           = 20
+	DefaultRefetchInterval = 30 * 24 * time.Hour
+	DefaultRetryInterval   = 24 * time.Hour
+	DefaultTimeout         = 20 * time.Second
+	DefaultMaxSize         = 5 << 20
+	DefaultMaxText         = 200000
+	DefaultUserAgent       = "Mozilla/5.0 (compatible; gosuki-fulltext)"
+
+	// Same limit as the default http client
+	MaxRedirects = 10
+
+	// Page sources
+	SourceWeb     = "web"
+	SourceArchive = "archive"
+)
+
+var (
+	Config *FulltextConfig
+	log    = logging.GetLogger(ModID)
+	model  *fulltextModel
+
+	ErrDisabled = errors.New("disabled, set `enabled = true` in the [fulltext] config section")
+	ErrExcluded = errors.New("redirected to an excluded domain")
+)
+
+type FulltextConfig struct {
+	Enabled bool `toml:"enabled" mapstructure:"enabled"`
+
+	// Pages are indexed by batches at this interval
+	Interval time.Duration `toml:"interval" mapstructure:"interval"`
+	Batch    int           `toml:"batch" mapstructure:"batch"`
+
+	// Indexed pages are fetched again after this duration, 0 to never
+	// refetch them
+	RefetchInterval time.Duration `toml:"refetch-interval" mapstructure:"refetch-interval"`
+
+	// Pages that could not be fetched are tried again after this duration
+	RetryInterval time.Duration `toml:"retry-interval" mapstructure:"retry-interval"`
+
+	// Time limit to fetch a page
+	Timeout time.Duration `toml:"timeout" mapstructure:"timeout"`
+
+	// Larger pages are not indexed (bytes)
+	MaxSize int `toml:"max-size" mapstructure:"max-size"`
+
+	// The text of pages is cut after this number of characters
+	MaxText int `toml:"max-text" mapstructure:"max-text"`
+
+	// Pages of these domains are never fetched. A domain also matches its
+	// subdomains, glob patterns are supported, ex. `*.bank.com`.
+	ExcludeDomains []string `toml:"exclude-domains" mapstructure:"exclude-domains"`
+
+	// Read the HTML snapshots of the archiver instead of fetching the pages
+	UseArchives bool `toml:"use-archives" mapstructure:"use-archives"`
+
+	UserAgent string `toml:"user-agent" mapstructure:"user-agent"`
+}
+
+func NewFulltextConfig() *FulltextConfig {
+	return &FulltextConfig{
+		Interval:        DefaultInterval,
+		Batch:           DefaultBatch,
+		RefetchInterval: DefaultRefetchInterval,
+		RetryInterval:   DefaultRetryInterval,
+		Timeout:         DefaultTimeout,
+		MaxSize:         DefaultMaxSize,
+		MaxText:         DefaultMaxText,
+		ExcludeDomains:  []string{},
+		UseArchives:     true,
+		UserAgent:       DefaultUserAgent,
+	}
+}
+
+type fulltextModel struct {
+	ctx    context.Context
+	client *http.Client
+
+	// excluded pages are checked again once per run of the daemon
+	cleaned bool
+}
+
+// Indexer is the fulltext module
+type Indexer struct{}
+
+// Init implements modules.Initializer
+func (ix *Indexer) Init(ctx *modules.Context) error {
+	if !Config.Enabled {
+		return ErrDisabled
+	}
+	model.ctx = ctx.Context
+
+	for _, pattern := range Config.ExcludeDomains {
+		if err := utils.CheckPattern(pattern); err != nil {
+			return fmt.Errorf("invalid exclude-domains pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (ix Indexer) ModInfo() modules.ModInfo {
+	return modules.ModInfo{
+		ID: modules.ModID(ModID),
+		New: func() modules.Module {
+			return &Indexer{}
+		},
+	}
+}
+
+// checkRedirect follows the redirects of a page unless they lead to an
+// excluded domain
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= MaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", MaxRedirects)
+	}
+	if utils.MatchAnyDomain(Config.ExcludeDomains, req.URL.String()) {
+		return fmt.Errorf("%s: %w", req.URL.Hostname(), ErrExcluded)
+	}
+	return nil
+}
+
+// truncate cuts the text after max characters
+func truncate(text string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:max])
+}
+
+func isHTML(contentType string) bool {
+	ct, _, _ := mime.ParseMediaType(contentType)
+	return ct == "text/html" || ct == "application/xhtml+xml"
+}
+
+// readLimited reads at most max bytes, larger bodies are an error
+func readLimited(r io.Reader, max int) ([]byte, error) {
+	if max <= 0 {
+		return io.ReadAll(r)
+	}
+	body, err := io.ReadAll(io.LimitReader(r, int64(max)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > max {
+		return nil, fmt.Errorf("larger than max-size (%d bytes)", max)
+	}
+	return body, nil
+}
+
+// fetch gets the HTML of the page
+func fetch(ctx context.Context, client *http.Client, pageURL string) ([]byte, error) {
+	if Config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, Config.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	if Config.UserAgent != "" {
+		req.Header.Set("User-Agent", Config.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// the client may follow redirects without checkRedirect
+	if utils.MatchAnyDomain(Config.ExcludeDomains, resp.Request.URL.String()) {
+		return nil, fmt.Errorf("%s: %w", resp.Request.URL.Hostname(), ErrExcluded)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.New(resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !isHTML(ct) {
+		return nil, fmt.Errorf("not an HTML page: %s", ct)
+	}
+	return readLimited(resp.Body, Config.MaxSize)
+}
+
+// readSnapshot returns the HTML of the latest snapshot of the page
+func readSnapshot(pageURL string) ([]byte, error) {
+	if db.L2Cache.DB == nil {
+		return nil, errors.New("L2 cache not initialized")
+	}
+	snap, err := db.L2Cache.LatestSnapshot(pageURL, archive.FormatHTML)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := snap.File()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readLimited(file, Config.MaxSize)
+}
+
+// index returns the page with its readable text or the reason it was not
+// indexed
+func index(ctx context.Context, client *http.Client, pageURL string) *db.Page {
+	page := &db.Page{
+		URL:     pageURL,
+		Status:  db.PageFailed,
+		Fetched: time.Now().Unix(),
+	}
+
+	if utils.MatchAnyDomain(Config.ExcludeDomains, pageURL) {
+		page.Status = db.PageExcluded
+		return page
+	}
+
+	var body []byte
+	var err error
+	if Config.UseArchives {
+		if body, err = readSnapshot(pageURL); err == nil {
+			page.Source = SourceArchive
+		}
+	}
+	if page.Source == "" {
+		if body, err = fetch(ctx, client, pageURL); errors.Is(err, ErrExcluded) {
+			page.Status = db.PageExcluded
+			return page
+		} else if err != nil {
+			page.Error = err.Error()
+			return page
+		}
+		page.Source = SourceWeb
+	}
+
+	article, err := readability.Extract(bytes.NewReader(body))
+	if err != nil {
+		page.Error = err.Error()
+		return page
+	}
+
+	page.Title = article.Title
+	page.Content = truncate(article.Text, Config.MaxText)
+	page.Status = db.PageOK
+	return page
+}
+
+// cleanup removes the excluded pages so they are checked again with the
+// current exclusions, including the text of pages indexed before their
+// domain was excluded
+func cleanup() error {
+	for _, status := range []string{db.PageExcluded, db.PageOK, db.PageFailed} {
+		urls, err := db.L2Cache.PageURLs(status)
+		if err != nil {
+			return err
+		}
+		for _, u := range urls {
+			if status != db.PageExcluded && !utils.MatchAnyDomain(Config.ExcludeDomains, u) {
+				continue
+			}
+			if err := db.L2Cache.DeletePage(u); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Fetch implements watch.Fetcher. Page text is written to its own table, no
+// bookmark is returned.
+func (ix *Indexer) Fetch() ([]*gosuki.Bookmark, error) {
+	if !model.cleaned {
+		if err := cleanup(); err != nil {
+			return nil, fmt.Errorf("cleaning excluded pages: %w", err)
+		}
+		model.cleaned = true
+	}
+
+	now := time.Now()
+	var refetch time.Time
+	if Config.RefetchInterval > 0 {
+		refetch = now.Add(-Config.RefetchInterval)
+	}
+
+	urls, err := db.L2Cache.PagesToIndex(now.Add(-Config.RetryInterval), refetch, Config.Batch)
+	if err != nil {
+		return nil, fmt.Errorf("listing pages: %w", err)
+	}
+
+	var failed int
+	for _, u := range urls {
+		page := index(model.ctx, model.client, u)
+		if err := model.ctx.Err(); err != nil {
+			// shutting down, the page is indexed on the next run
+			return nil, err
+		}
+		if page.Status == db.PageFailed {
+			log.Debug("page not indexed", "url", u, "err", page.Error)
+			failed++
+		}
+
+		if err := db.L2Cache.UpsertPage(page); err != nil {
+			return nil, fmt.Errorf("indexing %s: %w", u, err)
+		}
+	}
+
+	removed, err := db.L2Cache.PrunePages()
+	if err != nil {
+		return nil, fmt.Errorf("pruning pages: %w", err)
+	}
+
+	if len(urls) > 0 || removed > 0 {
+		log.Debug("indexed pages", "count", len(urls), "failed", failed, "removed", removed)
+		db.ScheduleBackupToDisk()
+	}
+
+	return nil, nil
+}
+
+// Interval implements watch.Poller
+func (ix Indexer) Interval() time.Duration {
+	return Config.Interval
+}
+
+func init() {
+	model = &fulltextModel{
+		ctx:    context.Background(),
+		client: &http.Client{CheckRedirect: checkRedirect},
+	}
+
+	Config = NewFulltextConfig()
+	config.RegisterConfigurator(ModID, config.AsConfigurator(Config))
+	modules.RegisterModule(&Indexer{})
+}
+
+// interface guards
+var _ watch.Poller = (*Indexer)(nil)
+var _ modules.Initializer = (*Indexer)(nil)
@@ -0,0 +1,111 @@
+package fulltext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	db "github.com/blob42/gosuki/internal/database"
+)
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "héllo", truncate("héllo", 0))
+	assert.Equal(t, "héllo", truncate("héllo", 5))
+	assert.Equal(t, "hé", truncate("héllo", 2))
+}
+
+func TestIndex(t *testing.T) {
+	saved := *Config
+	t.Cleanup(func() { *Config = saved })
+	Config.UseArchives = false
+	Config.ExcludeDomains = []string{"excluded.test"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head><title>Tides</title></head><body>
+			<nav><a href="/">Home</a></nav>
+			<article><p>The moon pulls the oceans, and the tides follow it twice a day.</p></article>
+			</body></html>`))
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body><p>" + strings.Repeat("lorem ipsum ", 1000) + "</p></body></html>"))
+	})
+	mux.HandleFunc("/file.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.4"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	page := index(ctx, srv.Client(), srv.URL+"/article")
+	assert.Equal(t, db.PageOK, page.Status)
+	assert.Equal(t, SourceWeb, page.Source)
+	assert.Equal(t, "Tides", page.Title)
+	assert.Equal(t, "The moon pulls the oceans, and the tides follow it twice a day.", page.Content)
+	assert.NotZero(t, page.Fetched)
+
+	t.Run("max text", func(t *testing.T) {
+		Config.MaxText = 8
+		defer func() { Config.MaxText = DefaultMaxText }()
+		page := index(ctx, srv.Client(), srv.URL+"/article")
+		assert.Equal(t, "The moon", page.Content)
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		Config.MaxSize = 1024
+		defer func() { Config.MaxSize = DefaultMaxSize }()
+		page := index(ctx, srv.Client(), srv.URL+"/large")
+		assert.Equal(t, db.PageFailed, page.Status)
+		assert.Contains(t, page.Error, "larger than max-size")
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		page := index(ctx, srv.Client(), srv.URL+"/file.pdf")
+		assert.Equal(t, db.PageFailed, page.Status)
+		assert.Contains(t, page.Error, "not an HTML page")
+
+		page = index(ctx, srv.Client(), srv.URL+"/missing")
+		assert.Equal(t, db.PageFailed, page.Status)
+		assert.Equal(t, "404 Not Found", page.Error)
+	})
+
+	t.Run("excluded domain is not fetched", func(t *testing.T) {
+		page := index(ctx, srv.Client(), "https://www.excluded.test/secret")
+		assert.Equal(t, db.PageExcluded, page.Status)
+		assert.Empty(t, page.Content)
+	})
+
+	t.Run("redirect to excluded domain", func(t *testing.T) {
+		var hits atomic.Int32
+		secret := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><body><p>secret</p></body></html>"))
+		}))
+		defer secret.Close()
+
+		// same server under an excluded name
+		target := strings.Replace(secret.URL, "127.0.0.1", "localhost", 1)
+		mux.Handle("/redirect", http.RedirectHandler(target+"/secret", http.StatusFound))
+		Config.ExcludeDomains = []string{"localhost"}
+		defer func() { Config.ExcludeDomains = []string{"excluded.test"} }()
+
+		page := index(ctx, &http.Client{CheckRedirect: checkRedirect}, srv.URL+"/redirect")
+		assert.Equal(t, db.PageExcluded, page.Status)
+		assert.Zero(t, hits.Load())
+
+		// the final URL is checked for clients following all redirects
+		page = index(ctx, srv.Client(), srv.URL+"/redirect")
+		assert.Equal(t, db.PageExcluded, page.Status)
+		assert.Empty(t, page.Content)
+	})
+}
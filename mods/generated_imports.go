@@ -1,6 +1,8 @@
 package mods
 
 import (
+	_ "github.com/blob42/gosuki/mods/fulltext"
 	_ "github.com/blob42/gosuki/mods/github"
+	_ "github.com/blob42/gosuki/mods/history"
 	_ "github.com/blob42/gosuki/mods/importer"
 )
@@ -0,0 +1,216 @@
+//
+//  Copyright (c) 2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+// Package history indexes the browsing history of the detected browser
+// profiles in a table apart from bookmarks. The module is opt-in: it only
+// runs when `enabled = true` is set in the [history] config section.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/blob42/gosuki"
+	db "github.com/blob42/gosuki/internal/database"
+	"github.com/blob42/gosuki/pkg/config"
+	hist "github.com/blob42/gosuki/pkg/history"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/modules"
+	"github.com/blob42/gosuki/pkg/watch"
+)
+
+const (
+	ModID = "history"
+
+	DefaultSyncInterval  = 15 * time.Minute
+	DefaultRetentionDays = 90
+	DefaultMaxEntries    = 100000
+
+	// visits are read again from this long before the previous run, browsers
+	// do not write their history immediately
+	readOverlap = time.Hour
+)
+
+var (
+	Config *HistoryConfig
+	log    = logging.GetLogger(ModID)
+	model  *historyModel
+
+	ErrDisabled = errors.New("disabled, set `enabled = true` in the [history] config section")
+)
+
+type HistoryConfig struct {
+	Enabled      bool          `toml:"enabled" mapstructure:"enabled"`
+	SyncInterval time.Duration `toml:"sync-interval" mapstructure:"sync-interval"`
+
+	// Visits older than this number of days are removed, 0 to keep them
+	RetentionDays int `toml:"retention-days" mapstructure:"retention-days"`
+
+	// Maximum number of entries kept, 0 for no limit
+	MaxEntries int `toml:"max-entries" mapstructure:"max-entries"`
+
+	// Regular expressions matched against the URL of visited pages. Matching
+	// pages are not indexed.
+	Exclude []string `toml:"exclude" mapstructure:"exclude"`
+}
+
+func NewHistoryConfig() *HistoryConfig {
+	return &HistoryConfig{
+		SyncInterval:  DefaultSyncInterval,
+		RetentionDays: DefaultRetentionDays,
+		MaxEntries:    DefaultMaxEntries,
+		Exclude:       []string{},
+	}
+}
+
+// oldest returns the time before which visits are removed, the zero time
+// when they are kept forever
+func (c *HistoryConfig) oldest(now time.Time) time.Time {
+	if c.RetentionDays <= 0 {
+		return time.Time{}
+	}
+	return now.Add(-time.Duration(c.RetentionDays) * 24 * time.Hour)
+}
+
+type historyModel struct {
+	exclude []*regexp.Regexp
+	lastRun time.Time
+}
+
+// HistoryIndexer is the history module
+type HistoryIndexer struct{}
+
+// Init implements modules.Initializer
+func (hi *HistoryIndexer) Init(_ *modules.Context) error {
+	if !Config.Enabled {
+		return ErrDisabled
+	}
+
+	exclude, err := compileExclusions(Config.Exclude)
+	if err != nil {
+		return err
+	}
+	model.exclude = exclude
+
+	return nil
+}
+
+func compileExclusions(patterns []string) ([]*regexp.Regexp, error) {
+	var result []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		result = append(result, re)
+	}
+	return result, nil
+}
+
+func (hi HistoryIndexer) ModInfo() modules.ModInfo {
+	return modules.ModInfo{
+		ID: modules.ModID(ModID),
+		New: func() modules.Module {
+			return &HistoryIndexer{}
+		},
+	}
+}
+
+// excluded returns true if the url matches an exclude pattern
+func excluded(url string, patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if re.MatchString(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// entries converts visits to history entries, dropping excluded urls
+func entries(visits []*hist.Visit, exclude []*regexp.Regexp) []*db.HistoryEntry {
+	var result []*db.HistoryEntry
+	for _, v := range visits {
+		if excluded(v.URL, exclude) {
+			continue
+		}
+
+		result = append(result, &db.HistoryEntry{
+			URL:        v.URL,
+			Title:      v.Title,
+			Module:     v.Module,
+			VisitCount: v.VisitCount,
+			LastVisit:  v.LastVisit.Unix(),
+		})
+	}
+	return result
+}
+
+// Fetch implements watch.Fetcher. History is written to its own table, no
+// bookmark is returned.
+func (hi *HistoryIndexer) Fetch() ([]*gosuki.Bookmark, error) {
+	now := time.Now()
+	oldest := Config.oldest(now)
+
+	since := oldest
+	if resume := model.lastRun.Add(-readOverlap); resume.After(oldest) {
+		since = resume
+	}
+
+	visits := entries(hist.Collect(since), model.exclude)
+	log.Debug("indexing history", "visits", len(visits), "since", since)
+
+	if err := db.L2Cache.UpsertHistory(visits); err != nil {
+		return nil, fmt.Errorf("indexing history: %w", err)
+	}
+
+	removed, err := db.L2Cache.PruneHistory(oldest, Config.MaxEntries)
+	if err != nil {
+		return nil, fmt.Errorf("pruning history: %w", err)
+	}
+
+	if len(visits) > 0 || removed > 0 {
+		db.ScheduleBackupToDisk()
+	}
+
+	model.lastRun = now
+
+	return nil, nil
+}
+
+// Interval implements watch.Poller
+func (hi HistoryIndexer) Interval() time.Duration {
+	return Config.SyncInterval
+}
+
+func init() {
+	model = &historyModel{}
+
+	Config = NewHistoryConfig()
+	config.RegisterConfigurator(ModID, config.AsConfigurator(Config))
+	modules.RegisterModule(&HistoryIndexer{})
+}
+
+// interface guards
+var _ watch.Poller = (*HistoryIndexer)(nil)
+var _ modules.Initializer = (*HistoryIndexer)(nil)
@@ -0,0 +1,50 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	hist "github.com/blob42/gosuki/pkg/history"
+)
+
+func TestEntries(t *testing.T) {
+	exclude, err := compileExclusions([]string{`^https?://localhost`, `[?&]token=`})
+	require.NoError(t, err)
+
+	visited := time.Unix(1700000000, 0)
+	visits := []*hist.Visit{
+		{URL: "https://example.com", Title: "Example", VisitCount: 3, LastVisit: visited, Module: "firefox_default"},
+		{URL: "http://localhost:8080/admin", Title: "Admin", VisitCount: 1, LastVisit: visited, Module: "firefox_default"},
+		{URL: "https://example.com/reset?token=secret", VisitCount: 1, LastVisit: visited, Module: "chrome_default"},
+	}
+
+	result := entries(visits, exclude)
+	require.Len(t, result, 1)
+	assert.Equal(t, "https://example.com", result[0].URL)
+	assert.Equal(t, "Example", result[0].Title)
+	assert.Equal(t, 3, result[0].VisitCount)
+	assert.Equal(t, visited.Unix(), result[0].LastVisit)
+	assert.Equal(t, "firefox_default", result[0].Module)
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := compileExclusions([]string{`(`})
+		assert.Error(t, err)
+	})
+}
+
+func TestOldest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cfg := NewHistoryConfig()
+
+	cfg.RetentionDays = 2
+	assert.Equal(t, now.Add(-48*time.Hour), cfg.oldest(now))
+
+	cfg.RetentionDays = 0
+	assert.True(t, cfg.oldest(now).IsZero(), "0 keeps history forever")
+
+	cfg.RetentionDays = -1
+	assert.True(t, cfg.oldest(now).IsZero())
+}
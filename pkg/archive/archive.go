@@ -0,0 +1,169 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package archive saves local snapshots of bookmarked pages. Bookmarks with the
+// tag set in the [archive] config section are archived by the daemon, any URL
+// can be archived with `gosuki archive <url>`.
+//
+//	[archive]
+//	tag = "archive"
+//	format = "html"
+//
+// The `html` format is a single self-contained HTML file where images, styles
+// and fonts are inlined as data URIs. Scripts are removed so snapshots are
+// static and safe to open from the web UI. The `warc` format stores the HTTP
+// responses of the page and its assets in a WARC 1.1 file for replay tools.
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/logging"
+)
+
+// Snapshot formats
+const (
+	FormatHTML = "html"
+	FormatWARC = "warc"
+)
+
+const (
+	DefaultTag       = "archive"
+	DefaultTimeout   = 30 * time.Second
+	DefaultMaxSize   = 32 << 20
+	DefaultUserAgent = "Mozilla/5.0 (compatible; gosuki-archiver)"
+
+	// Name of the snapshots directory next to the database
+	ArchiveDir = "archive"
+)
+
+var (
+	Config *ArchiveConfig
+	log    = logging.GetLogger("archive")
+
+	ErrOutsideDir = errors.New("snapshot outside of the archive directory")
+)
+
+type ArchiveConfig struct {
+	// Bookmarks with this tag are archived by the daemon, empty to disable
+	Tag string `toml:"tag" mapstructure:"tag"`
+
+	// html: single self-contained HTML file
+	// warc: WARC file of the page and its assets
+	Format string `toml:"format" mapstructure:"format"`
+
+	// Snapshots directory, `archive` next to the database when empty
+	Dir string `toml:"dir" mapstructure:"dir"`
+
+	// Time limit to fetch a page and its assets
+	Timeout time.Duration `toml:"timeout" mapstructure:"timeout"`
+
+	// Maximum size in bytes of a page and its assets, larger assets are
+	// left out of the snapshot
+	MaxSize int `toml:"max-size" mapstructure:"max-size"`
+
+	UserAgent string `toml:"user-agent" mapstructure:"user-agent"`
+}
+
+// Snapshot is an archived copy of a page. Path is relative to the archive
+// directory so snapshots are found after it moves, see [Snapshot.File].
+type Snapshot struct {
+	ID      int64  `db:"id" json:"id"`
+	URL     string `db:"URL" json:"url"`
+	Title   string `db:"title" json:"title"`
+	Path    string `db:"path" json:"path"`
+	Format  string `db:"format" json:"format"`
+	Size    int64  `db:"size" json:"size"`
+	Created int64  `db:"created" json:"created"`
+}
+
+// Store saves the snapshots taken by the daemon
+type Store interface {
+	SaveSnapshot(*Snapshot) error
+	HasSnapshot(url string) (bool, error)
+}
+
+var store Store
+
+// SetStore sets where the snapshots taken by the daemon are recorded
+func SetStore(s Store) {
+	store = s
+}
+
+// Dir returns the directory holding the snapshots
+func Dir() (string, error) {
+	if Config.Dir != "" {
+		return utils.ExpandOnly(Config.Dir)
+	}
+
+	dbPath, err := utils.ExpandOnly(config.DBPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(dbPath), ArchiveDir), nil
+}
+
+// File returns the path of the snapshot file in the current archive directory
+func (s *Snapshot) File() (string, error) {
+	if !filepath.IsLocal(s.Path) {
+		return "", fmt.Errorf("%s: %w", s.Path, ErrOutsideDir)
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, s.Path), nil
+}
+
+// IsTagged returns true if the bookmark has the archive tag
+func IsTagged(bk *gosuki.Bookmark) bool {
+	return Config.Tag != "" && slices.Contains(bk.Tags, Config.Tag)
+}
+
+func init() {
+	Config = &ArchiveConfig{
+		Tag:       DefaultTag,
+		Format:    FormatHTML,
+		Timeout:   DefaultTimeout,
+		MaxSize:   DefaultMaxSize,
+		UserAgent: DefaultUserAgent,
+	}
+	config.RegisterConfigurator("archive", config.AsConfigurator(Config))
+
+	config.RegisterConfReadyHooks(func(context.Context, *cli.Command) error {
+		switch Config.Format {
+		case FormatHTML, FormatWARC:
+			return nil
+		}
+		return fmt.Errorf("[archive] format: unknown format %q, expected %q or %q",
+			Config.Format, FormatHTML, FormatWARC)
+	})
+}
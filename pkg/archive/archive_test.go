@@ -0,0 +1,248 @@
+package archive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+const fixturePage = `<!DOCTYPE html>
+<html><head>
+<title>Fixture page</title>
+<meta charset="iso-8859-1">
+<link rel="stylesheet" href="/style.css">
+<link rel="icon" href="/favicon.png">
+<script src="/app.js"></script>
+<script>alert("inline")</script>
+</head>
+<body onload="init()">
+<h1>Hello</h1>
+<img src="img/logo.png" srcset="img/logo@2x.png 2x">
+<img src="/missing.png">
+<a href="/about">about</a>
+<a href="javascript:alert(1)">js</a>
+</body></html>`
+
+var png = []byte("\x89PNG\r\n\x1a\nfake")
+
+func fixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(fixturePage))
+	})
+	mux.HandleFunc("/style.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte(`@import "/extra.css"; body { background: url('img/bg.png') }`))
+	})
+	mux.HandleFunc("/extra.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte(`h1 { color: red }`))
+	})
+	for _, p := range []string{"/favicon.png", "/img/logo.png", "/img/bg.png"} {
+		mux.HandleFunc(p, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(png)
+		})
+	}
+	mux.HandleFunc("/app.js", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript")
+		w.Write([]byte(`console.log("app")`))
+	})
+	mux.HandleFunc("/file.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.4"))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestArchiver(t *testing.T, format string) *Archiver {
+	t.Helper()
+	return &Archiver{
+		Format:  format,
+		Dir:     t.TempDir(),
+		MaxSize: DefaultMaxSize,
+		client:  &http.Client{},
+	}
+}
+
+func TestArchiveHTML(t *testing.T) {
+	srv := fixtureServer(t)
+	a := newTestArchiver(t, FormatHTML)
+
+	snap, err := a.Archive(context.Background(), srv.URL+"/page")
+	require.NoError(t, err)
+	assert.Equal(t, "Fixture page", snap.Title)
+	assert.Equal(t, FormatHTML, snap.Format)
+	assert.True(t, filepath.IsLocal(snap.Path))
+	assert.Equal(t, ".html", filepath.Ext(snap.Path))
+
+	data, err := os.ReadFile(filepath.Join(a.Dir, snap.Path))
+	require.NoError(t, err)
+	html := string(data)
+	assert.EqualValues(t, len(data), snap.Size)
+
+	// assets are inlined
+	assert.Contains(t, html, `src="data:image/png;base64,`)
+	assert.Contains(t, html, `url("data:image/png;base64,`)
+	assert.Contains(t, html, `h1 { color: red }`)
+	assert.Contains(t, html, `rel="icon" href="data:image/png;base64,`)
+	assert.NotContains(t, html, "srcset")
+	assert.NotContains(t, html, `rel="stylesheet"`)
+
+	// static page
+	assert.NotContains(t, html, "<script")
+	assert.NotContains(t, html, "onload")
+	assert.NotContains(t, html, "javascript:")
+	assert.Contains(t, html, `<meta charset="utf-8"/>`)
+	assert.NotContains(t, html, "iso-8859-1")
+
+	// links and missing assets point to the original site
+	assert.Contains(t, html, `href="`+srv.URL+`/about"`)
+	assert.Contains(t, html, `src="`+srv.URL+`/missing.png"`)
+	assert.True(t, strings.HasPrefix(html, "<!-- archived by gosuki from "+srv.URL+"/page"))
+}
+
+func TestArchiveWARC(t *testing.T) {
+	srv := fixtureServer(t)
+	a := newTestArchiver(t, FormatWARC)
+
+	snap, err := a.Archive(context.Background(), srv.URL+"/page")
+	require.NoError(t, err)
+	assert.Equal(t, ".warc", filepath.Ext(snap.Path))
+
+	data, err := os.ReadFile(filepath.Join(a.Dir, snap.Path))
+	require.NoError(t, err)
+	warc := string(data)
+
+	assert.True(t, strings.HasPrefix(warc, "WARC/1.1\r\nWARC-Type: warcinfo\r\n"))
+	assert.Equal(t, 7, strings.Count(warc, "WARC-Type: response\r\n"))
+	for _, p := range []string{"/page", "/style.css", "/extra.css", "/img/bg.png", "/favicon.png", "/app.js", "/img/logo.png"} {
+		assert.Contains(t, warc, "WARC-Target-URI: "+srv.URL+p+"\r\n")
+	}
+	assert.Contains(t, warc, "HTTP/1.1 200 OK\r\n")
+	assert.Contains(t, warc, fixturePage)
+}
+
+func TestArchiveLimits(t *testing.T) {
+	srv := fixtureServer(t)
+
+	t.Run("not html", func(t *testing.T) {
+		_, err := newTestArchiver(t, FormatHTML).Archive(context.Background(), srv.URL+"/file.pdf")
+		assert.ErrorContains(t, err, "not an HTML page")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := newTestArchiver(t, FormatHTML).Archive(context.Background(), srv.URL+"/nothing")
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("assets over the size limit are left out", func(t *testing.T) {
+		a := newTestArchiver(t, FormatHTML)
+		a.MaxSize = len(fixturePage) + 10
+		snap, err := a.Archive(context.Background(), srv.URL+"/page")
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(a.Dir, snap.Path))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "data:image/png")
+		assert.Contains(t, string(data), `src="`+srv.URL+`/img/logo.png"`)
+	})
+
+	t.Run("page over the size limit", func(t *testing.T) {
+		a := newTestArchiver(t, FormatHTML)
+		a.MaxSize = 10
+		_, err := a.Archive(context.Background(), srv.URL+"/page")
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+}
+
+type memStore struct {
+	saved []*Snapshot
+}
+
+func (m *memStore) SaveSnapshot(s *Snapshot) error {
+	m.saved = append(m.saved, s)
+	return nil
+}
+
+func (m *memStore) HasSnapshot(url string) (bool, error) {
+	for _, s := range m.saved {
+		if s.URL == url {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestRequest(t *testing.T) {
+	srv := fixtureServer(t)
+
+	saved := *Config
+	t.Cleanup(func() {
+		*Config = saved
+		SetStore(nil)
+	})
+	Config.Dir = t.TempDir()
+
+	ms := &memStore{}
+	SetStore(ms)
+
+	// untagged bookmarks are not archived
+	require.NoError(t, Request(&gosuki.Bookmark{URL: srv.URL + "/page"}))
+
+	bk := &gosuki.Bookmark{URL: srv.URL + "/page", Tags: []string{DefaultTag}}
+	require.NoError(t, Request(bk))
+	require.Eventually(t, func() bool {
+		pendingMu.Lock()
+		defer pendingMu.Unlock()
+		return len(pending) == 0
+	}, 5*1e9, 1e7)
+
+	require.Len(t, ms.saved, 1)
+	assert.Equal(t, bk.URL, ms.saved[0].URL)
+	path, err := ms.saved[0].File()
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	// archived once
+	require.NoError(t, Request(bk))
+	pendingMu.Lock()
+	assert.Empty(t, pending)
+	pendingMu.Unlock()
+}
+
+func TestSnapshotFile(t *testing.T) {
+	saved := *Config
+	t.Cleanup(func() { *Config = saved })
+
+	snap := &Snapshot{Path: filepath.Join("example.com", "page.html")}
+
+	// snapshots follow the archive directory
+	for _, dir := range []string{t.TempDir(), t.TempDir()} {
+		Config.Dir = dir
+		path, err := snap.File()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "example.com", "page.html"), path)
+	}
+
+	for _, p := range []string{"/etc/passwd", "../page.html", ""} {
+		snap.Path = p
+		_, err := snap.File()
+		assert.ErrorIs(t, err, ErrOutsideDir, p)
+	}
+}
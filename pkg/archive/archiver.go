@@ -0,0 +1,409 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var ErrTooLarge = errors.New("size limit reached")
+
+// resource is a fetched HTTP response
+type resource struct {
+	URL    string
+	Proto  string
+	Status string
+	Header http.Header
+	Body   []byte
+	Date   time.Time
+}
+
+func (r *resource) contentType() string {
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if ct == "" {
+		ct, _, _ = mime.ParseMediaType(http.DetectContentType(r.Body))
+	}
+	return ct
+}
+
+func (r *resource) dataURI() string {
+	return "data:" + r.contentType() + ";base64," + base64.StdEncoding.EncodeToString(r.Body)
+}
+
+// Archiver takes snapshots of pages
+type Archiver struct {
+	Format    string
+	Dir       string
+	MaxSize   int
+	UserAgent string
+
+	client *http.Client
+}
+
+// NewArchiver returns an archiver using the [archive] config
+func NewArchiver() (*Archiver, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Archiver{
+		Format:    Config.Format,
+		Dir:       dir,
+		MaxSize:   Config.MaxSize,
+		UserAgent: Config.UserAgent,
+		client:    &http.Client{},
+	}, nil
+}
+
+// capture holds the state of one snapshot
+type capture struct {
+	*Archiver
+	ctx       context.Context
+	resources []*resource
+	cache     map[string]*resource
+	size      int
+}
+
+// fetch gets the URL within the size limit left for the snapshot. Assets are
+// fetched once per snapshot.
+func (c *capture) fetch(u string) (*resource, error) {
+	if res, ok := c.cache[u]; ok {
+		if res == nil {
+			return nil, fmt.Errorf("%s: already failed", u)
+		}
+		return res, nil
+	}
+	c.cache[u] = nil
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s: %s", u, resp.Status)
+	}
+
+	left := c.MaxSize - c.size
+	if c.MaxSize <= 0 {
+		left = int(^uint(0) >> 2)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(left)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > left {
+		return nil, fmt.Errorf("%s: %w", u, ErrTooLarge)
+	}
+	c.size += len(body)
+
+	res := &resource{
+		URL:    resp.Request.URL.String(),
+		Proto:  resp.Proto,
+		Status: resp.Status,
+		Header: resp.Header,
+		Body:   body,
+		Date:   time.Now(),
+	}
+	c.cache[u] = res
+	c.resources = append(c.resources, res)
+	return res, nil
+}
+
+// asset fetches the URL relative to base, failures are logged and skipped
+func (c *capture) asset(base *url.URL, ref string) *resource {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "data:") {
+		return nil
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+	u.Fragment = ""
+
+	res, err := c.fetch(u.String())
+	if err != nil {
+		log.Debug("skipping asset", "url", u, "err", err)
+		return nil
+	}
+	return res
+}
+
+// Archive fetches the page and its assets and writes the snapshot
+func (a *Archiver) Archive(ctx context.Context, pageURL string) (*Snapshot, error) {
+	if Config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, Config.Timeout)
+		defer cancel()
+	}
+
+	c := &capture{Archiver: a, ctx: ctx, cache: map[string]*resource{}}
+	page, err := c.fetch(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if page.contentType() != "text/html" && page.contentType() != "application/xhtml+xml" {
+		return nil, fmt.Errorf("%s: not an HTML page: %s", pageURL, page.contentType())
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+	if err != nil {
+		return nil, err
+	}
+	base, _ := url.Parse(page.URL)
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if u, err := base.Parse(href); err == nil {
+			base = u
+		}
+	}
+
+	snap := &Snapshot{
+		URL:     pageURL,
+		Title:   strings.TrimSpace(doc.Find("title").First().Text()),
+		Format:  a.Format,
+		Created: time.Now().Unix(),
+	}
+
+	var data []byte
+	switch a.Format {
+	case FormatWARC:
+		c.collect(doc, base)
+		data = c.warc()
+	default:
+		snap.Format = FormatHTML
+		c.inline(doc, base)
+		var out string
+		if out, err = doc.Html(); err != nil {
+			return nil, err
+		}
+		data = []byte(fmt.Sprintf("<!-- archived by gosuki from %s on %s -->\n%s",
+			strings.ReplaceAll(pageURL, "--", "%2D%2D"),
+			time.Unix(snap.Created, 0).UTC().Format(time.RFC3339), out))
+	}
+
+	if snap.Path, err = a.write(snap, data); err != nil {
+		return nil, err
+	}
+	snap.Size = int64(len(data))
+	return snap, nil
+}
+
+// write saves the snapshot under `<dir>/<host>/` and returns its path relative
+// to the archive directory
+func (a *Archiver) write(snap *Snapshot, data []byte) (string, error) {
+	host := "unknown"
+	if u, err := url.Parse(snap.URL); err == nil && u.Hostname() != "" {
+		host = unsafeChars.ReplaceAllString(u.Hostname(), "_")
+	}
+	if err := os.MkdirAll(filepath.Join(a.Dir, host), 0o700); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(snap.URL))
+	name := fmt.Sprintf("%s-%s.%s",
+		time.Unix(snap.Created, 0).UTC().Format("20060102-150405"),
+		hex.EncodeToString(sum[:4]),
+		snap.Format,
+	)
+	rel := filepath.Join(host, name)
+	path := filepath.Join(a.Dir, rel)
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return "", err
+	}
+	return rel, os.Rename(tmp, path)
+}
+
+var (
+	unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9.-]`)
+	cssURL      = regexp.MustCompile(`url\(\s*(?:'([^']*)'|"([^"]*)"|([^)'"\s]*))\s*\)`)
+	cssImport   = regexp.MustCompile(`@import\s+(?:url\()?\s*['"]?([^'")\s;]+)['"]?\s*\)?[^;]*;`)
+)
+
+// inlineCSS replaces the url() of the stylesheet with data URIs
+func (c *capture) inlineCSS(base *url.URL, css string, depth int) string {
+	css = cssImport.ReplaceAllStringFunc(css, func(m string) string {
+		res := c.asset(base, cssImport.FindStringSubmatch(m)[1])
+		if res == nil || depth > 2 {
+			return ""
+		}
+		u, _ := url.Parse(res.URL)
+		return c.inlineCSS(u, string(res.Body), depth+1)
+	})
+
+	return cssURL.ReplaceAllStringFunc(css, func(m string) string {
+		sub := cssURL.FindStringSubmatch(m)
+		ref := sub[1] + sub[2] + sub[3]
+		if strings.HasPrefix(ref, "#") {
+			return m
+		}
+		if res := c.asset(base, ref); res != nil {
+			return `url("` + res.dataURI() + `")`
+		}
+		if u, err := base.Parse(ref); err == nil {
+			return `url("` + u.String() + `")`
+		}
+		return m
+	})
+}
+
+// inline turns the document into a self-contained static page
+func (c *capture) inline(doc *goquery.Document, base *url.URL) {
+	doc.Find("script, noscript, base, meta[http-equiv]").Remove()
+	doc.Find("meta[charset]").Remove()
+	doc.Find("head").PrependHtml(`<meta charset="utf-8">`)
+
+	// event handler attributes
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		var handlers []string
+		for _, attr := range s.Nodes[0].Attr {
+			if strings.HasPrefix(strings.ToLower(attr.Key), "on") {
+				handlers = append(handlers, attr.Key)
+			}
+		}
+		for _, key := range handlers {
+			s.RemoveAttr(key)
+		}
+	})
+
+	// SetText would escape the CSS, the styles are parsed again instead
+	doc.Find("style").Each(func(_ int, s *goquery.Selection) {
+		s.ReplaceWithHtml(styleElement(s.AttrOr("media", ""), c.inlineCSS(base, s.Text(), 0)))
+	})
+
+	doc.Find("link[href]").Each(func(_ int, s *goquery.Selection) {
+		rel := strings.ToLower(s.AttrOr("rel", ""))
+		switch {
+		case strings.Contains(rel, "stylesheet"):
+			res := c.asset(base, s.AttrOr("href", ""))
+			if res == nil {
+				s.Remove()
+				return
+			}
+			u, _ := url.Parse(res.URL)
+			s.ReplaceWithHtml(styleElement(s.AttrOr("media", ""), c.inlineCSS(u, string(res.Body), 0)))
+		case strings.Contains(rel, "icon"):
+			if res := c.asset(base, s.AttrOr("href", "")); res != nil {
+				s.SetAttr("href", res.dataURI())
+			}
+		default:
+			s.Remove()
+		}
+	})
+
+	doc.Find("img[src], input[src], video[poster], source[src]").Each(func(_ int, s *goquery.Selection) {
+		attr := "src"
+		if goquery.NodeName(s) == "video" {
+			attr = "poster"
+		}
+		s.RemoveAttr("srcset")
+		s.RemoveAttr("loading")
+		if res := c.asset(base, s.AttrOr(attr, "")); res != nil {
+			s.SetAttr(attr, res.dataURI())
+		} else {
+			absolute(base, s, attr)
+		}
+	})
+	doc.Find("source[srcset]").Remove()
+	doc.Find("img[srcset]").RemoveAttr("srcset")
+
+	doc.Find("a[href], area[href], iframe[src], form[action]").Each(func(_ int, s *goquery.Selection) {
+		for _, attr := range []string{"href", "src", "action"} {
+			absolute(base, s, attr)
+		}
+	})
+}
+
+// collect fetches the assets of the page for the WARC records
+func (c *capture) collect(doc *goquery.Document, base *url.URL) {
+	doc.Find("link[href]").Each(func(_ int, s *goquery.Selection) {
+		rel := strings.ToLower(s.AttrOr("rel", ""))
+		if !strings.Contains(rel, "stylesheet") && !strings.Contains(rel, "icon") {
+			return
+		}
+		if res := c.asset(base, s.AttrOr("href", "")); res != nil && strings.Contains(rel, "stylesheet") {
+			u, _ := url.Parse(res.URL)
+			c.inlineCSS(u, string(res.Body), 0)
+		}
+	})
+	doc.Find("style").Each(func(_ int, s *goquery.Selection) {
+		c.inlineCSS(base, s.Text(), 0)
+	})
+	doc.Find("script[src], img[src], input[src], source[src]").Each(func(_ int, s *goquery.Selection) {
+		c.asset(base, s.AttrOr("src", ""))
+	})
+	doc.Find("video[poster]").Each(func(_ int, s *goquery.Selection) {
+		c.asset(base, s.AttrOr("poster", ""))
+	})
+}
+
+func styleElement(media, css string) string {
+	css = strings.ReplaceAll(css, "</", `<\/`)
+	if media != "" {
+		return `<style media="` + html.EscapeString(media) + `">` + css + "</style>"
+	}
+	return "<style>" + css + "</style>"
+}
+
+func absolute(base *url.URL, s *goquery.Selection, attr string) {
+	ref, ok := s.Attr(attr)
+	if !ok || strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "data:") {
+		return
+	}
+	if u, err := base.Parse(strings.TrimSpace(ref)); err == nil {
+		if u.Scheme == "javascript" {
+			s.RemoveAttr(attr)
+			return
+		}
+		s.SetAttr(attr, u.String())
+	}
+}
@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package archive
+
+import (
+	"context"
+	"sync"
+
+	"github.com/blob42/gosuki"
+)
+
+const queueSize = 256
+
+var (
+	queue     chan string
+	pending   = map[string]bool{}
+	pendingMu sync.Mutex
+	startOnce sync.Once
+)
+
+// Start archives the requested bookmarks in the background until the context
+// is done. Pages are archived one at a time.
+func Start(ctx context.Context) {
+	startOnce.Do(func() {
+		queue = make(chan string, queueSize)
+		go run(ctx)
+	})
+}
+
+func run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u := <-queue:
+			if _, err := ArchiveURL(ctx, u); err != nil {
+				log.Error("archiving", "url", u, "err", err)
+			}
+
+			pendingMu.Lock()
+			delete(pending, u)
+			pendingMu.Unlock()
+		}
+	}
+}
+
+// ArchiveURL takes a snapshot of the page and records it in the store
+func ArchiveURL(ctx context.Context, u string) (*Snapshot, error) {
+	a, err := NewArchiver()
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := a.Archive(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("archived", "url", u, "path", snap.Path, "size", snap.Size)
+
+	if store != nil {
+		if err := store.SaveSnapshot(snap); err != nil {
+			return snap, err
+		}
+	}
+	return snap, nil
+}
+
+// Request queues the bookmark if it has the archive tag and was not archived
+// yet
+func Request(bk *gosuki.Bookmark) error {
+	if bk == nil || !IsTagged(bk) || store == nil {
+		return nil
+	}
+
+	if archived, err := store.HasSnapshot(bk.URL); err != nil || archived {
+		return err
+	}
+
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	if pending[bk.URL] {
+		return nil
+	}
+
+	Start(context.Background())
+	select {
+	case queue <- bk.URL:
+		pending[bk.URL] = true
+	default:
+		log.Warn("archive queue full, skipping", "url", bk.URL)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package archive
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const warcVersion = "WARC/1.1"
+
+// warc writes a warcinfo record followed by a response record for each
+// fetched resource, the page first
+func (c *capture) warc() []byte {
+	var buf bytes.Buffer
+
+	info := "software: gosuki\r\nformat: WARC File Format 1.1\r\n"
+	writeRecord(&buf, http.Header{
+		"Warc-Type":    {"warcinfo"},
+		"Content-Type": {"application/warc-fields"},
+	}, time.Now(), []byte(info))
+
+	for _, res := range c.resources {
+		writeRecord(&buf, http.Header{
+			"Warc-Type":       {"response"},
+			"Warc-Target-Uri": {res.URL},
+			"Content-Type":    {"application/http;msgtype=response"},
+		}, res.Date, res.httpBlock())
+	}
+	return buf.Bytes()
+}
+
+// httpBlock returns the response as received. The body was already decoded by
+// the client so the transfer headers are rewritten to match it.
+func (r *resource) httpBlock() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s\r\n", r.Proto, r.Status)
+
+	header := r.Header.Clone()
+	for _, h := range []string{"Content-Encoding", "Content-Length", "Transfer-Encoding"} {
+		header.Del(h)
+	}
+	header.Set("Content-Length", strconv.Itoa(len(r.Body)))
+	header.Write(&buf)
+
+	buf.WriteString("\r\n")
+	buf.Write(r.Body)
+	return buf.Bytes()
+}
+
+func writeRecord(buf *bytes.Buffer, header http.Header, date time.Time, block []byte) {
+	buf.WriteString(warcVersion + "\r\n")
+
+	// WARC field names are case insensitive, keep the spelling of the spec
+	fields := [][2]string{
+		{"WARC-Type", header.Get("Warc-Type")},
+		{"WARC-Record-ID", "<urn:uuid:" + uuid() + ">"},
+		{"WARC-Date", date.UTC().Format(time.RFC3339)},
+		{"WARC-Target-URI", header.Get("Warc-Target-Uri")},
+		{"Content-Type", header.Get("Content-Type")},
+		{"Content-Length", strconv.Itoa(len(block))},
+	}
+	for _, f := range fields {
+		if f[1] != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", f[0], f[1])
+		}
+	}
+
+	buf.WriteString("\r\n")
+	buf.Write(block)
+	buf.WriteString("\r\n\r\n")
+}
+
+// uuid returns a random version 4 UUID
+func uuid() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
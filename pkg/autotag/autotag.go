@@ -0,0 +1,230 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package autotag tags bookmarks with declarative rules from the [autotag]
+// config section.
+//
+//	[[autotag.rules]]
+//	domain = "github.com"
+//	add-tags = ["dev"]
+//
+//	[[autotag.rules]]
+//	title = 'RFC \d+'
+//	add-tags = ["rfc"]
+//
+// All the conditions of a rule must match for its actions to apply. Rules are
+// applied in order.
+package autotag
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/logging"
+)
+
+var (
+	Config *AutotagConfig
+	log    = logging.GetLogger("autotag")
+
+	engine   *Engine
+	engineMu sync.Mutex
+)
+
+type AutotagConfig struct {
+	Rules []RuleConfig `toml:"rules" mapstructure:"rules"`
+}
+
+// RuleConfig is a rule as written in the config file
+type RuleConfig struct {
+	Name string `toml:"name" mapstructure:"name"`
+
+	// Conditions
+
+	// Glob matched against the host of the URL, ex. `*.github.com`. A domain
+	// without wildcards also matches its subdomains.
+	Domain string `toml:"domain" mapstructure:"domain"`
+
+	// Regular expressions matched against the URL and title
+	URL   string `toml:"url" mapstructure:"url"`
+	Title string `toml:"title" mapstructure:"title"`
+
+	// `name[:profile]` glob matched against the module, ex. `chrome:work`,
+	// `firefox`
+	Module string `toml:"module" mapstructure:"module"`
+
+	// The bookmark has all these tags
+	Tags []string `toml:"tags" mapstructure:"tags"`
+
+	// Actions
+
+	AddTags     []string `toml:"add-tags" mapstructure:"add-tags"`
+	RemoveTags  []string `toml:"remove-tags" mapstructure:"remove-tags"`
+	Description string   `toml:"description" mapstructure:"description"`
+}
+
+// Rule is a compiled [RuleConfig]
+type Rule struct {
+	RuleConfig
+	urlRe   *regexp.Regexp
+	titleRe *regexp.Regexp
+}
+
+func NewRule(c RuleConfig) (*Rule, error) {
+	var err error
+	r := &Rule{RuleConfig: c}
+
+	if c.URL != "" {
+		if r.urlRe, err = regexp.Compile(c.URL); err != nil {
+			return nil, fmt.Errorf("rule %s: url: %w", r, err)
+		}
+	}
+	if c.Title != "" {
+		if r.titleRe, err = regexp.Compile(c.Title); err != nil {
+			return nil, fmt.Errorf("rule %s: title: %w", r, err)
+		}
+	}
+	if err = utils.CheckPattern(c.Domain); err != nil {
+		return nil, fmt.Errorf("rule %s: domain: %w", r, err)
+	}
+	if err = utils.CheckPattern(c.Module); err != nil {
+		return nil, fmt.Errorf("rule %s: module: %w", r, err)
+	}
+
+	if len(c.AddTags) == 0 && len(c.RemoveTags) == 0 && c.Description == "" {
+		return nil, fmt.Errorf("rule %s: no action", r)
+	}
+
+	return r, nil
+}
+
+func (r *Rule) String() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%+v", r.RuleConfig)
+}
+
+// Match returns true if the bookmark matches all the conditions of the rule
+func (r *Rule) Match(bk *gosuki.Bookmark) bool {
+	if r.Domain != "" && !utils.MatchDomain(r.Domain, bk.URL) {
+		return false
+	}
+	if r.urlRe != nil && !r.urlRe.MatchString(bk.URL) {
+		return false
+	}
+	if r.titleRe != nil && !r.titleRe.MatchString(bk.Title) {
+		return false
+	}
+	if r.Module != "" && !utils.MatchModule(r.Module, bk.Module) {
+		return false
+	}
+	for _, tag := range r.Tags {
+		if !slices.Contains(bk.Tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// apply runs the actions of the rule on the bookmark
+func (r *Rule) apply(bk *gosuki.Bookmark) {
+	bk.Tags = utils.Extends(bk.Tags, r.AddTags...)
+	bk.Tags = slices.DeleteFunc(bk.Tags, func(tag string) bool {
+		return slices.Contains(r.RemoveTags, tag)
+	})
+
+	if r.Description != "" {
+		bk.Desc = r.Description
+	}
+}
+
+// Engine applies a list of rules to bookmarks
+type Engine struct {
+	Rules []*Rule
+}
+
+func NewEngine(rules []RuleConfig) (*Engine, error) {
+	e := &Engine{}
+	for _, c := range rules {
+		rule, err := NewRule(c)
+		if err != nil {
+			return nil, err
+		}
+		e.Rules = append(e.Rules, rule)
+	}
+
+	return e, nil
+}
+
+// Apply runs the matching rules on the bookmark and returns the rules that
+// matched
+func (e *Engine) Apply(bk *gosuki.Bookmark) []*Rule {
+	var matched []*Rule
+	for _, rule := range e.Rules {
+		if rule.Match(bk) {
+			log.Trace("rule matched", "rule", rule, "url", bk.URL)
+			rule.apply(bk)
+			matched = append(matched, rule)
+		}
+	}
+
+	return matched
+}
+
+// DefaultEngine returns the engine for the rules of the config. Invalid rules
+// are reported and no rule is applied.
+func DefaultEngine() *Engine {
+	engineMu.Lock()
+	defer engineMu.Unlock()
+
+	if engine != nil {
+		return engine
+	}
+
+	var err error
+	engine, err = NewEngine(Config.Rules)
+	if err != nil {
+		log.Errorf("[autotag] config: %s", err)
+		engine = &Engine{}
+	}
+
+	return engine
+}
+
+// ResetEngine drops the current engine, the next call to [DefaultEngine]
+// reads the config again.
+func ResetEngine() {
+	engineMu.Lock()
+	engine = nil
+	engineMu.Unlock()
+}
+
+func init() {
+	Config = &AutotagConfig{Rules: []RuleConfig{}}
+	config.RegisterConfigurator("autotag", config.AsConfigurator(Config))
+}
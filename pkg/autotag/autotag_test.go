@@ -0,0 +1,98 @@
+package autotag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func TestEngineApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []RuleConfig
+		bk       gosuki.Bookmark
+		wantTags []string
+		wantDesc string
+		matched  int
+	}{
+		{
+			name:     "domain matches subdomains",
+			rules:    []RuleConfig{{Domain: "github.com", AddTags: []string{"dev"}}},
+			bk:       gosuki.Bookmark{URL: "https://gist.github.com/foo"},
+			wantTags: []string{"dev"},
+			matched:  1,
+		},
+		{
+			name:    "domain does not match suffix",
+			rules:   []RuleConfig{{Domain: "github.com", AddTags: []string{"dev"}}},
+			bk:      gosuki.Bookmark{URL: "https://notgithub.com/foo"},
+			matched: 0,
+		},
+		{
+			name:     "url and title regex",
+			rules:    []RuleConfig{{URL: `\.pdf$`, Title: `(?i)paper`, AddTags: []string{"paper"}}},
+			bk:       gosuki.Bookmark{URL: "https://arxiv.org/a.pdf", Title: "A Paper", Tags: []string{"ml"}},
+			wantTags: []string{"ml", "paper"},
+			matched:  1,
+		},
+		{
+			name:     "title regex does not match",
+			rules:    []RuleConfig{{URL: `\.pdf$`, Title: `(?i)paper`, AddTags: []string{"paper"}}},
+			bk:       gosuki.Bookmark{URL: "https://arxiv.org/a.pdf", Title: "Slides", Tags: []string{"ml"}},
+			wantTags: []string{"ml"},
+			matched:  0,
+		},
+		{
+			name:     "module glob",
+			rules:    []RuleConfig{{Module: "firefox", AddTags: []string{"ff"}}},
+			bk:       gosuki.Bookmark{URL: "https://a.com", Module: "firefox_work"},
+			wantTags: []string{"ff"},
+			matched:  1,
+		},
+		{
+			name:     "tags condition, remove tags and description",
+			rules:    []RuleConfig{{Tags: []string{"todo", "read"}, RemoveTags: []string{"todo"}, Description: "reading list"}},
+			bk:       gosuki.Bookmark{URL: "https://a.com", Tags: []string{"read", "todo", "x"}},
+			wantTags: []string{"read", "x"},
+			wantDesc: "reading list",
+			matched:  1,
+		},
+		{
+			name: "rules are applied in order",
+			rules: []RuleConfig{
+				{Domain: "youtube.com", AddTags: []string{"video"}},
+				{Tags: []string{"video"}, AddTags: []string{"watch-later"}},
+			},
+			bk:       gosuki.Bookmark{URL: "https://www.youtube.com/watch?v=1"},
+			wantTags: []string{"video", "watch-later"},
+			matched:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := NewEngine(tt.rules)
+			require.NoError(t, err)
+
+			bk := tt.bk
+			matched := engine.Apply(&bk)
+			assert.Len(t, matched, tt.matched)
+			assert.ElementsMatch(t, tt.wantTags, bk.Tags)
+			assert.Equal(t, tt.wantDesc, bk.Desc)
+		})
+	}
+}
+
+func TestNewRuleInvalid(t *testing.T) {
+	_, err := NewRule(RuleConfig{Name: "noop", Domain: "a.com"})
+	assert.ErrorContains(t, err, "rule noop: no action")
+
+	_, err = NewRule(RuleConfig{Name: "bad", URL: "(", AddTags: []string{"x"}})
+	assert.ErrorContains(t, err, "rule bad: url")
+
+	_, err = NewRule(RuleConfig{Name: "glob", Module: "[", AddTags: []string{"x"}})
+	assert.ErrorContains(t, err, "rule glob: module")
+}
@@ -0,0 +1,286 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package mozilla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/blob42/gosuki"
+)
+
+const (
+	// Directory inside a profile where Firefox stores its daily bookmark
+	// backups.
+	BookmarkBackupsDir = "bookmarkbackups"
+
+	// mime types of the nodes found in a bookmark backup
+	BackupTypeBookmark  = "text/x-moz-place"
+	BackupTypeFolder    = "text/x-moz-place-container"
+	BackupTypeSeparator = "text/x-moz-place-separator"
+)
+
+var (
+	ErrNoBookmarkBackup = errors.New("no bookmark backup found")
+
+	// bookmarks-2024-01-31_1234_<hash>.jsonlz4
+	reBackupName = regexp.MustCompile(`^bookmarks-(\d{4}-\d{2}-\d{2})(?:_\d+)?(?:_.*)?\.(jsonlz4|json)$`)
+
+	// Maps the `root` attribute of backup root folders to moz_bookmarks ids
+	backupRoots = map[string]Sqlid{
+		"placesRoot":             RootID,
+		"bookmarksMenuFolder":    MenuID,
+		"toolbarFolder":          ToolbarID,
+		"tagsFolder":             TagsID,
+		"unfiledBookmarksFolder": OtherID,
+		"mobileFolder":           MobileID,
+	}
+)
+
+// BackupNode is a node of the JSON bookmark tree written by Firefox to the
+// `bookmarkbackups` directory.
+type BackupNode struct {
+	GUID         string        `json:"guid"`
+	Title        string        `json:"title"`
+	ID           Sqlid         `json:"id"`
+	Type         string        `json:"type"`
+	Root         string        `json:"root"`
+	URI          string        `json:"uri"`
+	Tags         string        `json:"tags"`
+	LastModified Sqlid         `json:"lastModified"`
+	Children     []*BackupNode `json:"children"`
+}
+
+// BookmarkBackup is a parsed Firefox bookmark backup file
+type BookmarkBackup struct {
+	Path string
+	Root *BackupNode
+}
+
+// ParseBookmarkBackup reads a Firefox bookmark backup. Both compressed
+// (.jsonlz4) and plain (.json) backups are supported.
+func ParseBookmarkBackup(path string) (*BookmarkBackup, error) {
+	var data []byte
+	var err error
+
+	if strings.HasSuffix(path, ".jsonlz4") {
+		data, err = ReadMozLz4File(path)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	root := &BackupNode{}
+	if err = json.Unmarshal(data, root); err != nil {
+		return nil, fmt.Errorf("parsing bookmark backup %s: %w", path, err)
+	}
+
+	if root.Type != BackupTypeFolder {
+		return nil, fmt.Errorf("%s: unexpected root node type <%s>", path, root.Type)
+	}
+
+	return &BookmarkBackup{Path: path, Root: root}, nil
+}
+
+// ListBookmarkBackups returns the bookmark backups found in the profile
+// directory sorted from newest to oldest.
+func ListBookmarkBackups(profileDir string) ([]string, error) {
+	backupDir := filepath.Join(profileDir, BookmarkBackupsDir)
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return nil, err
+	}
+
+	type backup struct {
+		path  string
+		date  string
+		mtime int64
+	}
+
+	var backups []backup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := reBackupName.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, backup{
+			path:  filepath.Join(backupDir, e.Name()),
+			date:  m[1],
+			mtime: info.ModTime().UnixNano(),
+		})
+	}
+
+	// Firefox keeps one backup per day, the date in the name is the most
+	// reliable ordering. Use the modification time for same day backups.
+	slices.SortFunc(backups, func(a, b backup) int {
+		if c := strings.Compare(b.date, a.date); c != 0 {
+			return c
+		}
+		switch {
+		case a.mtime > b.mtime:
+			return -1
+		case a.mtime < b.mtime:
+			return 1
+		}
+		return 0
+	})
+
+	result := make([]string, 0, len(backups))
+	for _, b := range backups {
+		result = append(result, b.path)
+	}
+
+	return result, nil
+}
+
+// LatestBookmarkBackup returns the path to the newest bookmark backup of the
+// profile located at profileDir.
+func LatestBookmarkBackup(profileDir string) (string, error) {
+	backups, err := ListBookmarkBackups(profileDir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if len(backups) == 0 {
+		return "", fmt.Errorf("%w in %s", ErrNoBookmarkBackup, profileDir)
+	}
+
+	return backups[0], nil
+}
+
+// Flatten walks the backup tree and returns the folders and bookmarks using
+// the same types used when scanning places.sqlite. Root folders are mapped to
+// their canonical moz_bookmarks ids.
+func (bb *BookmarkBackup) Flatten() ([]*MozFolder, []*MozBookmark) {
+	var folders []*MozFolder
+	var bookmarks []*MozBookmark
+
+	var walk func(node *BackupNode, parent Sqlid, parentTitle string)
+	walk = func(node *BackupNode, parent Sqlid, parentTitle string) {
+		id := node.ID
+		if rootID, ok := backupRoots[node.Root]; ok {
+			id = rootID
+		}
+
+		switch node.Type {
+		case BackupTypeFolder:
+			// the tags folder is not part of backups, tags are stored
+			// inline with each bookmark
+			if id != RootID && id != TagsID {
+				folders = append(folders, &MozFolder{
+					ID:     id,
+					Parent: parent,
+					Title:  node.Title,
+				})
+			}
+
+			for _, child := range node.Children {
+				walk(child, id, node.Title)
+			}
+
+		case BackupTypeBookmark:
+			// skip places: queries and other non web entries
+			if node.URI == "" || strings.HasPrefix(node.URI, "place:") {
+				return
+			}
+
+			bookmarks = append(bookmarks, &MozBookmark{
+				PlID:           id,
+				Title:          node.Title,
+				Tags:           node.Tags,
+				ParentID:       parent,
+				ParentFolder:   parentTitle,
+				URL:            node.URI,
+				BkLastModified: node.LastModified,
+			})
+		}
+	}
+
+	walk(bb.Root, 0, "")
+
+	return folders, bookmarks
+}
+
+// Bookmarks converts the backup to a list of bookmarks. Tags are made of the
+// Firefox tags and the names of the parent folders, root folders use the same
+// names as the Firefox module (see [RootFolderNames]).
+func (bb *BookmarkBackup) Bookmarks(module string) []*gosuki.Bookmark {
+	folders, mozBookmarks := bb.Flatten()
+
+	folderMap := make(map[Sqlid]*MozFolder, len(folders))
+	for _, f := range folders {
+		folderMap[f.ID] = f
+	}
+
+	folderTitle := func(f *MozFolder) string {
+		if name, isRoot := RootFolderNames[f.ID]; isRoot {
+			return name
+		}
+		return f.Title
+	}
+
+	result := make([]*gosuki.Bookmark, 0, len(mozBookmarks))
+	for _, mb := range mozBookmarks {
+		var tags []string
+		for _, tag := range strings.Split(mb.Tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+
+		// walk up the folder hierarchy
+		seen := map[Sqlid]bool{}
+		for f, ok := folderMap[mb.ParentID]; ok && !seen[f.ID]; f, ok = folderMap[f.Parent] {
+			seen[f.ID] = true
+			if title := folderTitle(f); title != "" && !slices.Contains(tags, title) {
+				tags = append(tags, title)
+			}
+		}
+
+		result = append(result, &gosuki.Bookmark{
+			URL:      mb.URL,
+			Title:    mb.Title,
+			Tags:     tags,
+			Module:   module,
+			Modified: uint64(mb.BkLastModified / (1000 * 1000)),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,133 @@
+package mozilla
+
+import (
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const backupProfile = "testdata/backup-profile"
+
+func TestDecodeMozLz4(t *testing.T) {
+	header := func(size int) []byte {
+		h := []byte(MozLz4Magic)
+		return binary.LittleEndian.AppendUint32(h, uint32(size))
+	}
+
+	t.Run("literals only", func(t *testing.T) {
+		data := append(header(5), 0x50, 'h', 'e', 'l', 'l', 'o')
+		out, err := DecodeMozLz4(data)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(out))
+	})
+
+	t.Run("overlapping match", func(t *testing.T) {
+		// literal `ab` followed by a match of 6 bytes at offset 2
+		block := []byte{0x22, 'a', 'b', 0x02, 0x00, 0x00}
+		data := append(header(8), block...)
+		out, err := DecodeMozLz4(data)
+		require.NoError(t, err)
+		assert.Equal(t, "abababab", string(out))
+	})
+
+	t.Run("bad magic", func(t *testing.T) {
+		_, err := DecodeMozLz4([]byte("notmozlz4file"))
+		assert.ErrorIs(t, err, ErrNotMozLz4)
+	})
+
+	t.Run("bad offset", func(t *testing.T) {
+		block := []byte{0x10, 'a', 0x05, 0x00, 0x00}
+		_, err := DecodeMozLz4(append(header(8), block...))
+		assert.ErrorIs(t, err, ErrCorruptLz4)
+	})
+
+	t.Run("wrong size", func(t *testing.T) {
+		_, err := DecodeMozLz4(append(header(10), 0x10, 'a'))
+		assert.ErrorIs(t, err, ErrCorruptLz4)
+	})
+
+	t.Run("size larger than the block can hold", func(t *testing.T) {
+		_, err := DecodeMozLz4(append(header(1<<31), 0x10, 'a'))
+		assert.ErrorIs(t, err, ErrCorruptLz4)
+	})
+
+	t.Run("output overflows size", func(t *testing.T) {
+		// a match of 250 bytes for a declared size of 8
+		block := []byte{0x1f, 'a', 0x01, 0x00, 0xe7}
+		_, err := DecodeMozLz4(append(header(8), block...))
+		assert.ErrorIs(t, err, ErrCorruptLz4)
+	})
+}
+
+func TestLatestBookmarkBackup(t *testing.T) {
+	backups, err := ListBookmarkBackups(backupProfile)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "bookmarks-2024-03-02_5_abcdef==.jsonlz4", filepath.Base(backups[0]))
+
+	latest, err := LatestBookmarkBackup(backupProfile)
+	require.NoError(t, err)
+	assert.Equal(t, backups[0], latest)
+
+	_, err = LatestBookmarkBackup(t.TempDir())
+	assert.True(t, errors.Is(err, ErrNoBookmarkBackup))
+}
+
+func TestParseBookmarkBackup(t *testing.T) {
+	latest, err := LatestBookmarkBackup(backupProfile)
+	require.NoError(t, err)
+
+	backup, err := ParseBookmarkBackup(latest)
+	require.NoError(t, err)
+
+	t.Run("Flatten", func(t *testing.T) {
+		folders, bookmarks := backup.Flatten()
+
+		var folderIDs []Sqlid
+		for _, f := range folders {
+			folderIDs = append(folderIDs, f.ID)
+		}
+		assert.ElementsMatch(t, []Sqlid{MenuID, 11, 12, ToolbarID, OtherID, MobileID}, folderIDs)
+
+		var urls []string
+		for _, b := range bookmarks {
+			urls = append(urls, b.URL)
+		}
+		// separators and place: queries are ignored
+		assert.ElementsMatch(t, []string{
+			"https://github.com/blob42/gosuki",
+			"https://go.dev/doc/",
+			"https://en.wikipedia.org/",
+			"https://example.com/",
+		}, urls)
+	})
+
+	t.Run("Bookmarks", func(t *testing.T) {
+		bookmarks := backup.Bookmarks("test")
+		byURL := map[string][]string{}
+		for _, b := range bookmarks {
+			assert.Equal(t, "test", b.Module)
+			byURL[b.URL] = b.Tags
+		}
+
+		assert.ElementsMatch(t, []string{"go", "bookmarks", "menu"}, byURL["https://github.com/blob42/gosuki"])
+		assert.ElementsMatch(t, []string{"Golang", "Dev", "menu"}, byURL["https://go.dev/doc/"])
+		assert.ElementsMatch(t, []string{"wiki", "toolbar"}, byURL["https://en.wikipedia.org/"])
+		assert.ElementsMatch(t, []string{"other"}, byURL["https://example.com/"])
+	})
+
+	t.Run("plain json", func(t *testing.T) {
+		backups, err := ListBookmarkBackups(backupProfile)
+		require.NoError(t, err)
+
+		old, err := ParseBookmarkBackup(backups[1])
+		require.NoError(t, err)
+		bookmarks := old.Bookmarks("test")
+		require.Len(t, bookmarks, 1)
+		assert.Equal(t, "https://old.example.com/", bookmarks[0].URL)
+	})
+}
@@ -0,0 +1,170 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package mozilla
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// MozLz4Magic is the header of files compressed in the mozlz4 format. It is
+// followed by the little endian uint32 size of the decompressed payload and an
+// lz4 block.
+//
+// Firefox uses this format for `bookmarkbackups/*.jsonlz4` and
+// `sessionstore-backups/*.jsonlz4` files.
+const MozLz4Magic = "mozLz40\x00"
+
+// MaxMozLz4Size is the largest decompressed size accepted from a mozlz4
+// header, the size is read from the file before anything is decoded.
+const MaxMozLz4Size = 256 << 20
+
+// lz4 blocks expand at most 255 times, a match length byte of 255 yields 255
+// bytes
+const maxLz4Ratio = 255
+
+var (
+	ErrNotMozLz4  = errors.New("not a mozlz4 file")
+	ErrCorruptLz4 = errors.New("corrupt lz4 block")
+)
+
+// DecodeMozLz4 decompresses a mozlz4 payload.
+func DecodeMozLz4(data []byte) ([]byte, error) {
+	hdrLen := len(MozLz4Magic)
+	if len(data) < hdrLen+4 || !bytes.Equal(data[:hdrLen], []byte(MozLz4Magic)) {
+		return nil, ErrNotMozLz4
+	}
+
+	size := binary.LittleEndian.Uint32(data[hdrLen : hdrLen+4])
+	return decodeLz4Block(data[hdrLen+4:], int(size))
+}
+
+// ReadMozLz4File reads and decompresses the mozlz4 file at path
+func ReadMozLz4File(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := DecodeMozLz4(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return out, nil
+}
+
+// decodeLz4Block decompresses a raw lz4 block (no frame header) into a buffer
+// of the given size. Blocks that do not fit the size are rejected as soon as
+// they overflow it.
+// See: https://github.com/lz4/lz4/blob/dev/doc/lz4_Block_format.md
+func decodeLz4Block(src []byte, size int) ([]byte, error) {
+	if size > MaxMozLz4Size || size > len(src)*maxLz4Ratio {
+		return nil, fmt.Errorf("%w: invalid size %d for a %d bytes block",
+			ErrCorruptLz4, size, len(src))
+	}
+
+	dst := make([]byte, 0, size)
+	i := 0
+
+	for i < len(src) {
+		token := src[i]
+		i++
+
+		// literals
+		litLen := int(token >> 4)
+		if litLen == 15 {
+			n, read, err := readLz4Len(src[i:])
+			if err != nil {
+				return nil, err
+			}
+			litLen += n
+			i += read
+		}
+
+		if i+litLen > len(src) || len(dst)+litLen > size {
+			return nil, ErrCorruptLz4
+		}
+		dst = append(dst, src[i:i+litLen]...)
+		i += litLen
+
+		// the last sequence only contains literals
+		if i == len(src) {
+			break
+		}
+
+		// match
+		if i+2 > len(src) {
+			return nil, ErrCorruptLz4
+		}
+		offset := int(binary.LittleEndian.Uint16(src[i : i+2]))
+		i += 2
+		if offset == 0 || offset > len(dst) {
+			return nil, ErrCorruptLz4
+		}
+
+		matchLen := int(token & 0x0f)
+		if matchLen == 15 {
+			n, read, err := readLz4Len(src[i:])
+			if err != nil {
+				return nil, err
+			}
+			matchLen += n
+			i += read
+		}
+		matchLen += 4
+		if len(dst)+matchLen > size {
+			return nil, ErrCorruptLz4
+		}
+
+		// matches can overlap with the bytes being written, copy byte by byte
+		start := len(dst) - offset
+		for j := range matchLen {
+			dst = append(dst, dst[start+j])
+		}
+	}
+
+	if len(dst) != size {
+		return nil, fmt.Errorf("%w: expected %d bytes got %d", ErrCorruptLz4, size, len(dst))
+	}
+
+	return dst, nil
+}
+
+// reads an lz4 extended length, returns the length and the number of bytes read
+func readLz4Len(src []byte) (int, int, error) {
+	var n, i int
+	for {
+		if i >= len(src) {
+			return 0, 0, ErrCorruptLz4
+		}
+		b := src[i]
+		i++
+		n += int(b)
+		if b != 255 {
+			return n, i, nil
+		}
+	}
+}
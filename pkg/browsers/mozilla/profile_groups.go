@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package mozilla
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+const (
+	// Directory under the browser base dir holding one sqlite store per
+	// profile group (selectable profiles, Firefox >= 136)
+	ProfileGroupsDir = "Profile Groups"
+
+	// profiles.ini key linking a profile to its group store
+	StoreIDKey = "StoreID"
+
+	qGroupProfiles = `SELECT id, path, name FROM Profiles ORDER BY id`
+)
+
+type groupProfile struct {
+	ID   int64  `db:"id"`
+	Path string `db:"path"`
+	Name string `db:"name"`
+}
+
+// GetGroupProfiles returns the profiles listed in all the profile group
+// stores found under `baseDir`. The profile group is the store ID, which is
+// the store file name without extension.
+func GetGroupProfiles(baseDir string) ([]*profiles.Profile, error) {
+	stores, err := filepath.Glob(filepath.Join(baseDir, ProfileGroupsDir, "*.sqlite"))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*profiles.Profile
+	for _, store := range stores {
+		profs, err := loadGroupStore(baseDir, store)
+		if err != nil {
+			log.Warnf("reading profile group <%s>: %s", store, err)
+			continue
+		}
+		result = append(result, profs...)
+	}
+
+	return result, nil
+}
+
+func loadGroupStore(baseDir, store string) ([]*profiles.Profile, error) {
+	storeID := strings.TrimSuffix(filepath.Base(store), filepath.Ext(store))
+
+	dsn := fmt.Sprintf("file:%s?_query_only=true", store)
+	db, err := sqlx.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var rows []groupProfile
+	if err = db.Select(&rows, qGroupProfiles); err != nil {
+		return nil, err
+	}
+
+	var result []*profiles.Profile
+	for _, row := range rows {
+		p := &profiles.Profile{
+			ID:         fmt.Sprintf("%s:%d", storeID, row.ID),
+			Name:       row.Name,
+			Path:       filepath.FromSlash(row.Path),
+			IsRelative: !filepath.IsAbs(row.Path),
+			BaseDir:    baseDir,
+			Group:      storeID,
+		}
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+// mergeProfiles appends to `profs` the profiles from `others` that do not
+// point to an already listed profile directory. Group membership is copied
+// over to duplicates that have none.
+func mergeProfiles(profs []*profiles.Profile, others []*profiles.Profile) []*profiles.Profile {
+	seen := map[string]*profiles.Profile{}
+	for _, p := range profs {
+		seen[profileKey(p)] = p
+	}
+
+	for _, o := range others {
+		if p, ok := seen[profileKey(o)]; ok {
+			if p.Group == "" {
+				p.Group = o.Group
+			}
+			continue
+		}
+
+		seen[profileKey(o)] = o
+		profs = append(profs, o)
+	}
+
+	return profs
+}
+
+// profile directory without resolving symlinks, the directory might not
+// exist yet for newly created group profiles
+func profileKey(p *profiles.Profile) string {
+	if p.IsRelative {
+		return filepath.Join(p.BaseDir, p.Path)
+	}
+	return filepath.Clean(p.Path)
+}
@@ -28,6 +28,7 @@ package mozilla
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 
 	"github.com/blob42/gosuki/internal/utils"
@@ -110,6 +111,7 @@ func (pm *MozProfileManager) GetProfiles(flavour string) ([]*profiles.Profile, e
 			if err != nil {
 				return nil, err
 			}
+			p.Group = section.Key(StoreIDKey).String()
 
 			result = append(result, p)
 		}
@@ -119,7 +121,13 @@ func (pm *MozProfileManager) GetProfiles(flavour string) ([]*profiles.Profile, e
 		return nil, ErrProfilesIni
 	}
 
-	return result, nil
+	// profiles that only exist in a profile group store
+	groupProfiles, err := GetGroupProfiles(baseDir)
+	if err != nil {
+		log.Warnf("listing profile groups: %s", err)
+	}
+
+	return mergeProfiles(result, groupProfiles), nil
 }
 
 func (pm *MozProfileManager) GetProfileByName(flavour string, name string) (*profiles.Profile, error) {
@@ -137,6 +145,25 @@ func (pm *MozProfileManager) GetProfileByName(flavour string, name string) (*pro
 	return nil, fmt.Errorf("profile %s not found", name)
 }
 
+// ProfileIndexFiles returns the files listing the profiles of `flavour`:
+// profiles.ini and the directory of the profile group stores.
+func (pm *MozProfileManager) ProfileIndexFiles(flavour string) ([]string, error) {
+	flv, ok := browsers.Defined(browsers.Mozilla)[flavour]
+	if !ok {
+		return nil, fmt.Errorf("unknown flavour <%s>", flavour)
+	}
+
+	baseDir, err := flv.ExpandBaseDir()
+	if err != nil {
+		return nil, fmt.Errorf("expanding base directory: %w", err)
+	}
+
+	return []string{
+		filepath.Join(baseDir, ProfilesFile),
+		filepath.Join(baseDir, ProfileGroupsDir),
+	}, nil
+}
+
 func (pm *MozProfileManager) ListFlavours() []BrowserDef {
 	var result []BrowserDef
 
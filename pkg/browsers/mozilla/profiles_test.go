@@ -59,6 +59,30 @@ func TestGetProfiles(t *testing.T) {
 			t.Error("Expected default profile in profiles.ini")
 		}
 	})
+	t.Run("Groups", func(t *testing.T) {
+		browsers.AddBrowserDef(browsers.MozBrowser("test-groups", "testdata/profile-groups", "", ""))
+		pm := &MozProfileManager{
+			PathResolver: &profiles.INIProfileLoader{ProfilesFile: ProfilesFile},
+		}
+
+		profs, err := pm.GetProfiles("test-groups")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		groups := map[string]string{}
+		for _, p := range profs {
+			groups[p.Name] = p.Group
+		}
+
+		// the default profile is listed in both profiles.ini and the group
+		// store, it is only returned once
+		assert.Equal(t, map[string]string{
+			"default":  "d2b1a07c",
+			"legacy":   "",
+			"Research": "d2b1a07c",
+		}, groups)
+	})
 	t.Run("Bad", func(t *testing.T) {
 		pm := &MozProfileManager{
 			PathResolver: BadProfile,
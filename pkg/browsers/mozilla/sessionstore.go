@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package mozilla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/blob42/gosuki/pkg/tabs"
+)
+
+const (
+	// Directory inside a profile where Firefox saves the running session
+	SessionBackupsDir = "sessionstore-backups"
+
+	// Session of the running browser, updated every few seconds
+	SessionRecoveryFile = "recovery.jsonlz4"
+
+	// Previous copy of the running session
+	SessionRecoveryBackupFile = "recovery.baklz4"
+
+	// Session saved on shutdown, in the profile root
+	SessionStoreFile = "sessionstore.jsonlz4"
+)
+
+var ErrNoSessionStore = errors.New("no session store found")
+
+// SessionTab is an open tab found in a Firefox session store
+type SessionTab struct {
+	URL    string
+	Title  string
+	Window int
+	Pinned bool
+
+	// last time the tab was accessed in milliseconds since epoch
+	LastAccessed int64
+}
+
+// Tab implements the tabs.SessionTab interface
+func (t *SessionTab) Tab() *tabs.Tab {
+	return &tabs.Tab{
+		URL:    t.URL,
+		Title:  t.Title,
+		Window: t.Window,
+		Pinned: t.Pinned,
+	}
+}
+
+type sessionStore struct {
+	Windows []struct {
+		Tabs []struct {
+			Entries []struct {
+				URL   string `json:"url"`
+				Title string `json:"title"`
+			} `json:"entries"`
+
+			// 1 based index of the current entry in `entries`
+			Index        int   `json:"index"`
+			Pinned       bool  `json:"pinned"`
+			LastAccessed int64 `json:"lastAccessed"`
+		} `json:"tabs"`
+	} `json:"windows"`
+}
+
+// SessionStorePaths returns the session store files of a profile, ordered
+// from the most to the least recent.
+func SessionStorePaths(profileDir string) []string {
+	return []string{
+		filepath.Join(profileDir, SessionBackupsDir, SessionRecoveryFile),
+		filepath.Join(profileDir, SessionBackupsDir, SessionRecoveryBackupFile),
+		filepath.Join(profileDir, SessionStoreFile),
+	}
+}
+
+// ReadSessionTabs returns the open tabs of the profile in `profileDir` from
+// the most recent session store available.
+func ReadSessionTabs(profileDir string) ([]*SessionTab, error) {
+	for _, path := range SessionStorePaths(profileDir) {
+		data, err := ReadMozLz4File(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		tabs, err := ParseSessionStore(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return tabs, nil
+	}
+
+	return nil, fmt.Errorf("%w in %s", ErrNoSessionStore, profileDir)
+}
+
+// ParseSessionStore returns the open tabs from the decompressed json of a
+// session store. Hidden tabs (ex. inactive tab groups) are included.
+func ParseSessionStore(data []byte) ([]*SessionTab, error) {
+	store := sessionStore{}
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, err
+	}
+
+	var result []*SessionTab
+	for w, window := range store.Windows {
+		for _, tab := range window.Tabs {
+			if len(tab.Entries) == 0 {
+				continue
+			}
+
+			current := min(max(tab.Index, 1), len(tab.Entries)) - 1
+			entry := tab.Entries[current]
+			result = append(result, &SessionTab{
+				URL:          entry.URL,
+				Title:        entry.Title,
+				Window:       w,
+				Pinned:       tab.Pinned,
+				LastAccessed: tab.LastAccessed,
+			})
+		}
+	}
+
+	return result, nil
+}
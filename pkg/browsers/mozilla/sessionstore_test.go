@@ -0,0 +1,28 @@
+package mozilla
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSessionTabs(t *testing.T) {
+	tabs, err := ReadSessionTabs("testdata/session-profile")
+	require.NoError(t, err)
+	require.Len(t, tabs, 4)
+
+	// the current entry of the tab history is used
+	assert.Equal(t, "https://go.dev/doc/", tabs[0].URL)
+	assert.Equal(t, "Documentation - The Go Programming Language", tabs[0].Title)
+
+	assert.True(t, tabs[1].Pinned)
+	assert.Equal(t, "about:newtab", tabs[2].URL)
+
+	assert.Equal(t, 1, tabs[3].Window)
+	assert.Equal(t, int64(1709300003000), tabs[3].LastAccessed)
+
+	_, err = ReadSessionTabs(t.TempDir())
+	assert.True(t, errors.Is(err, ErrNoSessionStore))
+}
@@ -19,14 +19,17 @@
 // You should have received a copy of the GNU Affero General Public License along with
 // gosuki.  If not, see <http://www.gnu.org/licenses/>.
 
-// TODO: auto detect vfs lock then switch or not to watch&copy places
 package mozilla
 
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path"
 
+	"golang.org/x/sys/unix"
+
 	"github.com/blob42/gosuki/internal/utils"
 )
 
@@ -44,16 +47,78 @@ const (
 	//- https://dxr.mozilla.org/mozilla-central/source/storage/mozStorageService.cpp#377
 	//- Change on github: https://github.com/mozilla/gecko-dev/commit/a543f35d4be483b19446304f52e4781d7a4a0a2f
 	PrefMultiProcessAccess = "storage.multiProcessAccess.enabled"
+
+	// SQLite lock bytes, see the "unix" VFS in sqlite3.c (os_unix.c). A
+	// connection holding an EXCLUSIVE lock places a write lock over the
+	// whole shared range.
+	sqlitePendingByte = 0x40000000
+	sqliteSharedFirst = sqlitePendingByte + 2
+	sqliteSharedSize  = 510
 )
 
 var (
 	ErrMultiProcessAlreadyEnabled = errors.New("multiProcessAccess already enabled")
 )
 
-// TODO!:
-func CheckVFSLock(bkDir string) error {
-	log.Debugf("TODO: checking VFS for <%s>", bkDir)
-	return nil
+// VFSStatus reports the locking state of places.sqlite in a profile
+type VFSStatus struct {
+	// Path to places.sqlite
+	Path string
+
+	// places.sqlite is exclusively locked by another process
+	Locked bool
+
+	// `storage.multiProcessAccess.enabled` is set in prefs.js
+	MultiProcessAccess bool
+}
+
+// PlacesLocked reports whether the sqlite database at `dbPath` is
+// exclusively locked by another process. This is the case when Firefox runs
+// without multiProcessAccess, it then keeps an EXCLUSIVE lock on
+// places.sqlite and other processes cannot read it.
+//
+// Locks held by the calling process are not reported.
+func PlacesLocked(dbPath string) (bool, error) {
+	f, err := os.Open(dbPath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	// Ask for the first lock that would prevent reading the shared range.
+	// See man fcntl(2)
+	lock := unix.Flock_t{
+		Type:   unix.F_RDLCK,
+		Whence: io.SeekStart,
+		Start:  sqliteSharedFirst,
+		Len:    sqliteSharedSize,
+	}
+
+	if err = unix.FcntlFlock(f.Fd(), unix.F_GETLK, &lock); err != nil {
+		return false, fmt.Errorf("querying lock on %s: %w", dbPath, err)
+	}
+
+	return lock.Type == unix.F_WRLCK, nil
+}
+
+// CheckVFSLock checks the VFS lock state of places.sqlite in the profile
+// directory `bkDir`
+func CheckVFSLock(bkDir string) (*VFSStatus, error) {
+	log.Debugf("checking VFS for <%s>", bkDir)
+
+	status := &VFSStatus{Path: path.Join(bkDir, PlacesFile)}
+
+	pref, err := GetPrefBool(path.Join(bkDir, PrefsFile), PrefMultiProcessAccess)
+	if err != nil && err != ErrPrefNotFound && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	status.MultiProcessAccess = pref
+
+	if status.Locked, err = PlacesLocked(status.Path); err != nil {
+		return nil, err
+	}
+
+	return status, nil
 }
 
 func UnlockPlaces(bkDir string) error {
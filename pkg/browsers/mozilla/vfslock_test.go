@@ -0,0 +1,95 @@
+package mozilla
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+const envLockHelper = "GOSUKI_TEST_LOCK_HELPER"
+
+// TestLockHelperProcess is not a real test. It is run as a subprocess to hold
+// an exclusive sqlite lock on the file given in envLockHelper until stdin is
+// closed.
+func TestLockHelperProcess(t *testing.T) {
+	dbPath := os.Getenv(envLockHelper)
+	if dbPath == "" {
+		t.Skip("helper process")
+	}
+
+	f, err := os.OpenFile(dbPath, os.O_RDWR, 0)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	lock := unix.Flock_t{
+		Type:   unix.F_WRLCK,
+		Whence: io.SeekStart,
+		Start:  sqliteSharedFirst,
+		Len:    sqliteSharedSize,
+	}
+	if err = unix.FcntlFlock(f.Fd(), unix.F_SETLK, &lock); err != nil {
+		os.Exit(1)
+	}
+
+	os.Stdout.WriteString("locked\n")
+	io.Copy(io.Discard, os.Stdin)
+	os.Exit(0)
+}
+
+func TestCheckVFSLock(t *testing.T) {
+	profileDir := t.TempDir()
+	dbPath := filepath.Join(profileDir, PlacesFile)
+	require.NoError(t, os.WriteFile(dbPath, []byte("SQLite format 3\x00"), 0o600))
+
+	t.Run("unlocked", func(t *testing.T) {
+		status, err := CheckVFSLock(profileDir)
+		require.NoError(t, err)
+		assert.Equal(t, dbPath, status.Path)
+		assert.False(t, status.Locked)
+		assert.False(t, status.MultiProcessAccess)
+	})
+
+	t.Run("multiProcessAccess", func(t *testing.T) {
+		prefs := filepath.Join(profileDir, PrefsFile)
+		require.NoError(t, os.WriteFile(prefs,
+			[]byte(`user_pref("storage.multiProcessAccess.enabled", true);`+"\n"), 0o600))
+		defer os.Remove(prefs)
+
+		status, err := CheckVFSLock(profileDir)
+		require.NoError(t, err)
+		assert.True(t, status.MultiProcessAccess)
+	})
+
+	t.Run("locked by other process", func(t *testing.T) {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestLockHelperProcess$")
+		cmd.Env = append(os.Environ(), envLockHelper+"="+dbPath)
+		stdin, err := cmd.StdinPipe()
+		require.NoError(t, err)
+		stdout, err := cmd.StdoutPipe()
+		require.NoError(t, err)
+		require.NoError(t, cmd.Start())
+		defer cmd.Wait()
+		defer stdin.Close()
+
+		line, err := bufio.NewReader(stdout).ReadString('\n')
+		require.NoError(t, err)
+		require.Equal(t, "locked\n", line)
+
+		status, err := CheckVFSLock(profileDir)
+		require.NoError(t, err)
+		assert.True(t, status.Locked)
+	})
+
+	t.Run("missing places", func(t *testing.T) {
+		_, err := CheckVFSLock(t.TempDir())
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
@@ -61,3 +61,8 @@ type LoadingCompleteMsg struct {
 type RunnerStarted struct {
 	watch.WatchRunner
 }
+
+// Stopped a [watch.Runner] instance, ie. a browser profile that was removed
+type RunnerStopped struct {
+	watch.WatchRunner
+}
@@ -45,6 +45,9 @@ const (
 
 	// Generic RSS-XML format
 	RSS
+
+	// Pocket CSV file format
+	PocketCSV
 )
 
 type BookmarksExporter struct {
@@ -42,22 +42,93 @@ type pinboardBookmark struct {
 	Shared      string `json:"shared"`
 	Toread      string `json:"toread"`
 	Tags        string `json:"tags"`
+
+	// Not part of the Pinboard format: keeps the read/archived states and
+	// favorites which `toread` cannot express
+	State    string `json:"state,omitempty"`
+	Favorite string `json:"favorite,omitempty"`
+
+	// Markdown notes of the bookmark
+	Notes string `json:"notes,omitempty"`
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
 }
 
 func jsonBookmark(book *gosuki.Bookmark) pinboardBookmark {
 	timeStr := time.Unix(int64(book.Modified), 0).UTC().Format(time.RFC3339)
 
-	return pinboardBookmark{
+	res := pinboardBookmark{
 		Href:        book.URL,
 		Description: book.Title,
-		Extended:    "",
+		Extended:    book.Desc,
 		Meta:        "",
 		Hash:        book.Xhsum,
 		Time:        timeStr,
 		Shared:      "no",
-		Toread:      "no",
+		Toread:      yesNo(book.Flags.State() == gosuki.StateUnread),
 		Tags:        strings.Join(book.Tags, ","),
+		Notes:       book.Notes,
 	}
+
+	if state := book.Flags.State(); state != gosuki.StateNone {
+		res.State = state.String()
+	}
+	if book.Flags.Favorite() {
+		res.Favorite = yesNo(true)
+	}
+
+	return res
+}
+
+// ParseJSON reads bookmarks exported in the Pinboard JSON format. Tags can be
+// separated by commas or spaces.
+func ParseJSON(r io.Reader) ([]*gosuki.Bookmark, error) {
+	var entries []pinboardBookmark
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	res := make([]*gosuki.Bookmark, 0, len(entries))
+	for _, e := range entries {
+		if e.Href == "" {
+			continue
+		}
+
+		bk := &gosuki.Bookmark{
+			URL:   e.Href,
+			Title: e.Description,
+			Desc:  e.Extended,
+			Notes: e.Notes,
+			Tags: strings.FieldsFunc(e.Tags, func(r rune) bool {
+				return r == ',' || r == ' '
+			}),
+		}
+
+		if t, err := time.Parse(time.RFC3339, e.Time); err == nil {
+			bk.Modified = uint64(t.Unix())
+		}
+
+		state := gosuki.StateNone
+		if e.Toread == "yes" {
+			state = gosuki.StateUnread
+		}
+		if e.State != "" {
+			var err error
+			if state, err = gosuki.ParseReadState(e.State); err != nil {
+				return nil, fmt.Errorf("%s: %w", e.Href, err)
+			}
+		}
+		bk.Flags = bk.Flags.WithState(state).WithFavorite(e.Favorite == "yes")
+
+		res = append(res, bk)
+	}
+
+	return res, nil
 }
 
 func (je *JSONExporter) MarshalBookmark(book *gosuki.Bookmark) []byte {
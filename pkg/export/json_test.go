@@ -0,0 +1,82 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	bookmarks := []*gosuki.Bookmark{
+		{
+			URL:      "https://example.com/unread",
+			Title:    "Unread",
+			Tags:     []string{"go", "read-later"},
+			Modified: 1689230329,
+			Flags:    gosuki.Flags(0).WithState(gosuki.StateUnread),
+		},
+		{
+			URL:   "https://example.com/archived",
+			Title: "Archived",
+			Desc:  "an archived favorite",
+			Notes: "# Notes\nkept by gosuki",
+			Flags: gosuki.Flags(0).WithState(gosuki.StateArchived).WithFavorite(true),
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, (&JSONExporter{}).ExportBookmarks(bookmarks, &buf))
+
+	parsed, err := ParseJSON(&buf)
+	require.NoError(t, err)
+	require.Len(t, parsed, 2)
+	for i, bk := range parsed {
+		assert.Equal(t, bookmarks[i].URL, bk.URL)
+		assert.Equal(t, bookmarks[i].Title, bk.Title)
+		assert.Equal(t, bookmarks[i].Desc, bk.Desc)
+		assert.Equal(t, bookmarks[i].Flags, bk.Flags)
+		assert.Equal(t, bookmarks[i].Notes, bk.Notes)
+	}
+	assert.Equal(t, bookmarks[0].Tags, parsed[0].Tags)
+	assert.Equal(t, bookmarks[0].Modified, parsed[0].Modified)
+}
+
+func TestParseJSONToread(t *testing.T) {
+	parsed, err := ParseJSON(strings.NewReader(
+		`[{"href": "https://example.com", "toread": "yes", "tags": "a b"}]`))
+	require.NoError(t, err)
+	require.Len(t, parsed, 1)
+	assert.Equal(t, gosuki.StateUnread, parsed[0].Flags.State())
+	assert.Equal(t, []string{"a", "b"}, parsed[0].Tags)
+}
+
+func TestPocketCSVExporter(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, (&PocketCSVExporter{}).ExportBookmarks([]*gosuki.Bookmark{
+		{URL: "https://example.com/a", Title: "A, quoted", Tags: []string{"x", "y"}, Modified: 10},
+		{URL: "https://example.com/b", Title: "B", Flags: gosuki.Flags(0).WithState(gosuki.StateRead)},
+	}, &buf))
+
+	assert.Equal(t, `title,url,time_added,cursor,tags,status
+"A, quoted",https://example.com/a,10,,x|y,unread
+B,https://example.com/b,0,,,archive
+`, buf.String())
+}
+
+func TestNetscapeNotes(t *testing.T) {
+	res := (&NetscapeHTMLExporter{}).MarshalBookmark(&gosuki.Bookmark{
+		URL:   "https://example.com",
+		Title: "Example",
+		Desc:  "description",
+		Notes: "some <notes>",
+	})
+	assert.Contains(t, string(res), "<DD>description\n\nsome &lt;notes&gt;\n")
+
+	res = (&NetscapeHTMLExporter{}).MarshalBookmark(&gosuki.Bookmark{URL: "https://example.com"})
+	assert.NotContains(t, string(res), "<DD>")
+}
@@ -65,7 +65,7 @@ func (ns *NetscapeHTMLExporter) ExportBookmarks(bookmarks []*gosuki.Bookmark, w
 }
 
 func (ns *NetscapeHTMLExporter) MarshalBookmark(book *gosuki.Bookmark) []byte {
-	return fmt.Appendf([]byte{}, `    <DT><A HREF="%s" TAGS="%s" ADD_DATE="%d" LAST_MODIFIED="%d">%s</A>
+	res := fmt.Appendf([]byte{}, `    <DT><A HREF="%s" TAGS="%s" ADD_DATE="%d" LAST_MODIFIED="%d">%s</A>
 `,
 		html.EscapeString(book.URL),
 
@@ -77,4 +77,17 @@ func (ns *NetscapeHTMLExporter) MarshalBookmark(book *gosuki.Bookmark) []byte {
 
 		html.EscapeString(book.Title),
 	)
+
+	// the description and notes go in the <DD> element
+	var dd []string
+	for _, text := range []string{book.Desc, book.Notes} {
+		if text = strings.TrimSpace(text); text != "" {
+			dd = append(dd, text)
+		}
+	}
+	if len(dd) > 0 {
+		res = fmt.Appendf(res, "    <DD>%s\n", html.EscapeString(strings.Join(dd, "\n\n")))
+	}
+
+	return res
 }
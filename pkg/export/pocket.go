@@ -23,29 +23,47 @@
 package export
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
 	"html"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/blob42/gosuki"
 )
 
-type PocketHTMLExporter struct{}
+// PocketHTMLExporter writes the Pocket HTML format. Unread items come first,
+// read and archived items are listed in the `Read Archive` section.
+type PocketHTMLExporter struct {
+	archive [][]byte
+}
+
+func pocketArchived(book *gosuki.Bookmark) bool {
+	state := book.Flags.State()
+	return state == gosuki.StateRead || state == gosuki.StateArchived
+}
 
 func (pe *PocketHTMLExporter) WriteHeader(w io.Writer) error {
+	pe.archive = nil
 	_, err := fmt.Fprintf(w, `<!DOCTYPE html>
 <html>
 <head><title>Pocket Bookmarks</title></head>
 <body>
+<h1>Unread</h1>
 <ul id="pocket-bookmarks">`)
 	return err
 }
 
 func (pe *PocketHTMLExporter) WriteFooter(w io.Writer) error {
 	_, err := fmt.Fprintf(w, `</ul>
+<h1>Read Archive</h1>
+<ul>
+%s</ul>
 </body>
-</html>`)
+</html>`, bytes.Join(pe.archive, nil))
+	pe.archive = nil
 	return err
 }
 
@@ -65,14 +83,77 @@ func (pe *PocketHTMLExporter) ExportBookmarks(bookmarks []*gosuki.Bookmark, w io
 	return pe.WriteFooter(w)
 }
 
+// MarshalBookmark returns the list item of unread bookmarks. Archived ones
+// are kept for the footer and nothing is returned.
 func (pe *PocketHTMLExporter) MarshalBookmark(book *gosuki.Bookmark) []byte {
 	escapedURL := html.EscapeString(book.URL)
 	escapedTitle := html.EscapeString(book.Title)
 	tagStr := strings.Join(book.Tags, ",")
-	return fmt.Appendf([]byte{}, `<li><a href="%s" time_added="%d" tags="%s">%s</a></li>
+	item := fmt.Appendf([]byte{}, `<li><a href="%s" time_added="%d" tags="%s">%s</a></li>
 `,
 		escapedURL,
 		book.Modified,
 		tagStr,
 		escapedTitle)
+
+	if pocketArchived(book) {
+		pe.archive = append(pe.archive, item)
+		return nil
+	}
+	return item
+}
+
+// PocketCSVExporter writes the CSV format of Pocket exports which can be read
+// back by `gosuki import pocket`
+type PocketCSVExporter struct{}
+
+func (pe *PocketCSVExporter) WriteHeader(w io.Writer) error {
+	_, err := fmt.Fprintln(w, "title,url,time_added,cursor,tags,status")
+	return err
 }
+
+func (pe *PocketCSVExporter) WriteFooter(w io.Writer) error {
+	return nil
+}
+
+func (pe *PocketCSVExporter) ExportBookmarks(bookmarks []*gosuki.Bookmark, w io.Writer) error {
+	var err error
+
+	if err = pe.WriteHeader(w); err != nil {
+		return err
+	}
+
+	for _, book := range bookmarks {
+		if _, err = w.Write(pe.MarshalBookmark(book)); err != nil {
+			return err
+		}
+	}
+
+	return pe.WriteFooter(w)
+}
+
+func (pe *PocketCSVExporter) MarshalBookmark(book *gosuki.Bookmark) []byte {
+	status := "unread"
+	if pocketArchived(book) {
+		status = "archive"
+	}
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	cw.Write([]string{
+		book.Title,
+		book.URL,
+		strconv.FormatUint(book.Modified, 10),
+		"",
+		strings.Join(book.Tags, "|"),
+		status,
+	})
+	cw.Flush()
+
+	return buf.Bytes()
+}
+
+var (
+	_ Exporter = (*PocketHTMLExporter)(nil)
+	_ Exporter = (*PocketCSVExporter)(nil)
+)
@@ -0,0 +1,180 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package history reads the browsing history of browser profiles. History
+// is indexed apart from bookmarks by the opt-in history module.
+package history
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+var (
+	log = logging.GetLogger("history")
+
+	// only visits with these schemes are indexed
+	indexedSchemes = []string{"http", "https", "ftp"}
+
+	registeredSources []source
+)
+
+// Visit is a page found in the history of a browser profile
+type Visit struct {
+	URL        string
+	Title      string
+	VisitCount int
+	LastVisit  time.Time
+
+	// Browser module and profile of the visit, formatted like the module of
+	// bookmarks: <browser>[_<flavour>]_<profile>
+	Module string
+}
+
+// Reader is implemented by browser modules that can read the history of a
+// profile. Only pages visited after `since` are returned. Implementations
+// must not change the state of the module.
+type Reader interface {
+	History(p *profiles.Profile, since time.Time) ([]*Visit, error)
+}
+
+// ProfileReader is a [Reader] that can also list the profiles it reads
+// history from, usually a browser module.
+type ProfileReader interface {
+	Reader
+	profiles.ProfileManager
+}
+
+type source struct {
+	id     string
+	reader ProfileReader
+}
+
+// RegisterReader registers the history reader of the browser module `id`.
+// Browser modules call it from their init() function.
+func RegisterReader(id string, reader ProfileReader) {
+	registeredSources = append(registeredSources, source{id, reader})
+}
+
+// Indexable returns true if the visited page can be indexed (ex. not
+// about:config or chrome://settings)
+func (v *Visit) Indexable() bool {
+	u, err := url.Parse(v.URL)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(indexedSchemes, u.Scheme)
+}
+
+// Collect returns the pages visited after `since` in all detected profiles
+// of the registered readers. Profiles that cannot be read are skipped.
+func Collect(since time.Time) []*Visit {
+	var result []*Visit
+
+	for _, src := range registeredSources {
+		for _, flv := range src.reader.ListFlavours() {
+			profs, err := src.reader.GetProfiles(flv.Flavour)
+			if err != nil {
+				log.Debugf("listing <%s> profiles: %s", flv.Flavour, err)
+				continue
+			}
+
+			for _, p := range profs {
+				modName := profiles.ModuleName(src.id, flv.Flavour, p)
+				visits, err := src.reader.History(p, since)
+				if err != nil {
+					log.Debugf("reading history of <%s>: %s", modName, err)
+					continue
+				}
+
+				for _, v := range visits {
+					if !v.Indexable() {
+						continue
+					}
+					v.Module = modName
+					result = append(result, v)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// Snapshot is a read only copy of a history database. Browsers keep an
+// exclusive lock on their history while running, the database and its WAL
+// are copied to a temporary directory before being read.
+type Snapshot struct {
+	*sqlx.DB
+	dir string
+}
+
+// OpenSnapshot copies the sqlite database at `path` and opens the copy
+func OpenSnapshot(path string) (*Snapshot, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp(utils.TMPDIR, "history")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, suffix := range []string{"", "-wal"} {
+		src := path + suffix
+		if _, err = os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+
+		if err = utils.CopyFileToDst(src, filepath.Join(dir, filepath.Base(src))); err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("copying %s: %w", src, err)
+		}
+	}
+
+	db, err := sqlx.Open("sqlite3", filepath.Join(dir, filepath.Base(path)))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return &Snapshot{DB: db, dir: dir}, nil
+}
+
+// Close closes the database and removes the copy
+func (s *Snapshot) Close() error {
+	err := s.DB.Close()
+	if rmErr := os.RemoveAll(s.dir); rmErr != nil && err == nil {
+		err = rmErr
+	}
+	return err
+}
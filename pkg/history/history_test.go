@@ -0,0 +1,129 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+// stubReader returns fixed visits for each profile
+type stubReader struct {
+	flavours []profiles.BrowserDef
+	profiles map[string][]*profiles.Profile
+	visits   map[string][]*Visit
+	since    time.Time
+}
+
+func (s *stubReader) History(p *profiles.Profile, since time.Time) ([]*Visit, error) {
+	s.since = since
+	visits, ok := s.visits[p.Name]
+	if !ok {
+		return nil, errors.New("locked")
+	}
+	return visits, nil
+}
+
+func (s *stubReader) GetProfiles(flavour string) ([]*profiles.Profile, error) {
+	profs, ok := s.profiles[flavour]
+	if !ok {
+		return nil, errors.New("not installed")
+	}
+	return profs, nil
+}
+
+func (s *stubReader) WatchAllProfiles() bool { return true }
+
+func (s *stubReader) UseProfile(*profiles.Profile, *profiles.BrowserDef) error { return nil }
+
+func (s *stubReader) GetProfile() *profiles.Profile { return nil }
+
+func (s *stubReader) ListFlavours() []profiles.BrowserDef { return s.flavours }
+
+func (s *stubReader) GetCurFlavour() *profiles.BrowserDef { return nil }
+
+func TestIndexable(t *testing.T) {
+	for url, want := range map[string]bool{
+		"https://example.com":     true,
+		"http://example.com/path": true,
+		"ftp://example.com/file":  true,
+		"about:config":            false,
+		"chrome://settings":       false,
+		"file:///etc/passwd":      false,
+		"://bad":                  false,
+	} {
+		assert.Equal(t, want, (&Visit{URL: url}).Indexable(), url)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	saved := registeredSources
+	t.Cleanup(func() { registeredSources = saved })
+	registeredSources = nil
+
+	visited := time.Unix(1700000000, 0)
+	reader := &stubReader{
+		flavours: []profiles.BrowserDef{{Flavour: "firefox"}, {Flavour: "zen"}, {Flavour: "missing"}},
+		profiles: map[string][]*profiles.Profile{
+			"firefox": {{Name: "default"}, {Name: "locked"}},
+			"zen":     {{Name: "work"}},
+		},
+		visits: map[string][]*Visit{
+			"default": {
+				{URL: "https://example.com", VisitCount: 2, LastVisit: visited},
+				{URL: "about:config", LastVisit: visited},
+			},
+			"work": {{URL: "https://go.dev", LastVisit: visited}},
+		},
+	}
+	RegisterReader("firefox", reader)
+
+	since := visited.Add(-time.Hour)
+	visits := Collect(since)
+	require.Len(t, visits, 2)
+	assert.Equal(t, since, reader.since)
+
+	assert.Equal(t, "https://example.com", visits[0].URL)
+	assert.Equal(t, "firefox_default", visits[0].Module)
+	assert.Equal(t, 2, visits[0].VisitCount)
+
+	assert.Equal(t, "https://go.dev", visits[1].URL)
+	assert.Equal(t, "firefox_zen_work", visits[1].Module)
+}
+
+func TestOpenSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "History")
+
+	src, err := sqlx.Open("sqlite3", path+"?_journal_mode=WAL")
+	require.NoError(t, err)
+	t.Cleanup(func() { src.Close() })
+	_, err = src.Exec(`CREATE TABLE urls (url TEXT); INSERT INTO urls VALUES ('https://example.com')`)
+	require.NoError(t, err)
+
+	// the browser keeps the database open, the rows are still in the WAL
+	_, err = os.Stat(path + "-wal")
+	require.NoError(t, err)
+
+	snap, err := OpenSnapshot(path)
+	require.NoError(t, err)
+
+	var url string
+	require.NoError(t, snap.Get(&url, `SELECT url FROM urls`))
+	assert.Equal(t, "https://example.com", url)
+
+	require.NoError(t, snap.Close())
+	_, err = os.Stat(snap.dir)
+	assert.True(t, os.IsNotExist(err), "the copy is removed on close")
+
+	t.Run("missing database", func(t *testing.T) {
+		_, err := OpenSnapshot(filepath.Join(t.TempDir(), "History"))
+		assert.Error(t, err)
+	})
+}
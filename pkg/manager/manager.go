@@ -62,9 +62,13 @@ type UnitManager interface {
 }
 
 type WorkUnitManager struct {
-	name         string
-	stop         chan bool
-	workerQuit   chan bool
+	name string
+	stop chan bool
+
+	// closed when the unit is done
+	workerQuit   chan struct{}
+	stopOnce     sync.Once
+	quitOnce     sync.Once
 	unit         WorkUnit
 	panic        chan error
 	isPaniced    bool
@@ -77,7 +81,14 @@ func (w *WorkUnitManager) ShouldStop() <-chan bool {
 }
 
 func (w *WorkUnitManager) Done() {
-	w.workerQuit <- true
+	w.quitOnce.Do(func() { close(w.workerQuit) })
+}
+
+// stopAndWait asks the unit to stop and waits until it is done. It can be
+// called many times and from many goroutines, the unit is asked once.
+func (w *WorkUnitManager) stopAndWait() {
+	w.stopOnce.Do(func() { w.stop <- true })
+	<-w.workerQuit
 }
 
 func (w *WorkUnitManager) Panic(val any) {
@@ -91,7 +102,7 @@ func (w *WorkUnitManager) Panic(val any) {
 	}
 	w.panic <- fmt.Errorf("%v", val)
 	w.isPaniced = true
-	w.workerQuit <- true
+	w.Done()
 }
 
 func (m *WorkUnitManager) RequestShutdown() {
@@ -112,18 +123,32 @@ type Manager struct {
 	mu           sync.Mutex
 }
 
+// running returns the current worker units, units can be added or stopped
+// while the returned slice is used.
+func (m *Manager) running() []*WorkUnitManager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	workers := make([]*WorkUnitManager, 0, len(m.workers))
+	for _, w := range m.workers {
+		workers = append(workers, w)
+	}
+	return workers
+}
+
 func (m *Manager) Shutdown() {
 	<-m.ready
+	workers := m.running()
+
 	// send shutdown event to all worker units
-	for name, w := range m.workers {
-		log.Debugf("stopping %s\n", name)
-		w.stop <- true
+	for _, w := range workers {
+		log.Debugf("stopping %s\n", w.name)
+		w.stopOnce.Do(func() { w.stop <- true })
 	}
 
 	// Wait for all units to quit
-	for name, w := range m.workers {
-		<-w.workerQuit
-		log.Debugf("%s down", name)
+	for _, w := range workers {
+		w.stopAndWait()
+		log.Debugf("%s down", w.name)
 	}
 
 	// All workers have shutdown
@@ -159,14 +184,13 @@ func (m *Manager) Start() {
 
 		case p := <-m.panic:
 
-			for name, w := range m.workers {
+			for _, w := range m.running() {
 				if w.isPaniced {
-					log.Errorf("<%s> panicked: %s", name, p)
+					log.Errorf("<%s> panicked: %s", w.name, p)
 				} else {
-					log.Debugf("shuting down <%s>\n", name)
-					w.stop <- true
-					<-w.workerQuit
-					log.Debugf("<%s> down", name)
+					log.Debugf("shuting down <%s>\n", w.name)
+					w.stopAndWait()
+					log.Debugf("<%s> down", w.name)
 				}
 			}
 
@@ -214,12 +238,15 @@ func runUnit(wum *WorkUnitManager, unitName string) {
 func (m *Manager) AddUnit(unit WorkUnit, name string) *WorkUnitManager {
 	workUnitManager := &WorkUnitManager{
 		name:       name,
-		workerQuit: make(chan bool, 1),
+		workerQuit: make(chan struct{}),
 		stop:       make(chan bool, 1),
 		unit:       unit,
 		panic:      m.panic,
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	unitType := reflect.TypeOf(unit)
 	unitClass := strings.Split(unitType.String(), ".")[1]
 	unitName := fmt.Sprintf("%s[%s", name, unitClass)
@@ -227,8 +254,6 @@ func (m *Manager) AddUnit(unit WorkUnit, name string) *WorkUnitManager {
 	unitName = fmt.Sprintf("%s#%d]", unitName, unitID)
 
 	log.Trace("adding unit ", "name", unitName)
-	m.mu.Lock()
-	defer m.mu.Unlock()
 	m.workers[unitName] = workUnitManager
 
 	// Launch the unit's goroutine *immediatly*
@@ -237,6 +262,21 @@ func (m *Manager) AddUnit(unit WorkUnit, name string) *WorkUnitManager {
 	return workUnitManager
 }
 
+// StopUnit stops a single unit and removes it from the manager. It blocks
+// until the unit is done, it is safe to call during a shutdown.
+func (m *Manager) StopUnit(wum *WorkUnitManager) {
+	m.mu.Lock()
+	for name, w := range m.workers {
+		if w == wum {
+			log.Debugf("stopping %s", name)
+			delete(m.workers, name)
+		}
+	}
+	m.mu.Unlock()
+
+	wum.stopAndWait()
+}
+
 func NewManager() *Manager {
 	return &Manager{
 		signalIn: make(chan os.Signal, 1),
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"os"
+	"sync"
 	"syscall"
 	"testing"
 	"time"
@@ -90,3 +91,61 @@ func TestRunMain(t *testing.T) {
 		<-quit
 	}
 }
+
+func TestStopUnit(t *testing.T) {
+	manager := NewManager()
+	wum := manager.AddUnit(NewWorker(), "stopped")
+	manager.AddUnit(NewWorker(), "running")
+
+	manager.StopUnit(wum)
+
+	units := manager.Units()
+	if len(units) != 1 {
+		t.Fatalf("expected 1 unit, got %d", len(units))
+	}
+	if _, ok := units["running"]; !ok {
+		t.Error("expected running unit to be kept")
+	}
+}
+
+// stoppingWorker returns once it is stopped
+type stoppingWorker struct{}
+
+func (stoppingWorker) Run(um UnitManager) {
+	<-um.ShouldStop()
+	um.Done()
+}
+
+func TestShutdownWhileStopping(t *testing.T) {
+	manager := NewManager()
+	var units []*WorkUnitManager
+	for range 10 {
+		units = append(units, manager.AddUnit(stoppingWorker{}, "unit"))
+	}
+	manager.ready <- true
+
+	// units are added and stopped while the manager shuts down
+	var wg sync.WaitGroup
+	for _, wum := range units {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			manager.StopUnit(wum)
+		}()
+		go func() {
+			defer wg.Done()
+			manager.StopUnit(manager.AddUnit(stoppingWorker{}, "added"))
+		}()
+	}
+	go manager.Shutdown()
+
+	select {
+	case <-manager.Quit:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not complete")
+	}
+	wg.Wait()
+
+	// stopping a unit again does not block
+	manager.StopUnit(units[0])
+}
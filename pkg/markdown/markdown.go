@@ -0,0 +1,218 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package markdown renders a safe subset of Markdown to HTML.
+//
+// Supported blocks are ATX headings, paragraphs, fenced code blocks,
+// blockquotes, bullet and ordered lists and thematic breaks. Inline, it
+// handles code spans, emphasis, strong emphasis, links and autolinks. All text
+// is HTML escaped and raw HTML is not supported, links only accept http(s),
+// mailto and relative URLs.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	reHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	reBullet   = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	reOrdered  = regexp.MustCompile(`^\s{0,3}\d{1,9}[.)]\s+(.*)$`)
+	reRule     = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	reFence    = regexp.MustCompile("^\\s{0,3}(```+|~~~+)\\s*([\\w+-]*)")
+	reQuote    = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	reLink     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	reAutolink = regexp.MustCompile(`&lt;((?:https?|mailto):\S+?)&gt;`)
+	reStrong   = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	reEmph     = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:[^*_]*?\S)?)[*_]`)
+
+	rePlaceholder = regexp.MustCompile("\x00\\d+\x00")
+)
+
+// ToHTML renders the Markdown source as HTML
+func ToHTML(src string) string {
+	// NUL delimits the link placeholders, it is replaced like CommonMark does
+	src = strings.ReplaceAll(src, "\x00", "\uFFFD")
+
+	var out strings.Builder
+	render(&out, strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n"))
+	return out.String()
+}
+
+func render(out *strings.Builder, lines []string) {
+	var para []string
+
+	flush := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>")
+			out.WriteString(inline(strings.Join(para, "\n")))
+			out.WriteString("</p>\n")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+
+		case reFence.MatchString(line):
+			flush()
+			m := reFence.FindStringSubmatch(line)
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			if m[2] != "" {
+				out.WriteString(`<pre><code class="language-` + html.EscapeString(m[2]) + `">`)
+			} else {
+				out.WriteString("<pre><code>")
+			}
+			out.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			out.WriteString("</code></pre>\n")
+
+		case reHeading.MatchString(line):
+			flush()
+			m := reHeading.FindStringSubmatch(line)
+			tag := "h" + string(rune('0'+len(m[1])))
+			out.WriteString("<" + tag + ">" + inline(m[2]) + "</" + tag + ">\n")
+
+		case reRule.MatchString(line):
+			flush()
+			out.WriteString("<hr>\n")
+
+		case reQuote.MatchString(line):
+			flush()
+			var quote []string
+			for ; i < len(lines) && reQuote.MatchString(lines[i]); i++ {
+				quote = append(quote, reQuote.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			out.WriteString("<blockquote>\n")
+			render(out, quote)
+			out.WriteString("</blockquote>\n")
+
+		case reBullet.MatchString(line), reOrdered.MatchString(line):
+			flush()
+			re, tag := reBullet, "ul"
+			if !reBullet.MatchString(line) {
+				re, tag = reOrdered, "ol"
+			}
+			out.WriteString("<" + tag + ">\n")
+			for ; i < len(lines) && re.MatchString(lines[i]); i++ {
+				item := re.FindStringSubmatch(lines[i])[1]
+
+				// lazy continuation lines
+				for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "  ") &&
+					!reBullet.MatchString(lines[i+1]) && !reOrdered.MatchString(lines[i+1]) {
+					i++
+					item += "\n" + strings.TrimSpace(lines[i])
+				}
+				out.WriteString("<li>" + inline(item) + "</li>\n")
+			}
+			i--
+			out.WriteString("</" + tag + ">\n")
+
+		default:
+			para = append(para, strings.TrimSpace(line))
+		}
+	}
+	flush()
+}
+
+// safeURL keeps http(s), mailto and relative URLs
+func safeURL(u string) bool {
+	scheme, _, found := strings.Cut(u, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+
+	switch strings.ToLower(scheme) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+func link(text, u string) string {
+	if !safeURL(html.UnescapeString(u)) {
+		return text
+	}
+	return `<a href="` + u + `" target="_blank" rel="noopener noreferrer">` + text + `</a>`
+}
+
+// inline renders the inline elements of escaped text. Code spans are
+// rendered first so their content is left as is.
+func inline(text string) string {
+	parts := strings.Split(text, "`")
+
+	var out strings.Builder
+	for i, part := range parts {
+		// an unmatched backtick is kept as text
+		if i%2 == 1 && i < len(parts)-1 {
+			out.WriteString("<code>" + html.EscapeString(part) + "</code>")
+			continue
+		} else if i%2 == 1 {
+			out.WriteString("`")
+		}
+		out.WriteString(spans(html.EscapeString(part)))
+	}
+
+	return strings.ReplaceAll(out.String(), "\n", "<br>\n")
+}
+
+// spans renders the links and emphasis of escaped text. Links are replaced
+// by placeholders while emphasis is rendered so URLs are left untouched.
+func spans(s string) string {
+	var links []string
+	placeholder := func(a string) string {
+		links = append(links, a)
+		return "\x00" + strconv.Itoa(len(links)-1) + "\x00"
+	}
+
+	s = reAutolink.ReplaceAllStringFunc(s, func(m string) string {
+		u := reAutolink.FindStringSubmatch(m)[1]
+		return placeholder(link(u, u))
+	})
+	s = reLink.ReplaceAllStringFunc(s, func(m string) string {
+		sub := reLink.FindStringSubmatch(m)
+		return placeholder(link(sub[1], sub[2]))
+	})
+
+	s = reStrong.ReplaceAllString(s, "<strong>$2</strong>")
+	s = reEmph.ReplaceAllString(s, "$1<em>$2</em>")
+
+	return rePlaceholder.ReplaceAllStringFunc(s, func(m string) string {
+		i, err := strconv.Atoi(m[1 : len(m)-1])
+		if err != nil || i >= len(links) {
+			return ""
+		}
+		return links[i]
+	})
+}
@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "heading and paragraph",
+			src:  "# Title\n\nsome *emphasis* and **strong**\ntext",
+			want: "<h1>Title</h1>\n<p>some <em>emphasis</em> and <strong>strong</strong><br>\ntext</p>\n",
+		},
+		{
+			name: "html is escaped",
+			src:  "<script>alert(1)</script> & co",
+			want: "<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp; co</p>\n",
+		},
+		{
+			name: "code",
+			src:  "run `a <b>` now\n\n```go\nif a < b {\n```",
+			want: "<p>run <code>a &lt;b&gt;</code> now</p>\n<pre><code class=\"language-go\">if a &lt; b {</code></pre>\n",
+		},
+		{
+			name: "lists",
+			src:  "- one\n- two\n\n1. first\n2. second",
+			want: "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n<ol>\n<li>first</li>\n<li>second</li>\n</ol>\n",
+		},
+		{
+			name: "blockquote and rule",
+			src:  "> quoted\n> text\n\n---",
+			want: "<blockquote>\n<p>quoted<br>\ntext</p>\n</blockquote>\n<hr>\n",
+		},
+		{
+			name: "links",
+			src:  "[docs](https://go.dev/doc/some_page_here) <https://example.com/a_b_c>",
+			want: `<p><a href="https://go.dev/doc/some_page_here" target="_blank" rel="noopener noreferrer">docs</a> ` +
+				`<a href="https://example.com/a_b_c" target="_blank" rel="noopener noreferrer">https://example.com/a_b_c</a></p>` + "\n",
+		},
+		{
+			name: "unsafe link",
+			src:  "[click](javascript:void)",
+			want: "<p>click</p>\n",
+		},
+		{
+			name: "nul placeholder",
+			src:  "hello \x007\x00 world",
+			want: "<p>hello \uFFFD7\uFFFD world</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ToHTML(tt.src))
+		})
+	}
+}
+
+func TestSpansPlaceholder(t *testing.T) {
+	assert.Equal(t, "a  b", spans("a \x007\x00 b"))
+}
@@ -0,0 +1,153 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package marktab
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/blob42/gosuki"
+)
+
+// Default time limit of test runs
+const TestRunTimeout = 30 * time.Second
+
+// PATH of test runs, the PATH of the daemon is not passed to the command
+const TestRunPath = "/usr/local/bin:/usr/bin:/bin"
+
+// Tags returns the tags passed to commands, marktab triggers starting with
+// `@` are left out.
+func Tags(bk *gosuki.Bookmark) []string {
+	var tags []string
+	for _, t := range bk.Tags {
+		if t == "" || t[0] == '@' {
+			continue
+		}
+		tags = append(tags, t)
+	}
+	return tags
+}
+
+// Env returns the `GOSUKI_*` variables passed to the command of the rule.
+// Named groups of the rule patterns are passed as `GOSUKI_MATCH_<name>`.
+func (rule Rule) Env(bk *gosuki.Bookmark, runID string) []string {
+	env := []string{
+		"GOSUKI_URL=" + bk.URL,
+		"GOSUKI_TITLE=" + bk.Title,
+		"GOSUKI_TAGS=" + strings.Join(Tags(bk), ","),
+		"GOSUKI_MODULE=" + bk.Module,
+		"GOSUKI_RUN_ID=" + runID,
+	}
+
+	captures := rule.Captures(bk)
+	for _, name := range slices.Sorted(maps.Keys(captures)) {
+		env = append(env, "GOSUKI_MATCH_"+name+"="+captures[name])
+	}
+	return env
+}
+
+// Evaluation is the result of matching a rule against a bookmark
+type Evaluation struct {
+	Rule    Rule     `json:"-"`
+	Line    int      `json:"line"`
+	Trigger string   `json:"trigger"`
+	Pattern string   `json:"pattern"`
+	Conds   []string `json:"conditions,omitempty"`
+	Matched bool     `json:"matched"`
+	Command string   `json:"command"`
+	Filter  bool     `json:"filter"`
+	Env     []string `json:"env,omitempty"`
+}
+
+// Evaluate matches every rule against the bookmark. The environment is set
+// for the rules that would fire.
+func Evaluate(rules []Rule, bk *gosuki.Bookmark) []Evaluation {
+	res := make([]Evaluation, 0, len(rules))
+	for _, rule := range rules {
+		ev := Evaluation{
+			Rule:    rule,
+			Line:    rule.Line,
+			Trigger: rule.Trigger,
+			Pattern: rule.Pattern,
+			Conds:   rule.Conditions(),
+			Matched: rule.Match(bk),
+			Command: rule.Command,
+			Filter:  rule.Filter,
+		}
+		if ev.Matched {
+			ev.Env = rule.Env(bk, "test-run")
+		}
+		res = append(res, ev)
+	}
+	return res
+}
+
+// TestRun executes the command of the rule for the bookmark and returns its
+// combined output. The command is run for real, with its side effects: it is
+// not sandboxed and can access the network and the file system.
+//
+// It runs in an empty temporary directory which is also its HOME and TMPDIR,
+// with [TestRunPath] and no other variable from the environment.
+// `GOSUKI_TEST_RUN=1` is set so scripts can tell a test from a bookmark event.
+// Filters receive the bookmark JSON on stdin, other commands get no input.
+func (rule Rule) TestRun(ctx context.Context, bk *gosuki.Bookmark, timeout time.Duration) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "gosuki-marktab-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", rule.Command)
+	cmd.Dir = dir
+	cmd.Env = append(rule.Env(bk, "test-run"),
+		"GOSUKI_TEST_RUN=1",
+		"PATH="+TestRunPath,
+		"HOME="+dir,
+		"TMPDIR="+dir,
+	)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.WaitDelay = time.Second
+
+	if rule.Filter {
+		input, err := filterInput(bk)
+		if err != nil {
+			return nil, err
+		}
+		cmd.Stdin = bytes.NewReader(input)
+	}
+
+	err = cmd.Run()
+	return out.Bytes(), err
+}
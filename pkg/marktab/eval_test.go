@@ -0,0 +1,63 @@
+package marktab
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func TestEvaluate(t *testing.T) {
+	mt, err := Parse(strings.NewReader(`notify  .*       echo hi
+archive ^ftp://  archive.sh
+`))
+	require.NoError(t, err)
+
+	bk := &gosuki.Bookmark{
+		URL:   "https://example.com",
+		Title: "Example",
+		Tags:  []string{"notify", "@archive", "web"},
+	}
+
+	evals := Evaluate(mt.Rules, bk)
+	require.Len(t, evals, 2)
+
+	assert.True(t, evals[0].Matched)
+	assert.Equal(t, 1, evals[0].Line)
+	assert.Contains(t, evals[0].Env, "GOSUKI_URL=https://example.com")
+	assert.Contains(t, evals[0].Env, "GOSUKI_TAGS=notify,web")
+
+	assert.False(t, evals[1].Matched)
+	assert.Empty(t, evals[1].Env)
+}
+
+func TestTestRun(t *testing.T) {
+	rule := Rule{
+		Trigger: "notify",
+		Pattern: ".*",
+		Command: `echo "$GOSUKI_URL $GOSUKI_TEST_RUN $SECRET"`,
+	}
+	t.Setenv("SECRET", "leaked")
+
+	out, err := rule.TestRun(context.Background(),
+		&gosuki.Bookmark{URL: "https://example.com"}, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com 1 \n", string(out))
+
+	// HOME and the working directory are an empty temporary directory
+	rule.Command = `test "$HOME" = "$(pwd)" && test -z "$(ls -A)" && echo "$PATH"`
+	t.Setenv("PATH", "/tmp/bin:"+os.Getenv("PATH"))
+	out, err = rule.TestRun(context.Background(), &gosuki.Bookmark{}, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, TestRunPath+"\n", string(out))
+
+	rule.Command = "sleep 5"
+	_, err = rule.TestRun(context.Background(), &gosuki.Bookmark{}, 100*time.Millisecond)
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package marktab
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// tagExpr is a boolean expression over the tags of a bookmark. Tags are
+// combined with `&` (and), `|` (or), `!` (not) and parentheses, `&` binds
+// tighter than `|`:
+//
+//	@archive
+//	@archive&!private
+//	(@read|@later)&work
+type tagExpr interface {
+	eval(tags []string) bool
+}
+
+type tagTerm string
+
+func (t tagTerm) eval(tags []string) bool {
+	return slices.Contains(tags, string(t))
+}
+
+type notExpr struct{ x tagExpr }
+
+func (n notExpr) eval(tags []string) bool {
+	return !n.x.eval(tags)
+}
+
+type andExpr []tagExpr
+
+func (a andExpr) eval(tags []string) bool {
+	for _, x := range a {
+		if !x.eval(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+type orExpr []tagExpr
+
+func (o orExpr) eval(tags []string) bool {
+	for _, x := range o {
+		if x.eval(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTagExpr parses a trigger. A trigger without operators is a single tag.
+func parseTagExpr(s string) (tagExpr, error) {
+	p := &exprParser{src: s}
+	x, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("unexpected `%c' at %d", p.src[p.pos], p.pos+1)
+	}
+	return x, nil
+}
+
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *exprParser) or() (tagExpr, error) {
+	var xs orExpr
+	for {
+		x, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		xs = append(xs, x)
+		if p.peek() != '|' {
+			break
+		}
+		p.pos++
+	}
+	if len(xs) == 1 {
+		return xs[0], nil
+	}
+	return xs, nil
+}
+
+func (p *exprParser) and() (tagExpr, error) {
+	var xs andExpr
+	for {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		xs = append(xs, x)
+		if p.peek() != '&' {
+			break
+		}
+		p.pos++
+	}
+	if len(xs) == 1 {
+		return xs[0], nil
+	}
+	return xs, nil
+}
+
+func (p *exprParser) unary() (tagExpr, error) {
+	switch p.peek() {
+	case '!':
+		p.pos++
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x}, nil
+	case '(':
+		p.pos++
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("missing `)' at %d", p.pos+1)
+		}
+		p.pos++
+		return x, nil
+	}
+
+	end := p.pos
+	for end < len(p.src) && !strings.ContainsRune("&|!()", rune(p.src[end])) {
+		end++
+	}
+	if end == p.pos {
+		return nil, fmt.Errorf("missing tag at %d", p.pos+1)
+	}
+	tag := p.src[p.pos:end]
+	p.pos = end
+	return tagTerm(tag), nil
+}
@@ -0,0 +1,240 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package marktab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+
+	"github.com/blob42/gosuki"
+)
+
+const (
+	// Size limit of the JSON printed by filters
+	maxFilterOutput = 1 << 20
+
+	// Cached filter results are cleared past this size
+	maxFilterResults = 10000
+)
+
+// FilterBookmark is the JSON document written on the stdin of filters
+type FilterBookmark struct {
+	URL    string   `json:"url"`
+	Title  string   `json:"title"`
+	Tags   []string `json:"tags"`
+	Desc   string   `json:"desc"`
+	Module string   `json:"module"`
+}
+
+// FilterOutput is the JSON document printed by filters on stdout. Fields that
+// are left out are not changed and an empty output keeps the bookmark as is.
+// Tags replace the tags of the bookmark, so filters can remove tags. A
+// bookmark is removed from gosuki when `drop` is true.
+//
+//	{"title": "New title", "tags": ["paper", "doi"], "desc": "...", "drop": false}
+type FilterOutput struct {
+	Title *string  `json:"title,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Desc  *string  `json:"desc,omitempty"`
+	Drop  bool     `json:"drop,omitempty"`
+}
+
+// Apply changes the bookmark with the output of a filter and reports whether
+// the bookmark changed
+func (out *FilterOutput) Apply(bk *gosuki.Bookmark) bool {
+	var changed bool
+	if out.Title != nil && *out.Title != bk.Title {
+		bk.Title = *out.Title
+		changed = true
+	}
+	if out.Desc != nil && *out.Desc != bk.Desc {
+		bk.Desc = *out.Desc
+		changed = true
+	}
+	if out.Tags != nil {
+		var tags []string
+		for _, tag := range out.Tags {
+			if tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		if !sameTags(tags, bk.Tags) {
+			bk.Tags = tags
+			changed = true
+		}
+	}
+	return changed
+}
+
+func sameTags(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+func filterInput(bk *gosuki.Bookmark) ([]byte, error) {
+	return json.Marshal(FilterBookmark{
+		URL:    bk.URL,
+		Title:  bk.Title,
+		Tags:   bk.Tags,
+		Desc:   bk.Desc,
+		Module: bk.Module,
+	})
+}
+
+// RunFilter executes the filter command of the rule with the bookmark on
+// stdin and parses its output. The run is recorded in the run history.
+func (rule Rule) RunFilter(ctx context.Context, bk *gosuki.Bookmark) (*FilterOutput, error) {
+	input, err := filterInput(bk)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := Config.FilterTimeout
+	if timeout <= 0 {
+		timeout = DefaultFilterTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	run := newRun(rule, bk)
+	run.Attempts = 1
+	log.Debug("run marktab filter", "id", run.ID, "rule", run.Trigger, "url", run.URL)
+	runCommand(ctx, timeout, rule, bk, run, bytes.NewReader(input), maxFilterOutput)
+
+	out := &FilterOutput{}
+	if run.Status == RunOK {
+		if stdout := bytes.TrimSpace([]byte(run.Stdout)); len(stdout) > 0 {
+			if err := json.Unmarshal(stdout, out); err != nil {
+				run.Status = RunFailed
+				run.Error = fmt.Sprintf("invalid filter output: %s", err)
+			}
+		}
+	}
+
+	// only the tail of the output is kept in the history
+	if len(run.Stdout) > OutputTail {
+		run.Stdout = run.Stdout[len(run.Stdout)-OutputTail:]
+	}
+	saveRun(run)
+
+	if run.Status != RunOK {
+		return nil, fmt.Errorf("filter %s: %s", run.ID, run.Error)
+	}
+	return out, nil
+}
+
+// HasFilters reports whether the rules in use have filters
+func HasFilters() bool {
+	return slices.ContainsFunc(Rules(), func(r Rule) bool { return r.Filter })
+}
+
+// Filters applies the filter rules to bookmarks. Results are cached by rule
+// and bookmark content so commands do not run again for unchanged bookmarks.
+// Bookmarks that went through the filters are not filtered again, which
+// prevents filters from retriggering themselves or each other. The cache is
+// cleared when the marktab rules change.
+type Filters struct {
+	mu      sync.Mutex
+	mt      *MarkTab
+	results map[string]*FilterOutput
+	settled map[uint64]bool
+}
+
+func NewFilters() *Filters {
+	return &Filters{
+		results: map[string]*FilterOutput{},
+		settled: map[uint64]bool{},
+	}
+}
+
+// Apply runs the matching filter rules in order on the bookmark. Each rule
+// sees the changes of the previous ones. It reports whether the bookmark
+// changed and whether it must be dropped.
+//
+// A failing filter leaves the bookmark unchanged and is not retried until the
+// bookmark changes.
+func (f *Filters) Apply(ctx context.Context, bk *gosuki.Bookmark) (changed bool, drop bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	mt := current.Load()
+	if mt != f.mt || len(f.results) > maxFilterResults {
+		f.mt = mt
+		clear(f.results)
+		clear(f.settled)
+	}
+	if mt == nil {
+		return false, false
+	}
+
+	if f.settled[bookmarkHash(bk)] {
+		return false, false
+	}
+
+	for _, rule := range mt.Rules {
+		if !rule.Filter || !rule.Match(bk) {
+			continue
+		}
+
+		key := fmt.Sprintf("%d:%x", rule.Line, bookmarkHash(bk))
+		out, ok := f.results[key]
+		if !ok {
+			var err error
+			if out, err = rule.RunFilter(ctx, bk); err != nil {
+				log.Error("marktab filter", "line", rule.Line, "url", bk.URL, "err", err)
+				out = &FilterOutput{}
+			}
+			f.results[key] = out
+		}
+
+		if out.Drop {
+			return changed, true
+		}
+		if out.Apply(bk) {
+			changed = true
+		}
+	}
+
+	f.settled[bookmarkHash(bk)] = true
+	return changed, false
+}
+
+// bookmarkHash identifies the content of a bookmark seen by filters
+func bookmarkHash(bk *gosuki.Bookmark) uint64 {
+	tags := slices.Clone(bk.Tags)
+	slices.Sort(tags)
+
+	h := fnv.New64a()
+	for _, s := range append([]string{bk.URL, bk.Title, bk.Desc}, tags...) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
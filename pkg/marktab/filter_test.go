@@ -0,0 +1,111 @@
+package marktab
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func useRules(t *testing.T, text string) {
+	t.Helper()
+	mt, err := Parse(strings.NewReader(text))
+	require.NoError(t, err)
+
+	prev := current.Load()
+	current.Store(mt)
+	t.Cleanup(func() { current.Store(prev) })
+}
+
+func TestParseFilter(t *testing.T) {
+	mt, err := Parse(strings.NewReader("@doi .* |  doi.sh --json\n"))
+	require.NoError(t, err)
+	require.Len(t, mt.Rules, 1)
+	assert.True(t, mt.Rules[0].Filter)
+	assert.Equal(t, "doi.sh --json", mt.Rules[0].Command)
+
+	_, err = Parse(strings.NewReader("@doi .* |\n"))
+	assert.Error(t, err)
+}
+
+func TestFilters(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+
+	// the filter appends to the title, running it on its own output would
+	// never settle
+	useRules(t, `
+@doi  .*  | echo run >> `+counter+`; sed 's/.*"title":"\([^"]*\)".*/{"title":"\1 [doi]","tags":["@doi","paper"]}/'
+@spam .*  | echo '{"drop": true}'
+@bad  .*  | echo not json
+notify .* echo not a filter
+`)
+
+	filters := NewFilters()
+	ctx := context.Background()
+
+	t.Run("changes bookmark", func(t *testing.T) {
+		bk := &gosuki.Bookmark{URL: "https://arxiv.org/abs/1", Title: "Paper", Tags: []string{"@doi"}}
+		changed, drop := filters.Apply(ctx, bk)
+		assert.True(t, changed)
+		assert.False(t, drop)
+		assert.Equal(t, "Paper [doi]", bk.Title)
+		assert.ElementsMatch(t, []string{"@doi", "paper"}, bk.Tags)
+
+		// the output of filters is not filtered again
+		changed, _ = filters.Apply(ctx, bk)
+		assert.False(t, changed)
+		assert.Equal(t, "Paper [doi]", bk.Title)
+
+		// same input reuses the cached result
+		bk = &gosuki.Bookmark{URL: "https://arxiv.org/abs/1", Title: "Paper", Tags: []string{"@doi"}}
+		changed, _ = filters.Apply(ctx, bk)
+		assert.True(t, changed)
+		assert.Equal(t, "Paper [doi]", bk.Title)
+
+		runs, err := os.ReadFile(counter)
+		require.NoError(t, err)
+		assert.Equal(t, "run\n", string(runs))
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		_, drop := filters.Apply(ctx, &gosuki.Bookmark{URL: "https://spam.example", Tags: []string{"@spam"}})
+		assert.True(t, drop)
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		bk := &gosuki.Bookmark{URL: "https://example.com", Title: "Example", Tags: []string{"@bad", "notify"}}
+		changed, drop := filters.Apply(ctx, bk)
+		assert.False(t, changed)
+		assert.False(t, drop)
+		assert.Equal(t, "Example", bk.Title)
+	})
+}
+
+func TestFilterOutputApply(t *testing.T) {
+	title := "New"
+	bk := &gosuki.Bookmark{Title: "Old", Desc: "desc", Tags: []string{"a"}}
+
+	out := &FilterOutput{Title: &title, Tags: []string{"a", "b"}}
+	assert.True(t, out.Apply(bk))
+	assert.Equal(t, "New", bk.Title)
+	assert.Equal(t, "desc", bk.Desc)
+	assert.Equal(t, []string{"a", "b"}, bk.Tags)
+
+	assert.False(t, out.Apply(bk))
+	assert.False(t, (&FilterOutput{}).Apply(bk))
+
+	// tags are replaced
+	out = &FilterOutput{Tags: []string{"b", "c", "c"}}
+	assert.True(t, out.Apply(bk))
+	assert.Equal(t, []string{"b", "c"}, bk.Tags)
+
+	out = &FilterOutput{Tags: []string{}}
+	assert.True(t, out.Apply(bk))
+	assert.Empty(t, bk.Tags)
+}
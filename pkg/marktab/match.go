@@ -26,24 +26,141 @@ import (
 	"regexp"
 
 	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
 )
 
-// Match checks if a bookmark matches the rule based on its title, URL, and
-// tags. It returns true if both the trigger (part of the tag) or the pattern
-// match is satisfied, otherwise it returns false.
+// Keys of the optional `key=value` conditions of a rule
+const (
+	condModule = "module"
+	condDomain = "domain"
+	condURL    = "url"
+	condTitle  = "title"
+)
+
+// Conditions returns the `key=value` conditions of the rule
+func (rule Rule) Conditions() []string {
+	var conds []string
+	for _, kv := range [][2]string{
+		{condModule, rule.Module},
+		{condDomain, rule.Domain},
+		{condURL, rule.URL},
+		{condTitle, rule.Title},
+	} {
+		if kv[1] != "" {
+			conds = append(conds, kv[0]+"="+kv[1])
+		}
+	}
+	return conds
+}
+
+// compiled holds the parsed trigger and regular expressions of a rule
+type compiled struct {
+	trigger tagExpr
+	pattern *regexp.Regexp
+	url     *regexp.Regexp
+	title   *regexp.Regexp
+}
+
+// compile parses the trigger and the patterns of the rule
+func (rule Rule) compile() (*compiled, error) {
+	var err error
+	c := &compiled{}
+
+	if c.trigger, err = parseTagExpr(rule.Trigger); err != nil {
+		return nil, MarktabError{ErrorType: ErrBadTrigger, Rule: &rule, err: err}
+	}
+	if c.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+		return nil, errBadPattern(rule.Pattern, err)
+	}
+	if rule.URL != "" {
+		if c.url, err = regexp.Compile(rule.URL); err != nil {
+			return nil, errBadPattern(rule.URL, err)
+		}
+	}
+	if rule.Title != "" {
+		if c.title, err = regexp.Compile(rule.Title); err != nil {
+			return nil, errBadPattern(rule.Title, err)
+		}
+	}
+	for _, glob := range []string{rule.Module, rule.Domain} {
+		if err = utils.CheckPattern(glob); err != nil {
+			return nil, errBadPattern(glob, err)
+		}
+	}
+
+	return c, nil
+}
+
+// compiled returns the rule compiled at load time. Rules built by hand are
+// compiled on every call.
+func (rule Rule) compiled() *compiled {
+	if rule.c != nil {
+		return rule.c
+	}
+	c, err := rule.compile()
+	if err != nil {
+		log.Error("marktab rule", "line", rule.Line, "err", err)
+		return nil
+	}
+	return c
+}
+
+// Match checks if a bookmark matches the rule: the trigger expression must
+// match the bookmark tags, the pattern must match the URL or the title and
+// all the `key=value` conditions must hold.
 func (rule Rule) Match(bk *gosuki.Bookmark) bool {
-	var triggerMatch bool
+	_, ok := rule.match(bk)
+	return ok
+}
+
+// Captures returns the named groups of the rule patterns matched by the
+// bookmark, they are passed to commands as `GOSUKI_MATCH_<name>`.
+func (rule Rule) Captures(bk *gosuki.Bookmark) map[string]string {
+	captures, _ := rule.match(bk)
+	return captures
+}
+
+func (rule Rule) match(bk *gosuki.Bookmark) (map[string]string, bool) {
 	if bk == nil {
+		return nil, false
+	}
+	c := rule.compiled()
+	if c == nil || !c.trigger.eval(bk.Tags) {
+		return nil, false
+	}
+
+	if rule.Module != "" && !utils.MatchModule(rule.Module, bk.Module) {
+		return nil, false
+	}
+	if rule.Domain != "" && !utils.MatchDomain(rule.Domain, bk.URL) {
+		return nil, false
+	}
+
+	captures := map[string]string{}
+	if !capture(c.pattern, bk.URL, captures) && !capture(c.pattern, bk.Title, captures) {
+		return nil, false
+	}
+	if c.url != nil && !capture(c.url, bk.URL, captures) {
+		return nil, false
+	}
+	if c.title != nil && !capture(c.title, bk.Title, captures) {
+		return nil, false
+	}
+
+	return captures, true
+}
+
+// capture matches the regexp against s and adds its named groups to
+// `captures`
+func capture(re *regexp.Regexp, s string, captures map[string]string) bool {
+	m := re.FindStringSubmatch(s)
+	if m == nil {
 		return false
 	}
-	for _, tag := range bk.Tags {
-		if rule.Trigger == tag {
-			triggerMatch = true
+	for i, name := range re.SubexpNames() {
+		if name != "" && i < len(m) {
+			captures[name] = m[i]
 		}
 	}
-	patternMatch := rule.Pattern == "" || regexp.MustCompile(rule.Pattern).MatchString(bk.URL) || regexp.MustCompile(rule.Pattern).MatchString(bk.Title)
-	if triggerMatch && patternMatch {
-		return true
-	}
-	return false
+	return true
 }
@@ -0,0 +1,119 @@
+package marktab
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+func parseRule(t *testing.T, line string) Rule {
+	t.Helper()
+	mt, err := Parse(strings.NewReader(line))
+	require.NoError(t, err)
+	require.Len(t, mt.Rules, 1)
+	require.NotNil(t, mt.Rules[0].c, "rule not compiled at load")
+	return mt.Rules[0]
+}
+
+func TestMatch(t *testing.T) {
+	bk := &gosuki.Bookmark{
+		URL:    "https://github.com/blob42/gosuki/issues/42",
+		Title:  "Marktab issue",
+		Tags:   []string{"@archive", "work", "Dev"},
+		Module: "firefox_work",
+	}
+
+	tests := []struct {
+		line  string
+		match bool
+	}{
+		// legacy three field rules
+		{"@archive .* cmd", true},
+		{"@archive ^ftp cmd", false},
+		{"@archive issue cmd", true},
+		{"notify .* cmd", false},
+
+		// tag expressions
+		{"@archive&work .* cmd", true},
+		{"@archive&!work .* cmd", false},
+		{"notify|@archive .* cmd", true},
+		{"(notify|@later)&work .* cmd", false},
+		{"!(notify|private)&Dev .* cmd", true},
+
+		// conditions
+		{"@archive module=firefox:work .* cmd", true},
+		{"@archive module=firefox .* cmd", true},
+		{"@archive module=chrome .* cmd", false},
+		{"@archive module=firefox:home .* cmd", false},
+		{"@archive domain=*.github.com .* cmd", false},
+		{"@archive domain=github.com .* cmd", true},
+		{"@archive domain=git*.com .* cmd", true},
+		{"@archive url=issues/\\d+ .* cmd", true},
+		{"@archive title=issues .* cmd", false},
+		{"@archive title=^Marktab url=github .* cmd", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			assert.Equal(t, tt.match, parseRule(t, tt.line).Match(bk))
+		})
+	}
+
+	t.Run("subdomain", func(t *testing.T) {
+		rule := parseRule(t, "@archive domain=github.com .* cmd")
+		assert.True(t, rule.Match(&gosuki.Bookmark{URL: "https://gist.github.com/x", Tags: []string{"@archive"}}))
+		assert.False(t, rule.Match(&gosuki.Bookmark{URL: "https://notgithub.com/x", Tags: []string{"@archive"}}))
+	})
+
+	t.Run("legacy pattern with equal sign", func(t *testing.T) {
+		rule := parseRule(t, "@archive a=b cmd")
+		assert.Equal(t, "a=b", rule.Pattern)
+		assert.Equal(t, "cmd", rule.Command)
+
+		rule = parseRule(t, "@archive url=x cmd")
+		assert.Equal(t, "url=x", rule.Pattern)
+		assert.Empty(t, rule.URL)
+	})
+
+	t.Run("hand built rule", func(t *testing.T) {
+		assert.True(t, Rule{Trigger: "@archive&work", Pattern: "github"}.Match(bk))
+	})
+}
+
+func TestCaptures(t *testing.T) {
+	rule := parseRule(t,
+		`@archive url=github\.com/(?P<repo>[^/]+/[^/]+)/issues/(?P<id>\d+) title=(?P<word>^\w+) .* cmd`)
+
+	bk := &gosuki.Bookmark{
+		URL:   "https://github.com/blob42/gosuki/issues/42",
+		Title: "Marktab issue",
+		Tags:  []string{"@archive"},
+	}
+	assert.Equal(t, map[string]string{
+		"repo": "blob42/gosuki",
+		"id":   "42",
+		"word": "Marktab",
+	}, rule.Captures(bk))
+
+	env := rule.Env(bk, "run")
+	assert.Contains(t, env, "GOSUKI_MATCH_repo=blob42/gosuki")
+	assert.Contains(t, env, "GOSUKI_MATCH_id=42")
+	assert.Contains(t, env, "GOSUKI_MATCH_word=Marktab")
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	for _, line := range []string{
+		"@archive& .* cmd",
+		"(@archive .* cmd",
+		"@archive|) .* cmd",
+		"@archive url=( .* cmd",
+		"@archive domain=[ .* cmd",
+	} {
+		_, err := Parse(strings.NewReader(line))
+		assert.Error(t, err, line)
+	}
+}
@@ -43,21 +43,57 @@
 //
 // The keyword to detect in the bookmark tags. When gosuki parses bookmark tags and finds this trigger keyword, it evaluates the pattern rule. If the pattern matches, the corresponding command is executed.
 //
+// The trigger can be a boolean expression of tags using `&` (and), `|` (or),
+// `!` (not) and parentheses. Folders are stored as tags so they can be part
+// of the expression:
+//
+//	@archive&!private	.*	archive.sh
+//	(@read|@later)&work	.*	notify.sh
+//
+// # Conditions:
+//
+// Optional `key=value` fields between the trigger and the pattern restrict
+// the bookmarks matched by the rule, all of them must match:
+//
+//   - module=firefox:work: module and profile globs of the bookmark source
+//   - domain=*.github.com: glob on the URL host, a plain domain also matches its subdomains
+//   - url=REGEXP: regular expression matched against the URL only
+//   - title=REGEXP: regular expression matched against the title only
+//
+// Example:
+//
+//	@archive module=firefox:work domain=*.github.com  .*  archive.sh
+//
 // # Pattern:
 //
 // A regular expression used for matching against a part of the bookmark URL or title. Once a trigger is detected, the pattern is evaluated to determine if there's a match with the bookmark data.
 //
+// Named groups of the pattern and of the url and title conditions are passed
+// to the command as `GOSUKI_MATCH_<name>`:
+//
+//	@issue	url=github\.com/(?P<repo>[^/]+/[^/]+)/issues/(?P<id>\d+)	.*	echo $GOSUKI_MATCH_repo $GOSUKI_MATCH_id
+//
 // # Command:
 //
 // The shell command to execute when both the trigger and pattern are matched in a bookmark tag. This command can be any valid shell command and it allows for flexibility in performing various actions.
+//
+// # Filters:
+//
+// A command starting with `|` is a filter: it receives the bookmark as JSON
+// on stdin and may print a JSON document changing the title, tags or
+// description of the bookmark, or dropping it. Filters run in the background
+// on the bookmarks changed by a sync to the cache, see [FilterOutput].
+//
+//	@doi	arxiv\.org	| doi-lookup.sh
 package marktab
 
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/pkg/logging"
@@ -69,11 +105,20 @@ type MarkTab struct {
 }
 
 type Rule struct {
-	Trigger string // keyword to detect in the bookmark tags
+	Trigger string // tag expression to detect in the bookmark tags
 	Pattern string // regular expression used for matching against the bookmark URL or title.
 	Command string // shell command to execute when both the trigger and pattern match the bookmark tags.
+	Line    int    // line number in the marktab file
+	Filter  bool   // the command is a filter, its output changes the bookmark
+
+	// Optional conditions written as `key=value` fields before the pattern
+	Module string // `name[:profile]` glob matched against the bookmark module
+	Domain string // glob matched against the host of the URL
+	URL    string // regular expression matched against the URL
+	Title  string // regular expression matched against the title
 
-	empty bool // empty is an unexported field indicating whether the rule is empty.
+	c     *compiled // trigger and patterns compiled when the rule is parsed
+	empty bool      // empty is an unexported field indicating whether the rule is empty.
 }
 
 const marktabPath = "~/.config/gosuki/marktab"
@@ -95,8 +140,10 @@ const (
 const InvalidFormat = "invalid format"
 
 var (
-	log         = logging.GetLogger("marktab")
-	CachedRules *MarkTab
+	log = logging.GetLogger("marktab")
+
+	// rules in use, swapped on reload
+	current atomic.Pointer[MarkTab]
 )
 
 type MarktabError struct {
@@ -114,98 +161,224 @@ func errInvalidFormat(context string, a ...any) error {
 	return fmt.Errorf("\n %s\n\ninvalid format", context)
 }
 
-func errBadPattern(pat string, context string, err error) error {
+func errBadPattern(pat string, err error) error {
 	return MarktabError{
 		ErrorType: ErrBadPattern,
 		Rule:      &Rule{Pattern: pat},
-		Context:   context,
 		err:       err,
-	}.Error()
+	}
 }
 
-// TODO:
-func (mte MarktabError) Error() error {
+func (mte MarktabError) Error() string {
 	var outErr string
 	switch mte.ErrorType {
 	case ErrBadPattern:
-		outErr = fmt.Sprintf("\n %s\n\ninvalid pattern: `%s'", mte.Context, mte.Rule.Pattern)
+		outErr = fmt.Sprintf("invalid pattern `%s'", mte.Rule.Pattern)
 	case ErrBadTrigger:
-		outErr = fmt.Sprintf(" %s\ninvalid trigger: `%s'", mte.Context, mte.Rule.Trigger)
+		outErr = fmt.Sprintf("invalid trigger `%s'", mte.Rule.Trigger)
 	case ErrBadRule:
-		outErr = fmt.Sprintf(" %s\ninvalid rule", mte.Context)
+		outErr = "invalid rule"
+	}
+	if mte.Context != "" {
+		outErr = fmt.Sprintf("%s (%s)", outErr, mte.Context)
 	}
 	if mte.err != nil {
-		return fmt.Errorf("%s : %w", outErr, mte.err)
+		return fmt.Sprintf("%s: %s", outErr, mte.err)
 	}
 
-	return fmt.Errorf("%s", outErr)
+	return outErr
+}
+
+func (mte MarktabError) Unwrap() error {
+	return mte.err
 }
 
 func PreloadRules() error {
-	if CachedRules == nil {
-		CachedRules = &MarkTab{}
-		return CachedRules.LoadMarktabs()
+	if current.Load() == nil {
+		return Reload()
+	}
+	return nil
+}
+
+// Rules returns the rules currently in use
+func Rules() []Rule {
+	if mt := current.Load(); mt != nil {
+		return mt.Rules
+	}
+	return nil
+}
+
+// Path returns the expanded path of the marktab file, the file might not
+// exist.
+func Path() (string, error) {
+	return utils.ExpandOnly(marktabPath)
+}
+
+// Reload parses the marktab file and swaps the rules in use. If the file is
+// invalid the previous rules are kept and the parsing errors are returned. A
+// missing file clears the rules.
+func Reload() error {
+	mt := &MarkTab{}
+	if err := mt.LoadMarktabs(); err != nil {
+		if current.Load() == nil {
+			current.Store(&MarkTab{})
+		}
+		return err
 	}
+
+	current.Store(mt)
+	log.Debug("loaded marktab", "rules", len(mt.Rules))
 	return nil
 }
 
 func (mt *MarkTab) LoadMarktabs() error {
-	path, err := utils.ExpandPath(marktabPath)
+	path, err := Path()
+	if err != nil {
+		return fmt.Errorf("reading %s : %w ", marktabPath, err)
+	}
+	file, err := os.Open(path)
 	if os.IsNotExist(err) {
 		log.Infof("skipping marktab, not found: %v", marktabPath)
 		return nil
-
 	} else if err != nil {
-		return fmt.Errorf("reading %s : %w ", marktabPath, err)
+		return err
 	}
-	file, err := os.Open(path)
+	defer file.Close()
+
+	parsed, err := Parse(file)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	mt.Rules = parsed.Rules
+
+	return nil
+}
+
+// LineError is the error of an invalid marktab line
+type LineError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors holds the errors of all invalid lines of a marktab
+type ParseErrors []LineError
+
+func (errs ParseErrors) Error() string {
+	lines := make([]string, 0, len(errs))
+	for _, e := range errs {
+		lines = append(lines, e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Parse reads a marktab. All lines are parsed, the errors of invalid lines
+// are returned together as [ParseErrors].
+func Parse(r io.Reader) (*MarkTab, error) {
+	mt := &MarkTab{}
+	var errs ParseErrors
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
 		line := scanner.Text()
 		rule, err := parseLine(line)
 		if err != nil {
-			return err
+			errs = append(errs, LineError{Line: n, Text: line, Err: err})
+			continue
 		}
 		if !rule.empty {
+			rule.Line = n
 			mt.Rules = append(mt.Rules, rule)
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	if len(errs) > 0 {
+		return mt, errs
+	}
+	return mt, nil
 }
 
 func parseLine(line string) (Rule, error) {
 	line = skipComments(line)
-	if len(line) == 0 {
+	if len(strings.TrimSpace(line)) == 0 {
 		return Rule{empty: true}, nil
 	}
 
 	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return Rule{}, MarktabError{
+			ErrorType: ErrBadRule,
+			Context:   "expected: trigger [key=value...] pattern command",
+		}
+	}
+
+	rule := Rule{Trigger: fields[0]}
+	fields = fields[1:]
 
-	// if len(fields) != 3 {
-	// 	return Rule{}, errInvalidFormat(line, "wrong number of fields")
-	// }
+	// key=value conditions between the trigger and the pattern
+	for len(fields) > 2 {
+		key, value, ok := strings.Cut(fields[0], "=")
+		if !ok {
+			break
+		}
+		switch key {
+		case condModule:
+			rule.Module = value
+		case condDomain:
+			rule.Domain = value
+		case condURL:
+			rule.URL = value
+		case condTitle:
+			rule.Title = value
+		default:
+			ok = false
+		}
+		if !ok {
+			break
+		}
+		fields = fields[1:]
+	}
 
-	trigger := fields[0]
-	pattern := fields[1]
-	command := strings.Join(fields[2:], " ")
+	if len(fields) < 2 {
+		return Rule{}, MarktabError{
+			ErrorType: ErrBadRule,
+			Context:   "expected: trigger [key=value...] pattern command",
+		}
+	}
+
+	rule.Pattern = fields[0]
+	rule.Command = strings.Join(fields[1:], " ")
+
+	if strings.HasPrefix(rule.Command, "|") {
+		rule.Filter = true
+		rule.Command = strings.TrimSpace(rule.Command[1:])
+		if rule.Command == "" {
+			return Rule{}, MarktabError{
+				ErrorType: ErrBadRule,
+				Context:   "missing filter command",
+			}
+		}
+	}
 
-	// Validate pattern (basic check, can be extended based on requirements)
-	_, err := regexp.Compile(pattern)
+	c, err := rule.compile()
 	if err != nil {
-		return Rule{}, errBadPattern(pattern, line, err)
+		return Rule{}, err
 	}
+	rule.c = c
 
-	return Rule{Trigger: trigger, Pattern: pattern, Command: command}, nil
+	return rule, nil
 }
 
 func skipComments(line string) string {
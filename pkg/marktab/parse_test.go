@@ -0,0 +1,66 @@
+package marktab
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	mt, err := Parse(strings.NewReader(`# comment
+notify  .*  echo hi
+bad     (foo  cmd
+only two
+
+archive ^https://  archive.sh "$GOSUKI_URL"
+`))
+
+	var errs ParseErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+
+	assert.Equal(t, 3, errs[0].Line)
+	assert.Equal(t, "bad     (foo  cmd", errs[0].Text)
+	assert.Contains(t, errs[0].Error(), "missing closing )")
+
+	assert.Equal(t, 4, errs[1].Line)
+	var mtErr MarktabError
+	require.True(t, errors.As(errs[1], &mtErr))
+	assert.Equal(t, ErrBadRule, mtErr.ErrorType)
+
+	require.Len(t, mt.Rules, 2)
+	assert.Equal(t, `archive.sh "$GOSUKI_URL"`, mt.Rules[1].Command)
+}
+
+func TestReload(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	defer current.Store(nil)
+
+	path := filepath.Join(home, ".config", "gosuki", "marktab")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+
+	require.NoError(t, os.WriteFile(path, []byte("notify .* echo hi\n"), 0o644))
+	require.NoError(t, Reload())
+	require.Len(t, Rules(), 1)
+
+	// an invalid marktab keeps the previous rules
+	require.NoError(t, os.WriteFile(path, []byte("notify ( echo hi\n"), 0o644))
+	assert.Error(t, Reload())
+	require.Len(t, Rules(), 1)
+	assert.Equal(t, ".*", Rules()[0].Pattern)
+
+	require.NoError(t, os.WriteFile(path, []byte("a .* x\nb .* y\n"), 0o644))
+	require.NoError(t, Reload())
+	assert.Len(t, Rules(), 2)
+
+	// a removed marktab clears the rules
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, Reload())
+	assert.Empty(t, Rules())
+}
@@ -0,0 +1,346 @@
+//
+//  Copyright (c) 2024-2025 Chakib Ben Ziane <contact@blob42.xyz>  and [`gosuki` contributors](https://github.com/blob42/gosuki/graphs/contributors).
+//  All rights reserved.
+//
+//  SPDX-License-Identifier: AGPL-3.0-or-later
+//
+//  This file is part of GoSuki.
+//
+//  GoSuki is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Affero General Public License as
+//  published by the Free Software Foundation, either version 3 of the
+//  License, or (at your option) any later version.
+//
+//  GoSuki is distributed in the hope that it will be useful,
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+//  GNU Affero General Public License for more details.
+//
+//  You should have received a copy of the GNU Affero General Public License
+//  along with gosuki.  If not, see <http://www.gnu.org/licenses/>.
+//
+
+package marktab
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/config"
+)
+
+const (
+	DefaultConcurrency = 2
+	DefaultTimeout     = 5 * time.Minute
+	DefaultBackoff     = 10 * time.Second
+	DefaultMaxRuns     = 1000
+
+	DefaultFilterTimeout  = 10 * time.Second
+	DefaultFilterDeadline = time.Minute
+
+	// Bytes of stdout and stderr kept for each run
+	OutputTail = 4096
+
+	queueLen = 256
+)
+
+// Run states
+const (
+	RunQueued  = "queued"
+	RunRunning = "running"
+	RunOK      = "ok"
+	RunFailed  = "failed"
+	RunTimeout = "timeout"
+	RunDropped = "dropped"
+)
+
+var Config *MarktabConfig
+
+type MarktabConfig struct {
+	// Number of commands running at the same time
+	Concurrency int `toml:"concurrency" mapstructure:"concurrency"`
+
+	// Time limit of commands, per trigger limits are set in rule-timeouts
+	Timeout      time.Duration            `toml:"timeout" mapstructure:"timeout"`
+	RuleTimeouts map[string]time.Duration `toml:"rule-timeouts" mapstructure:"rule-timeouts"`
+
+	// Failed commands are retried this number of times, waiting `backoff`
+	// before the first retry and doubling the wait after each attempt
+	Retries int           `toml:"retries" mapstructure:"retries"`
+	Backoff time.Duration `toml:"backoff" mapstructure:"backoff"`
+
+	// Number of runs kept in the run history, 0 for no limit
+	MaxRuns int `toml:"max-runs" mapstructure:"max-runs"`
+
+	// Time limit of filter commands. Filters are never retried.
+	FilterTimeout time.Duration `toml:"filter-timeout" mapstructure:"filter-timeout"`
+
+	// Time limit of a round of filters over the changed bookmarks, the
+	// bookmarks left are filtered in the next round
+	FilterDeadline time.Duration `toml:"filter-deadline" mapstructure:"filter-deadline"`
+}
+
+func (c *MarktabConfig) timeout(rule Rule) time.Duration {
+	if t, ok := c.RuleTimeouts[rule.Trigger]; ok && t > 0 {
+		return t
+	}
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return DefaultTimeout
+}
+
+// Run is the record of a marktab command execution. Runs are identified by
+// the GOSUKI_RUN_ID passed to the command, retries keep the same ID.
+type Run struct {
+	ID       string `db:"run_id" json:"id"`
+	Trigger  string `db:"rule" json:"trigger"`
+	Pattern  string `db:"pattern" json:"pattern"`
+	Command  string `db:"command" json:"command"`
+	Line     int    `db:"line" json:"line"`
+	URL      string `db:"URL" json:"url"`
+	Status   string `db:"status" json:"status"`
+	ExitCode int    `db:"exit_code" json:"exit_code"`
+	Attempts int    `db:"attempts" json:"attempts"`
+
+	// unix timestamp of the first attempt
+	Started int64 `db:"started" json:"started"`
+
+	// duration of the last attempt in milliseconds
+	Duration int64 `db:"duration" json:"duration"`
+
+	Stdout string `db:"stdout" json:"stdout"`
+	Stderr string `db:"stderr" json:"stderr"`
+	Error  string `db:"error" json:"error"`
+}
+
+// RunStore persists runs
+type RunStore interface {
+	SaveRun(*Run) error
+}
+
+var (
+	store   RunStore
+	storeMu sync.RWMutex
+
+	queue     chan job
+	queueOnce sync.Once
+)
+
+// SetRunStore sets where runs are saved
+func SetRunStore(s RunStore) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	store = s
+}
+
+func saveRun(run *Run) {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	if store == nil {
+		return
+	}
+	if err := store.SaveRun(run); err != nil {
+		log.Error("saving marktab run", "id", run.ID, "err", err)
+	}
+}
+
+type job struct {
+	rule Rule
+	bk   *gosuki.Bookmark
+	run  *Run
+
+	// wait before the next retry
+	backoff time.Duration
+}
+
+// tail keeps the last bytes written to it
+type tail struct {
+	max int
+	buf []byte
+}
+
+func (t *tail) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.max {
+		t.buf = t.buf[len(t.buf)-t.max:]
+	}
+	return len(p), nil
+}
+
+func newRun(rule Rule, bk *gosuki.Bookmark) *Run {
+	return &Run{
+		ID:      utils.GenStringID(8),
+		Trigger: rule.Trigger,
+		Pattern: rule.Pattern,
+		Command: rule.Command,
+		Line:    rule.Line,
+		URL:     bk.URL,
+		Status:  RunQueued,
+		Started: time.Now().Unix(),
+	}
+}
+
+// Enqueue schedules the command of the rule for the bookmark and returns the
+// run ID. Workers are started on the first call. If the queue is full the run
+// is recorded as dropped.
+func Enqueue(rule Rule, bk *gosuki.Bookmark) string {
+	queueOnce.Do(startWorkers)
+
+	run := newRun(rule, bk)
+
+	// saved before queuing, the run is owned by the worker afterwards
+	saveRun(run)
+
+	push(job{rule: rule, bk: bk, run: run})
+
+	return run.ID
+}
+
+// push queues the job. If the queue is full the run is recorded as dropped.
+func push(j job) {
+	select {
+	case queue <- j:
+	default:
+		log.Error("marktab queue is full, dropping run", "id", j.run.ID, "url", j.bk.URL)
+		j.run.Status = RunDropped
+		saveRun(j.run)
+	}
+}
+
+func startWorkers() {
+	queue = make(chan job, queueLen)
+
+	n := Config.Concurrency
+	if n <= 0 {
+		n = DefaultConcurrency
+	}
+	for range n {
+		go func() {
+			for j := range queue {
+				execute(j)
+			}
+		}()
+	}
+}
+
+// execute runs one attempt of the job. Failed attempts are queued again after
+// an exponential backoff, the worker is free in the meantime. A run waiting
+// for a retry is saved as queued with the error of the last attempt.
+func execute(j job) {
+	run := j.run
+	run.Attempts++
+	run.Status = RunRunning
+	saveRun(run)
+
+	attempt(j)
+
+	if run.Status == RunOK || run.Attempts > Config.Retries {
+		saveRun(run)
+		if run.Status != RunOK {
+			log.Error("marktab run failed", "id", run.ID, "rule", run.Trigger,
+				"url", run.URL, "status", run.Status, "err", run.Error)
+		}
+		return
+	}
+
+	if j.backoff <= 0 {
+		j.backoff = Config.Backoff
+	}
+	if j.backoff <= 0 {
+		j.backoff = DefaultBackoff
+	}
+
+	log.Warn("retrying marktab run", "id", run.ID, "in", j.backoff, "err", run.Error)
+	run.Status = RunQueued
+	saveRun(run)
+
+	retry := j
+	retry.backoff *= 2
+	time.AfterFunc(j.backoff, func() { push(retry) })
+}
+
+func attempt(j job) {
+	timeout := Config.timeout(j.rule)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Debug("run marktab", "id", j.run.ID, "rule", j.run.Trigger, "url", j.run.URL,
+		"attempt", j.run.Attempts)
+
+	runCommand(ctx, timeout, j.rule, j.bk, j.run, nil, OutputTail)
+}
+
+// runCommand executes the command of the rule and records the result in
+// `run`. At most `maxStdout` bytes of stdout are kept.
+func runCommand(
+	ctx context.Context,
+	timeout time.Duration,
+	rule Rule,
+	bk *gosuki.Bookmark,
+	run *Run,
+	stdin io.Reader,
+	maxStdout int,
+) {
+	stdout := &tail{max: maxStdout}
+	stderr := &tail{max: OutputTail}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", rule.Command)
+	cmd.Env = append(os.Environ(), rule.Env(bk, run.ID)...)
+	cmd.Env = append(cmd.Env, "GOSUKI_ATTEMPT="+strconv.Itoa(run.Attempts))
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	// kill the whole process group on timeout, children of the shell would
+	// otherwise keep the output pipes open
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = 5 * time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+	run.Duration = time.Since(start).Milliseconds()
+	run.Stdout = string(stdout.buf)
+	run.Stderr = string(stderr.buf)
+	run.ExitCode = 0
+	if cmd.ProcessState != nil {
+		run.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	switch {
+	case err == nil:
+		run.Status = RunOK
+		run.Error = ""
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		run.Status = RunTimeout
+		run.Error = "timed out after " + timeout.String()
+	default:
+		run.Status = RunFailed
+		run.Error = err.Error()
+	}
+}
+
+func init() {
+	Config = &MarktabConfig{
+		Concurrency:    DefaultConcurrency,
+		Timeout:        DefaultTimeout,
+		RuleTimeouts:   map[string]time.Duration{},
+		Backoff:        DefaultBackoff,
+		MaxRuns:        DefaultMaxRuns,
+		FilterTimeout:  DefaultFilterTimeout,
+		FilterDeadline: DefaultFilterDeadline,
+	}
+	config.RegisterConfigurator("marktab", config.AsConfigurator(Config))
+}
@@ -0,0 +1,146 @@
+package marktab
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+type memStore struct {
+	sync.Mutex
+	saved []Run
+}
+
+func (s *memStore) SaveRun(run *Run) error {
+	s.Lock()
+	defer s.Unlock()
+	s.saved = append(s.saved, *run)
+	return nil
+}
+
+func (s *memStore) last() (Run, bool) {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.saved) == 0 {
+		return Run{}, false
+	}
+	return s.saved[len(s.saved)-1], true
+}
+
+func (s *memStore) statuses() []string {
+	s.Lock()
+	defer s.Unlock()
+	var res []string
+	for _, r := range s.saved {
+		res = append(res, r.Status)
+	}
+	return res
+}
+
+// runJob queues the command and waits for its run to finish
+func runJob(t *testing.T, command string) (Run, *memStore) {
+	t.Helper()
+	s := &memStore{}
+	SetRunStore(s)
+	t.Cleanup(func() { SetRunStore(nil) })
+
+	rule := Rule{Trigger: "test", Pattern: ".*", Command: command, Line: 3}
+	Enqueue(rule, &gosuki.Bookmark{URL: "https://example.com"})
+
+	var run Run
+	require.Eventually(t, func() bool {
+		var ok bool
+		run, ok = s.last()
+		return ok && run.Status != RunQueued && run.Status != RunRunning
+	}, 10*time.Second, 5*time.Millisecond)
+	return run, s
+}
+
+func TestExecute(t *testing.T) {
+	prev := *Config
+	t.Cleanup(func() { *Config = prev })
+	Config.Retries = 2
+	Config.Backoff = 10 * time.Millisecond
+	Config.Timeout = time.Second
+
+	t.Run("ok", func(t *testing.T) {
+		run, s := runJob(t, `echo "$GOSUKI_RUN_ID $GOSUKI_URL $GOSUKI_ATTEMPT"; echo oops >&2`)
+		assert.Equal(t, RunOK, run.Status)
+		assert.Equal(t, 1, run.Attempts)
+		assert.Equal(t, run.ID+" https://example.com 1\n", run.Stdout)
+		assert.Equal(t, "oops\n", run.Stderr)
+		assert.Equal(t, []string{RunQueued, RunRunning, RunOK}, s.statuses())
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		run, s := runJob(t, `echo "attempt $GOSUKI_ATTEMPT"; exit 3`)
+		assert.Equal(t, RunFailed, run.Status)
+		assert.Equal(t, 3, run.ExitCode)
+		assert.Equal(t, 3, run.Attempts)
+		assert.Equal(t, "attempt 3\n", run.Stdout)
+
+		// queued again after each failed attempt
+		assert.Equal(t, []string{
+			RunQueued,
+			RunRunning, RunQueued,
+			RunRunning, RunQueued,
+			RunRunning, RunFailed,
+		}, s.statuses())
+	})
+
+	t.Run("succeeds after retry", func(t *testing.T) {
+		run, _ := runJob(t, `test "$GOSUKI_ATTEMPT" -ge 2`)
+		assert.Equal(t, RunOK, run.Status)
+		assert.Equal(t, 2, run.Attempts)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		Config.Retries = 0
+		Config.RuleTimeouts = map[string]time.Duration{"test": 100 * time.Millisecond}
+		run, _ := runJob(t, "sleep 5")
+		assert.Equal(t, RunTimeout, run.Status)
+		assert.Less(t, run.Duration, int64(5000))
+	})
+
+	t.Run("output tail", func(t *testing.T) {
+		run, _ := runJob(t, "head -c 10000 /dev/zero | tr '\\0' a; echo end")
+		require.Len(t, run.Stdout, OutputTail)
+		assert.True(t, strings.HasSuffix(run.Stdout, "aend\n"))
+	})
+}
+
+func TestRetryReleasesWorker(t *testing.T) {
+	prev := *Config
+	t.Cleanup(func() { *Config = prev })
+	Config.Retries = 1
+	Config.Backoff = time.Hour
+	Config.Timeout = time.Second
+
+	s := &memStore{}
+	SetRunStore(s)
+	t.Cleanup(func() { SetRunStore(nil) })
+
+	// more failing runs than workers, each waits an hour before its retry
+	bk := &gosuki.Bookmark{URL: "https://example.com"}
+	for range DefaultConcurrency + 1 {
+		Enqueue(Rule{Trigger: "test", Pattern: ".*", Command: "exit 1"}, bk)
+	}
+
+	id := Enqueue(Rule{Trigger: "test", Pattern: ".*", Command: "true"}, bk)
+	require.Eventually(t, func() bool {
+		s.Lock()
+		defer s.Unlock()
+		for _, run := range s.saved {
+			if run.ID == id && run.Status == RunOK {
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second, 5*time.Millisecond)
+}
@@ -58,6 +58,13 @@ type Browser interface {
 	Config() *BrowserConfig
 }
 
+// ProfileConfigurer is implemented by browsers using a copy of their config
+// for each profile instance when watching all the profiles.
+type ProfileConfigurer interface {
+	// Returns the config a profile instance would use
+	ProfileConfig() *BrowserConfig
+}
+
 // Browsers must offer a way to detect if they are installed on the system and
 // display path to their base directory. Note that if the browser module already
 // implements profiles.ProfileManager, implementing this interface is redundant.
@@ -118,6 +125,16 @@ type BrowserConfig struct {
 	hooks []hooks.NamedHook
 }
 
+// SetHooks checks the hooks configured for the browser and uses them on its
+// nodes or bookmarks, depending on T.
+func SetHooks[T hooks.Hookable](b *BrowserConfig, names []string) error {
+	if err := hooks.Check[T](names, hooks.BrowserHook); err != nil {
+		return fmt.Errorf("[%s] hooks: %w", b.Name, err)
+	}
+	b.UseHooks = names
+	return nil
+}
+
 func (b *BrowserConfig) GetWatcher() *watch.WatchDescriptor {
 	return b.watcher
 }
@@ -197,8 +214,9 @@ func (b BrowserConfig) BookmarkPath() (string, error) {
 }
 
 // Rebuilds the memory url index after parsing all bookmarks.
-// Keeps the memory url index in sync with last known state of browser bookmarks
-func (b BrowserConfig) RebuildIndex() {
+// Keeps the memory url index in sync with last known state of browser bookmarks.
+// The index of the config is replaced, urls removed from the tree are dropped.
+func (b *BrowserConfig) RebuildIndex() {
 	start := time.Now()
 	log.Debugf("<%s> rebuilding index based on current nodeTree", b.Name)
 	b.URLIndex = index.NewIndex()
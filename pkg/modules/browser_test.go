@@ -0,0 +1,31 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blob42/gosuki/internal/index"
+	"github.com/blob42/gosuki/pkg/tree"
+)
+
+func TestRebuildIndex(t *testing.T) {
+	root := &tree.Node{Title: "root", Type: tree.RootNode}
+	tree.AddChild(root, &tree.Node{Title: "new", Type: tree.URLNode, URL: "https://new.example.com/"})
+
+	previous := index.NewIndex()
+	previous.Insert("https://removed.example.com/", &tree.Node{Type: tree.URLNode})
+
+	b := &BrowserConfig{
+		Name:     "test",
+		URLIndex: previous,
+		NodeTree: root,
+	}
+	b.RebuildIndex()
+
+	_, ok := b.URLIndex.Get("https://new.example.com/")
+	assert.True(t, ok, "nodes of the current tree are indexed")
+
+	_, ok = b.URLIndex.Get("https://removed.example.com/")
+	assert.False(t, ok, "nodes of the previous tree are dropped")
+}
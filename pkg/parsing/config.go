@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package parsing
+
+import (
+	"github.com/blob42/gosuki/pkg/config"
+)
+
+// Tag syntaxes that can be enabled in the [tags] config section
+const (
+	// #tag
+	SyntaxHashtag = "hashtag"
+
+	// #{multi word tag}
+	SyntaxBraced = "braced"
+
+	// [tag] or [multi word tag]
+	SyntaxBracket = "bracket"
+)
+
+var TagsCfg *TagsConfig
+
+// TagsConfig controls how tags are extracted from bookmark titles and
+// descriptions by the `node_tags_from_name` and `bk_tags_from_name` hooks.
+type TagsConfig struct {
+	// Extract tags from the title
+	ParseTitle bool `toml:"parse-title" mapstructure:"parse-title"`
+
+	// Extract tags from the description
+	ParseDesc bool `toml:"parse-description" mapstructure:"parse-description"`
+
+	// Enabled syntaxes: hashtag, braced, bracket
+	Syntaxes []string `toml:"syntaxes" mapstructure:"syntaxes"`
+
+	// Custom regular expressions, the tag is the `tag` named group or the
+	// first group of the match
+	Patterns []string `toml:"patterns" mapstructure:"patterns"`
+
+	// Allow unicode letters in #hashtags and @actions
+	Unicode bool `toml:"unicode" mapstructure:"unicode"`
+
+	// Remove the parsed tags from the title
+	StripTitle bool `toml:"strip-title" mapstructure:"strip-title"`
+}
+
+func DefaultTagsConfig() *TagsConfig {
+	return &TagsConfig{
+		ParseTitle: true,
+		Syntaxes:   []string{SyntaxHashtag},
+		Patterns:   []string{},
+		StripTitle: true,
+	}
+}
+
+func init() {
+	TagsCfg = DefaultTagsConfig()
+	config.RegisterConfigurator("tags", config.AsConfigurator(TagsCfg))
+}
@@ -158,3 +158,115 @@ func TestParseTags(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestTagParserConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    func(*TagsConfig)
+		title     string
+		desc      string
+		wantTags  []string
+		wantTitle string
+	}{
+		{
+			name:      "unicode hashtags",
+			config:    func(c *TagsConfig) { c.Unicode = true },
+			title:     "Recette #café #日本",
+			wantTags:  []string{"café", "日本"},
+			wantTitle: "Recette  ",
+		},
+		{
+			name:      "ascii hashtags stop at unicode letters",
+			title:     "Recette #café",
+			wantTags:  []string{"caf"},
+			wantTitle: "Recette é",
+		},
+		{
+			name:      "braced multi word tags",
+			config:    func(c *TagsConfig) { c.Syntaxes = []string{SyntaxHashtag, SyntaxBraced} },
+			title:     "Talk #{machine learning} #video",
+			wantTags:  []string{"video", "machine learning"},
+			wantTitle: "Talk  ",
+		},
+		{
+			name:      "bracket tags",
+			config:    func(c *TagsConfig) { c.Syntaxes = []string{SyntaxBracket} },
+			title:     "[pdf] [type theory] Paper #notatag",
+			wantTags:  []string{"pdf", "type theory"},
+			wantTitle: "  Paper #notatag",
+		},
+		{
+			name:      "custom pattern",
+			config:    func(c *TagsConfig) { c.Syntaxes = nil; c.Patterns = []string{`\+(\w+)`} },
+			title:     "Article +golang",
+			wantTags:  []string{"golang"},
+			wantTitle: "Article ",
+		},
+		{
+			name:      "keep tags in title",
+			config:    func(c *TagsConfig) { c.StripTitle = false },
+			title:     "Article #golang @read",
+			wantTags:  []string{"golang", "@read"},
+			wantTitle: "Article #golang @read",
+		},
+		{
+			name:      "description tags",
+			config:    func(c *TagsConfig) { c.ParseDesc = true },
+			title:     "Article #golang",
+			desc:      "about #golang and #generics",
+			wantTags:  []string{"golang", "generics"},
+			wantTitle: "Article ",
+		},
+		{
+			name:      "title parsing disabled",
+			config:    func(c *TagsConfig) { c.ParseTitle = false; c.ParseDesc = true },
+			title:     "Article #golang",
+			desc:      "#generics",
+			wantTags:  []string{"generics"},
+			wantTitle: "Article #golang",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := DefaultTagsConfig()
+			if tt.config != nil {
+				tt.config(c)
+			}
+			parser, err := NewTagParser(c)
+			assert.NoError(t, err)
+
+			title, desc, tags := tt.title, tt.desc, []string{}
+			parser.Parse(&title, &desc, &tags)
+			assert.Equal(t, tt.wantTags, tags)
+			assert.Equal(t, tt.wantTitle, title)
+			assert.Equal(t, tt.desc, desc, "description is never modified")
+		})
+	}
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewTagParser(&TagsConfig{Syntaxes: []string{"unknown"}})
+		assert.Error(t, err)
+
+		_, err = NewTagParser(&TagsConfig{Patterns: []string{`(`}})
+		assert.Error(t, err)
+	})
+
+	t.Run("hooks honor the config", func(t *testing.T) {
+		defer func(c TagsConfig) {
+			*TagsCfg = c
+			ResetTagParser()
+		}(*TagsCfg)
+
+		TagsCfg.Syntaxes = []string{SyntaxBracket}
+		ResetTagParser()
+
+		bk := &gosuki.Bookmark{Title: "[news] #ignored"}
+		assert.NoError(t, ParseBkTags(bk))
+		assert.Equal(t, []string{"news"}, bk.Tags)
+
+		node := &tree.Node{Title: "[news] #ignored", Type: tree.URLNode}
+		assert.NoError(t, ParseNodeTags(node))
+		assert.Equal(t, []string{"news"}, node.Tags)
+	})
+}
@@ -25,8 +25,11 @@ package parsing
 import (
 	"fmt"
 	"regexp"
+	"strings"
+	"sync"
 
 	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
 	"github.com/blob42/gosuki/pkg/logging"
 	"github.com/blob42/gosuki/pkg/tree"
 )
@@ -52,43 +55,153 @@ const (
 	ReActionTag = `@(?P<tag>[a-zA-Z0-9_.-]+)`
 )
 
-var log = logging.GetLogger("parse")
+const (
+	asciiTagChars   = `[a-zA-Z0-9_.-]`
+	unicodeTagChars = `[\p{L}\p{M}\p{N}_.-]`
+)
+
+var (
+	log = logging.GetLogger("parse")
+
+	tagParser   *TagParser
+	tagParserMu sync.Mutex
+)
+
+// TagParser extracts tags and @actions from bookmark titles and descriptions
+// following a [TagsConfig].
+type TagParser struct {
+	tagRes     []*regexp.Regexp
+	actionRe   *regexp.Regexp
+	parseTitle bool
+	parseDesc  bool
+	strip      bool
+}
+
+func syntaxPattern(syntax string, unicode bool) (string, error) {
+	chars := asciiTagChars
+	if unicode {
+		chars = unicodeTagChars
+	}
+
+	switch syntax {
+	case SyntaxHashtag:
+		return `#(?P<tag>` + chars + `+)`, nil
+	case SyntaxBraced:
+		return `#\{(?P<tag>[^{}]+)\}`, nil
+	case SyntaxBracket:
+		return `\[(?P<tag>[^\[\]]+)\]`, nil
+	default:
+		return "", fmt.Errorf("unknown tag syntax %q", syntax)
+	}
+}
+
+func NewTagParser(c *TagsConfig) (*TagParser, error) {
+	var patterns []string
+	for _, syntax := range c.Syntaxes {
+		pattern, err := syntaxPattern(syntax, c.Unicode)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	patterns = append(patterns, c.Patterns...)
+
+	p := &TagParser{
+		parseTitle: c.ParseTitle,
+		parseDesc:  c.ParseDesc,
+		strip:      c.StripTitle,
+	}
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("tag pattern %q: %w", pattern, err)
+		}
+		p.tagRes = append(p.tagRes, re)
+	}
 
-func stripHashTag(s string) string {
-	return regexp.MustCompile(ReTags).ReplaceAllString(s, "")
+	actionPattern := ReActionTag
+	if c.Unicode {
+		actionPattern = `@(?P<tag>` + unicodeTagChars + `+)`
+	}
+	p.actionRe = regexp.MustCompile(actionPattern)
+
+	return p, nil
 }
 
-// compelte this function
-func stripActionTags(s string) string {
-	return regexp.MustCompile(ReActionTag).ReplaceAllString(s, "")
+// DefaultTagParser returns the parser for the [tags] config section. An
+// invalid config is reported and replaced by the default config.
+func DefaultTagParser() *TagParser {
+	tagParserMu.Lock()
+	defer tagParserMu.Unlock()
+
+	if tagParser != nil {
+		return tagParser
+	}
+
+	var err error
+	tagParser, err = NewTagParser(TagsCfg)
+	if err != nil {
+		log.Errorf("[tags] config: %s, using defaults", err)
+		tagParser, _ = NewTagParser(DefaultTagsConfig())
+	}
+
+	return tagParser
+}
+
+// ResetTagParser drops the current parser, the next call to
+// [DefaultTagParser] reads the config again.
+func ResetTagParser() {
+	tagParserMu.Lock()
+	tagParser = nil
+	tagParserMu.Unlock()
+}
+
+// Parse appends the tags found in the title and description to tags.
+// Matched tags and actions are removed from the title if configured.
+func (p *TagParser) Parse(title, desc *string, tags *[]string) {
+	if p.parseTitle {
+		for _, re := range p.tagRes {
+			processTags(re, title, tags, false)
+			if p.strip {
+				*title = re.ReplaceAllString(*title, "")
+			}
+		}
+
+		processTags(p.actionRe, title, tags, true)
+		if p.strip {
+			*title = p.actionRe.ReplaceAllString(*title, "")
+		}
+	}
+
+	if p.parseDesc {
+		var descTags []string
+		for _, re := range p.tagRes {
+			processTags(re, desc, &descTags, false)
+		}
+		*tags = utils.Extends(*tags, descTags...)
+	}
 }
 
 // parseTags is a [gosuki.Hook] that extracts tags like #tag from the title of
 // the bookmark or node.
-// It takes an item of type *tree.Node or *gosuki.Bookmark, extracts all tags
-// matching the regex pattern defined in ReTags, appends them to the item's Tags
-// field, and removes the matched tags from the title. If the item is of an
-// unsupported type, it returns an error.
+// It takes an item of type *tree.Node or *gosuki.Bookmark, extracts the tags
+// with the syntaxes of the [tags] config section, appends them to the item's
+// Tags field, and removes the matched tags from the title. If the item is of
+// an unsupported type, it returns an error.
 func parseTags(item any) error {
-	tagRe := regexp.MustCompile(ReTags)
-	actionTagRe := regexp.MustCompile(ReActionTag)
+	parser := DefaultTagParser()
 	switch v := item.(type) {
 	case *tree.Node:
 		if v.Tags == nil {
 			v.Tags = []string{}
 		}
-		processTags(tagRe, &v.Title, &v.Tags, false)
-		v.Title = stripHashTag(v.Title)
-		processTags(actionTagRe, &v.Title, &v.Tags, true)
-		v.Title = stripActionTags(v.Title)
+		parser.Parse(&v.Title, &v.Desc, &v.Tags)
 	case *gosuki.Bookmark:
 		if v.Tags == nil {
 			v.Tags = []string{}
 		}
-		processTags(tagRe, &v.Title, &v.Tags, false)
-		v.Title = stripHashTag(v.Title)
-		processTags(actionTagRe, &v.Title, &v.Tags, true)
-		v.Title = stripActionTags(v.Title)
+		parser.Parse(&v.Title, &v.Desc, &v.Tags)
 	default:
 		return fmt.Errorf("unsupported type")
 	}
@@ -97,17 +210,27 @@ func parseTags(item any) error {
 
 func processTags(
 	regex *regexp.Regexp,
-	title *string,
+	text *string,
 	tags *[]string,
 	withSymbol bool,
 ) {
 
-	matches := regex.FindAllStringSubmatch(*title, -1)
+	tagGroup := max(regex.SubexpIndex("tag"), 1)
+
+	matches := regex.FindAllStringSubmatch(*text, -1)
 	for _, m := range matches {
-		if withSymbol {
-			*tags = append(*tags, m[0])
-		} else {
-			*tags = append(*tags, m[1])
+		var tag string
+		switch {
+		case withSymbol:
+			tag = m[0]
+		case tagGroup < len(m):
+			tag = strings.TrimSpace(m[tagGroup])
+		default:
+			tag = m[0]
+		}
+
+		if tag != "" {
+			*tags = append(*tags, tag)
 		}
 	}
 	if len(*tags) > 0 {
@@ -23,6 +23,7 @@
 package profiles
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/blob42/gosuki/internal/utils"
@@ -52,6 +53,20 @@ type Profile struct {
 	BaseDir string
 
 	IsCustom bool
+
+	// Profile group the profile belongs to, if any. Firefox lists grouped
+	// (selectable) profiles in a per-group store.
+	Group string `ini:"-"`
+}
+
+// ModuleName returns the name used to attribute data to the profile `p` of
+// the module `mod`: <module>[_<flavour>]_<profile>
+func ModuleName(mod string, flavour string, p *Profile) string {
+	name := mod
+	if flavour != "" && flavour != name {
+		name = fmt.Sprintf("%s_%s", name, flavour)
+	}
+	return fmt.Sprintf("%s_%s", name, p.Name)
 }
 
 // returns shortcut for path
@@ -85,6 +100,15 @@ type ProfileManager interface {
 	GetCurFlavour() *BrowserDef
 }
 
+// ProfileIndexer is implemented by profile managers that list their profiles
+// in index files (ex. profiles.ini, Local State). The files are watched to
+// detect profiles that are added or removed while gosuki is running. An index
+// directory is watched for any file created or written in it, it does not
+// need to exist yet.
+type ProfileIndexer interface {
+	ProfileIndexFiles(flavour string) ([]string, error)
+}
+
 func FromCustom(list []CustomProfile, flavour string) []*Profile {
 	var result []*Profile
 
@@ -0,0 +1,14 @@
+package profiles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModuleName(t *testing.T) {
+	p := &Profile{Name: "work"}
+	assert.Equal(t, "firefox_work", ModuleName("firefox", "firefox", p))
+	assert.Equal(t, "firefox_librewolf_work", ModuleName("firefox", "librewolf", p))
+	assert.Equal(t, "chrome_work", ModuleName("chrome", "", p))
+}
@@ -0,0 +1,280 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package readability extracts the readable text of HTML pages. It follows the
+// scoring of the Arc90 readability algorithm: paragraphs give points to their
+// parent blocks, class and id names push blocks up or down, links lower the
+// score and the best block is kept with its related siblings.
+package readability
+
+import (
+	"errors"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var (
+	unlikely = regexp.MustCompile(`(?i)banner|breadcrumbs?|combx|comment|community|cookie|disqus|extra|` +
+		`footer|gdpr|header|legends|menu|modal|related|remark|replies|rss|share|shoutbox|sidebar|` +
+		`skyscraper|social|sponsor|subscribe|ad-break|agegate|pagination|pager|popup|newsletter`)
+	maybe    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positive = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negative = regexp.MustCompile(`(?i)hidden|banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|` +
+		`masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|` +
+		`shopping|tags|tool|widget`)
+	spaces = regexp.MustCompile(`[ \t\r\f\v\x{00a0}]+`)
+	lines  = regexp.MustCompile(`\s*\n\s*`)
+)
+
+// Elements removed before scoring
+const removed = "script, style, noscript, template, iframe, svg, canvas, form, button, " +
+	"input, select, textarea, nav, aside, footer, header, object, embed, dialog"
+
+// Blocks whose text is scored
+const scored = "p, pre, td, blockquote, li, h2, h3, section"
+
+// Block elements separated by blank lines in the text
+var blocks = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "main": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"li": true, "pre": true, "blockquote": true, "tr": true, "table": true,
+	"ul": true, "ol": true, "dl": true, "dt": true, "dd": true, "figure": true,
+	"figcaption": true, "br": true, "hr": true,
+}
+
+// minimum length of a paragraph to be scored
+const minParagraph = 25
+
+var ErrNoContent = errors.New("no readable content")
+
+// Article is the readable part of a page
+type Article struct {
+	Title string
+	Text  string
+}
+
+// Extract returns the title and readable text of the HTML document
+func Extract(r io.Reader) (*Article, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return FromDocument(doc)
+}
+
+// FromDocument returns the title and readable text of the parsed document.
+// The document is modified.
+func FromDocument(doc *goquery.Document) (*Article, error) {
+	article := &Article{Title: title(doc)}
+
+	doc.Find(removed).Remove()
+	doc.Find("[hidden], [aria-hidden=true]").Remove()
+
+	// unlikely candidates
+	doc.Find("body *").Each(func(_ int, s *goquery.Selection) {
+		switch goquery.NodeName(s) {
+		case "body", "article", "main", "a", "table", "tbody", "tr", "td":
+			return
+		}
+		names := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if unlikely.MatchString(names) && !maybe.MatchString(names) {
+			s.Remove()
+		}
+	})
+
+	top := bestCandidate(doc)
+	if top == nil {
+		top = doc.Find("body")
+	}
+
+	article.Text = normalize(text(top))
+	if article.Text == "" {
+		return article, ErrNoContent
+	}
+	return article, nil
+}
+
+func title(doc *goquery.Document) string {
+	if t, ok := doc.Find(`meta[property="og:title"]`).Attr("content"); ok && strings.TrimSpace(t) != "" {
+		return strings.TrimSpace(t)
+	}
+	t := strings.TrimSpace(doc.Find("title").First().Text())
+	if t == "" {
+		t = strings.TrimSpace(doc.Find("h1").First().Text())
+	}
+	return spaces.ReplaceAllString(t, " ")
+}
+
+type candidate struct {
+	sel   *goquery.Selection
+	score float64
+}
+
+// bestCandidate returns the best scored block with its related siblings
+func bestCandidate(doc *goquery.Document) *goquery.Selection {
+	scores := map[*html.Node]*candidate{}
+	var order []*html.Node
+
+	addScore := func(s *goquery.Selection, score float64) {
+		if s.Length() == 0 || goquery.NodeName(s) == "html" {
+			return
+		}
+		node := s.Get(0)
+		c, ok := scores[node]
+		if !ok {
+			c = &candidate{sel: s, score: classWeight(s)}
+			switch goquery.NodeName(s) {
+			case "article", "main":
+				c.score += 10
+			case "div":
+				c.score += 5
+			case "pre", "td", "blockquote":
+				c.score += 3
+			case "form", "ol", "ul", "dl", "dd", "dt", "li":
+				c.score -= 3
+			case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+				c.score -= 5
+			}
+			scores[node] = c
+			order = append(order, node)
+		}
+		c.score += score
+	}
+
+	doc.Find(scored).Each(func(_ int, s *goquery.Selection) {
+		t := strings.TrimSpace(s.Text())
+		length := utf8.RuneCountInString(t)
+		if length < minParagraph {
+			return
+		}
+
+		score := 1 + float64(strings.Count(t, ",")+strings.Count(t, "，"))
+		score += math.Min(float64(length/100), 3)
+
+		parent := s.Parent()
+		addScore(parent, score)
+		addScore(parent.Parent(), score/2)
+	})
+
+	var best *candidate
+	for _, node := range order {
+		c := scores[node]
+		c.score *= 1 - linkDensity(c.sel)
+		if best == nil || c.score > best.score {
+			best = c
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	// siblings sharing the content, ex. consecutive blocks of an article
+	threshold := math.Max(10, best.score*0.2)
+	result := best.sel
+	best.sel.Siblings().Each(func(_ int, s *goquery.Selection) {
+		if c, ok := scores[s.Get(0)]; ok && c.score >= threshold {
+			result = result.AddSelection(s)
+			return
+		}
+		if goquery.NodeName(s) == "p" {
+			t := strings.TrimSpace(s.Text())
+			if utf8.RuneCountInString(t) > 80 && linkDensity(s) < 0.25 {
+				result = result.AddSelection(s)
+			}
+		}
+	})
+	return result
+}
+
+func classWeight(s *goquery.Selection) float64 {
+	var weight float64
+	for _, name := range []string{s.AttrOr("class", ""), s.AttrOr("id", "")} {
+		if name == "" {
+			continue
+		}
+		if negative.MatchString(name) {
+			weight -= 25
+		}
+		if positive.MatchString(name) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity is the ratio of the text in links
+func linkDensity(s *goquery.Selection) float64 {
+	length := utf8.RuneCountInString(strings.TrimSpace(s.Text()))
+	if length == 0 {
+		return 0
+	}
+	var links int
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		links += utf8.RuneCountInString(strings.TrimSpace(a.Text()))
+	})
+	return math.Min(float64(links)/float64(length), 1)
+}
+
+// text returns the text of the selection with blocks on their own lines
+func text(s *goquery.Selection) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			if blocks[n.Data] {
+				b.WriteString("\n")
+				defer b.WriteString("\n")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range s.Nodes {
+		walk(n)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// normalize collapses the spaces and keeps a blank line between blocks
+func normalize(t string) string {
+	t = spaces.ReplaceAllString(t, " ")
+	t = lines.ReplaceAllString(t, "\n")
+
+	var paragraphs []string
+	for _, line := range strings.Split(t, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paragraphs = append(paragraphs, line)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
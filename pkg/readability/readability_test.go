@@ -0,0 +1,62 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html><head><title>Fallback title</title>
+<meta property="og:title" content="How gardens grow">
+<style>body { color: red }</style>
+<script>var tracking = "do not index";</script>
+</head>
+<body>
+<header class="site-header"><a href="/">Home</a> <a href="/blog">Blog</a></header>
+<nav><ul><li><a href="/a">Navigation link one</a></li></ul></nav>
+<div class="sidebar">Subscribe to the newsletter for weekly offers and more promotions</div>
+<div id="main-content" class="post">
+  <h1>How gardens grow</h1>
+  <p>Gardens need sunlight, water, and patience. A well planned garden, with good soil, grows for years.</p>
+  <p>Composting kitchen scraps returns nutrients to the soil, feeds the worms, and reduces waste.</p>
+  <p>Mulch keeps the moisture in the ground during the hot summer months, and limits weeds.</p>
+</div>
+<div class="comments"><p>First comment, great article, thanks for sharing it with us all here.</p></div>
+<footer>Copyright notice and legal links of the site footer</footer>
+</body></html>`
+
+func TestExtract(t *testing.T) {
+	article, err := Extract(strings.NewReader(articlePage))
+	require.NoError(t, err)
+
+	assert.Equal(t, "How gardens grow", article.Title)
+	assert.Contains(t, article.Text, "Gardens need sunlight, water, and patience.")
+	assert.Contains(t, article.Text, "Composting kitchen scraps")
+	assert.Contains(t, article.Text, "Mulch keeps the moisture")
+
+	// paragraphs are separated by blank lines
+	assert.Contains(t, article.Text, "for years.\n\nComposting")
+
+	for _, noise := range []string{"tracking", "color: red", "Navigation link", "newsletter",
+		"First comment", "Copyright", "Home"} {
+		assert.NotContains(t, article.Text, noise)
+	}
+}
+
+func TestExtractFallback(t *testing.T) {
+	t.Run("short page", func(t *testing.T) {
+		article, err := Extract(strings.NewReader(`<html><head><title> A  title </title></head>
+			<body><div>Short text</div></body></html>`))
+		require.NoError(t, err)
+		assert.Equal(t, "A title", article.Title)
+		assert.Equal(t, "Short text", article.Text)
+	})
+
+	t.Run("empty page", func(t *testing.T) {
+		_, err := Extract(strings.NewReader(`<html><body><script>x()</script></body></html>`))
+		assert.ErrorIs(t, err, ErrNoContent)
+	})
+}
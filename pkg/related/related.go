@@ -0,0 +1,241 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package related finds bookmarks similar to a given bookmark.
+//
+// The index keeps the terms of every bookmark (see [suggest.Terms]) and their
+// tags in an inverted index. Similarity is the cosine of the TF-IDF vectors of
+// two bookmarks, the IDF being computed at query time so the index can be
+// updated one bookmark at a time.
+package related
+
+import (
+	"encoding/gob"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/suggest"
+)
+
+const (
+	// Terms present in a larger share of the bookmarks are not used to find
+	// candidates, unless they are in less than minPostings bookmarks
+	maxTermShare = 0.3
+	minPostings  = 100
+
+	// Matches with a lower similarity are dropped
+	DefaultMinScore = 0.05
+)
+
+type Match struct {
+	URL   string  `json:"url"`
+	Score float64 `json:"score"`
+}
+
+// Index is a similarity index of bookmarks keyed by URL. It is safe for
+// concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]map[string]int
+	postings map[string]map[string]struct{}
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:     map[string]map[string]int{},
+		postings: map[string]map[string]struct{}{},
+	}
+}
+
+func terms(bk *gosuki.Bookmark) map[string]int {
+	tf := suggest.Terms(bk)
+	for _, tag := range bk.Tags {
+		tf["tag:"+strings.ToLower(tag)] += 2
+	}
+	return tf
+}
+
+// Add indexes the bookmark, replacing the previous terms of its URL
+func (idx *Index) Add(bookmarks ...*gosuki.Bookmark) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, bk := range bookmarks {
+		idx.remove(bk.URL)
+
+		tf := terms(bk)
+		idx.docs[bk.URL] = tf
+		for t := range tf {
+			if idx.postings[t] == nil {
+				idx.postings[t] = map[string]struct{}{}
+			}
+			idx.postings[t][bk.URL] = struct{}{}
+		}
+	}
+}
+
+// Remove drops the URL from the index
+func (idx *Index) Remove(url string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(url)
+}
+
+func (idx *Index) remove(url string) {
+	for t := range idx.docs[url] {
+		delete(idx.postings[t], url)
+		if len(idx.postings[t]) == 0 {
+			delete(idx.postings, t)
+		}
+	}
+	delete(idx.docs, url)
+}
+
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+func (idx *Index) idf(t string) float64 {
+	return math.Log(float64(len(idx.docs)+1)/float64(len(idx.postings[t])+1)) + 1
+}
+
+func (idx *Index) vector(tf map[string]int) map[string]float64 {
+	var norm float64
+	vec := make(map[string]float64, len(tf))
+	for t, n := range tf {
+		w := float64(n) * idx.idf(t)
+		vec[t] = w
+		norm += w * w
+	}
+
+	norm = math.Sqrt(norm)
+	for t := range vec {
+		vec[t] /= norm
+	}
+	return vec
+}
+
+// Related returns at most `limit` bookmarks similar to the indexed URL, most
+// similar first
+func (idx *Index) Related(url string, limit int) []Match {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	tf, ok := idx.docs[url]
+	if !ok || len(tf) == 0 {
+		return nil
+	}
+	vec := idx.vector(tf)
+
+	maxPostings := max(int(maxTermShare*float64(len(idx.docs))), minPostings)
+	candidates := map[string]struct{}{}
+	for t := range tf {
+		if len(idx.postings[t]) > maxPostings {
+			continue
+		}
+		for u := range idx.postings[t] {
+			candidates[u] = struct{}{}
+		}
+	}
+	delete(candidates, url)
+
+	var res []Match
+	for u := range candidates {
+		var score float64
+		for t, w := range idx.vector(idx.docs[u]) {
+			score += w * vec[t]
+		}
+		if score >= DefaultMinScore {
+			res = append(res, Match{u, score})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b Match) int {
+		if a.Score > b.Score {
+			return -1
+		} else if a.Score < b.Score {
+			return 1
+		}
+		return strings.Compare(a.URL, b.URL)
+	})
+
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
+
+// Save writes the index to path. The file is replaced atomically.
+func (idx *Index) Save(path string) error {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".related-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = gob.NewEncoder(tmp).Encode(idx.docs); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads an index saved with [Index.Save]
+func Load(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	docs := map[string]map[string]int{}
+	if err = gob.NewDecoder(f).Decode(&docs); err != nil {
+		return nil, fmt.Errorf("decoding related index: %w", err)
+	}
+
+	idx := NewIndex()
+	idx.docs = docs
+	for url, tf := range docs {
+		for t := range tf {
+			if idx.postings[t] == nil {
+				idx.postings[t] = map[string]struct{}{}
+			}
+			idx.postings[t][url] = struct{}{}
+		}
+	}
+
+	return idx, nil
+}
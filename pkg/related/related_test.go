@@ -0,0 +1,73 @@
+package related
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+var bookmarks = []*gosuki.Bookmark{
+	{URL: "https://go.dev/blog/generics", Title: "An introduction to generics", Tags: []string{"golang"}},
+	{URL: "https://go.dev/doc/effective_go", Title: "Effective Go", Tags: []string{"golang"}},
+	{URL: "https://github.com/golang/go/wiki/generics", Title: "Generics proposal"},
+	{URL: "https://www.allrecipes.com/recipe/pasta", Title: "Quick pasta recipe", Tags: []string{"cooking"}},
+	{URL: "https://www.bbcgoodfood.com/recipes/pasta-bake", Title: "Pasta bake", Tags: []string{"cooking"}},
+	{URL: "https://news.ycombinator.com/", Title: "Hacker News"},
+}
+
+func urls(matches []Match) []string {
+	var res []string
+	for _, m := range matches {
+		res = append(res, m.URL)
+	}
+	return res
+}
+
+func TestRelated(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(bookmarks...)
+	require.Equal(t, len(bookmarks), idx.Len())
+
+	res := idx.Related("https://go.dev/blog/generics", 0)
+	require.NotEmpty(t, res)
+	assert.ElementsMatch(t,
+		[]string{"https://go.dev/doc/effective_go", "https://github.com/golang/go/wiki/generics"},
+		urls(res),
+	)
+	assert.GreaterOrEqual(t, res[0].Score, res[len(res)-1].Score)
+
+	res = idx.Related("https://www.allrecipes.com/recipe/pasta", 1)
+	assert.Equal(t, []string{"https://www.bbcgoodfood.com/recipes/pasta-bake"}, urls(res))
+
+	assert.Empty(t, idx.Related("https://news.ycombinator.com/", 0))
+	assert.Empty(t, idx.Related("https://unknown.org", 0))
+
+	t.Run("update", func(t *testing.T) {
+		idx.Add(&gosuki.Bookmark{URL: "https://news.ycombinator.com/", Title: "Hacker News", Tags: []string{"golang"}})
+		assert.Contains(t, urls(idx.Related("https://news.ycombinator.com/", 0)), "https://go.dev/blog/generics")
+
+		idx.Remove("https://news.ycombinator.com/")
+		assert.NotContains(t, urls(idx.Related("https://go.dev/blog/generics", 0)), "https://news.ycombinator.com/")
+		assert.Equal(t, len(bookmarks)-1, idx.Len())
+	})
+}
+
+func TestSaveLoad(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(bookmarks...)
+
+	path := filepath.Join(t.TempDir(), "related.idx")
+	require.NoError(t, idx.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, idx.Len(), loaded.Len())
+	assert.Equal(t,
+		idx.Related("https://go.dev/blog/generics", 0),
+		loaded.Related("https://go.dev/blog/generics", 0),
+	)
+}
@@ -0,0 +1,269 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package suggest suggests tags for bookmarks with a local model trained on
+// the bookmarks that are already tagged.
+//
+// Bookmarks are turned into TF-IDF vectors of the words of their title,
+// description and URL and of their domain. Each tag is represented by the
+// normalized sum of the vectors of the bookmarks carrying it, a bookmark is
+// suggested the tags whose vectors are the most similar to its own.
+package suggest
+
+import (
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/blob42/gosuki"
+)
+
+const (
+	// Tags used by fewer bookmarks are not suggested
+	DefaultMinExamples = 2
+
+	// Suggestions with a lower cosine similarity are dropped
+	DefaultMinScore = 0.1
+)
+
+// Words that carry no meaning on their own
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true,
+	"this": true, "that": true, "are": true, "was": true, "you": true,
+	"your": true, "how": true, "what": true, "why": true, "not": true,
+	"but": true, "all": true, "can": true, "www": true, "http": true,
+	"https": true, "html": true, "htm": true, "php": true, "index": true,
+	"com": true, "org": true, "net": true,
+}
+
+type vector map[string]float64
+
+func (v vector) normalize() vector {
+	var norm float64
+	for _, w := range v {
+		norm += w * w
+	}
+	if norm == 0 {
+		return v
+	}
+
+	norm = math.Sqrt(norm)
+	for t, w := range v {
+		v[t] = w / norm
+	}
+	return v
+}
+
+func (v vector) dot(o vector) float64 {
+	if len(o) < len(v) {
+		v, o = o, v
+	}
+
+	var res float64
+	for t, w := range v {
+		res += w * o[t]
+	}
+	return res
+}
+
+type Suggestion struct {
+	Tag   string  `json:"tag"`
+	Score float64 `json:"score"`
+}
+
+// Model holds the tag vectors learned from a set of bookmarks
+type Model struct {
+	MinScore float64
+
+	docs int
+	df   map[string]int
+	tags map[string]vector
+
+	// tags rejected by bookmark URL
+	mu       sync.RWMutex
+	rejected map[string]map[string]bool
+}
+
+// Train builds a model from the given bookmarks. Untagged bookmarks only
+// contribute to the document frequency of terms.
+func Train(bookmarks []*gosuki.Bookmark) *Model {
+	m := &Model{
+		MinScore: DefaultMinScore,
+		df:       map[string]int{},
+		tags:     map[string]vector{},
+		rejected: map[string]map[string]bool{},
+	}
+
+	docs := make([]map[string]int, len(bookmarks))
+	for i, bk := range bookmarks {
+		docs[i] = Terms(bk)
+		for t := range docs[i] {
+			m.df[t]++
+		}
+	}
+	m.docs = len(bookmarks)
+
+	examples := map[string]int{}
+	for i, bk := range bookmarks {
+		if len(bk.Tags) == 0 {
+			continue
+		}
+
+		vec := m.vectorize(docs[i])
+		for _, tag := range bk.Tags {
+			tv, ok := m.tags[tag]
+			if !ok {
+				tv = vector{}
+				m.tags[tag] = tv
+			}
+			for t, w := range vec {
+				tv[t] += w
+			}
+			examples[tag]++
+		}
+	}
+
+	for tag, tv := range m.tags {
+		if examples[tag] < DefaultMinExamples {
+			delete(m.tags, tag)
+			continue
+		}
+		tv.normalize()
+	}
+
+	return m
+}
+
+// Tags returns the number of tags the model can suggest
+func (m *Model) Tags() int {
+	return len(m.tags)
+}
+
+func (m *Model) vectorize(tf map[string]int) vector {
+	vec := vector{}
+	for t, n := range tf {
+		idf := math.Log(float64(m.docs+1)/float64(m.df[t]+1)) + 1
+		vec[t] = float64(n) * idf
+	}
+	return vec.normalize()
+}
+
+// Reject stops suggesting the tags for the bookmark at url
+func (m *Model) Reject(url string, tags ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.rejected[url] == nil {
+		m.rejected[url] = map[string]bool{}
+	}
+	for _, tag := range tags {
+		m.rejected[url][tag] = true
+	}
+}
+
+// Suggest returns at most `limit` tags for the bookmark, best first. Tags the
+// bookmark already has and rejected tags are not suggested.
+func (m *Model) Suggest(bk *gosuki.Bookmark, limit int) []Suggestion {
+	vec := m.vectorize(Terms(bk))
+	if len(vec) == 0 {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rejected := m.rejected[bk.URL]
+
+	var res []Suggestion
+	for tag, tv := range m.tags {
+		if slices.Contains(bk.Tags, tag) || rejected[tag] {
+			continue
+		}
+		if score := vec.dot(tv); score >= m.MinScore {
+			res = append(res, Suggestion{tag, score})
+		}
+	}
+
+	slices.SortFunc(res, func(a, b Suggestion) int {
+		if a.Score > b.Score {
+			return -1
+		} else if a.Score < b.Score {
+			return 1
+		}
+		return strings.Compare(a.Tag, b.Tag)
+	})
+
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
+
+// words splits text into lower case words. Short words, numbers and stop
+// words are skipped.
+func words(text string) []string {
+	var res []string
+	for w := range strings.FieldsFuncSeq(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) < 3 || stopWords[w] {
+			continue
+		}
+		if strings.IndexFunc(w, unicode.IsLetter) < 0 {
+			continue
+		}
+		res = append(res, w)
+	}
+	return res
+}
+
+// Terms counts the terms of the bookmark. The domain is kept as a single
+// `site:` term in addition to the words of the host name.
+func Terms(bk *gosuki.Bookmark) map[string]int {
+	tf := map[string]int{}
+	for _, w := range words(bk.Title) {
+		tf[w]++
+	}
+	for _, w := range words(bk.Desc) {
+		tf[w]++
+	}
+
+	u, err := url.Parse(bk.URL)
+	if err != nil {
+		return tf
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host != "" {
+		tf["site:"+host]++
+		labels := strings.Split(host, ".")
+		for _, w := range words(strings.Join(labels[:len(labels)-1], " ")) {
+			tf[w]++
+		}
+	}
+	for _, w := range words(u.Path) {
+		tf[w]++
+	}
+
+	return tf
+}
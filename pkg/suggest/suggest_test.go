@@ -0,0 +1,90 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+var trainingSet = []*gosuki.Bookmark{
+	{URL: "https://go.dev/blog/generics", Title: "An introduction to generics in golang", Tags: []string{"golang", "dev"}},
+	{URL: "https://go.dev/doc/effective_go", Title: "Effective golang", Tags: []string{"golang"}},
+	{URL: "https://github.com/golang/go", Title: "The golang programming language", Tags: []string{"golang", "dev"}},
+	{URL: "https://www.allrecipes.com/recipe/pasta", Title: "Quick pasta recipe", Tags: []string{"cooking"}},
+	{URL: "https://www.bbcgoodfood.com/recipes/curry", Title: "Easy curry recipe", Tags: []string{"cooking"}},
+	{URL: "https://example.com/rare", Title: "Rare tag", Tags: []string{"once"}},
+	{URL: "https://news.ycombinator.com/item?id=1", Title: "Hacker News"},
+}
+
+func TestSuggest(t *testing.T) {
+	model := Train(trainingSet)
+
+	// tags with a single example are not learned
+	assert.Equal(t, 3, model.Tags())
+
+	t.Run("title words", func(t *testing.T) {
+		res := model.Suggest(&gosuki.Bookmark{
+			URL:   "https://blog.example.org/post",
+			Title: "Error handling in golang",
+		}, 3)
+		var tags []string
+		for _, s := range res {
+			tags = append(tags, s.Tag)
+		}
+		assert.ElementsMatch(t, []string{"golang", "dev"}, tags)
+	})
+
+	t.Run("domain", func(t *testing.T) {
+		res := model.Suggest(&gosuki.Bookmark{URL: "https://www.allrecipes.com/recipe/soup"}, 1)
+		require.Len(t, res, 1)
+		assert.Equal(t, "cooking", res[0].Tag)
+	})
+
+	t.Run("existing tags are not suggested", func(t *testing.T) {
+		res := model.Suggest(&gosuki.Bookmark{
+			URL:   "https://go.dev/blog/errors",
+			Title: "golang errors",
+			Tags:  []string{"golang"},
+		}, 0)
+		for _, s := range res {
+			assert.NotEqual(t, "golang", s.Tag)
+		}
+	})
+
+	t.Run("scores are sorted and limited", func(t *testing.T) {
+		res := model.Suggest(&gosuki.Bookmark{
+			URL:   "https://github.com/golang/tools",
+			Title: "golang tools",
+		}, 2)
+		require.Len(t, res, 2)
+		assert.GreaterOrEqual(t, res[0].Score, res[1].Score)
+	})
+
+	t.Run("rejected tags are not suggested", func(t *testing.T) {
+		bk := &gosuki.Bookmark{URL: "https://www.allrecipes.com/recipe/soup"}
+		model.Reject(bk.URL, "cooking")
+		for _, s := range model.Suggest(bk, 0) {
+			assert.NotEqual(t, "cooking", s.Tag)
+		}
+
+		// other bookmarks still get the tag
+		res := model.Suggest(&gosuki.Bookmark{URL: "https://www.allrecipes.com/recipe/stew"}, 1)
+		require.Len(t, res, 1)
+		assert.Equal(t, "cooking", res[0].Tag)
+	})
+
+	t.Run("unrelated bookmark", func(t *testing.T) {
+		res := model.Suggest(&gosuki.Bookmark{URL: "https://zzz.invalid", Title: "qwerty"}, 3)
+		assert.Empty(t, res)
+	})
+}
+
+func TestWords(t *testing.T) {
+	assert.Equal(t,
+		[]string{"introduction", "générique", "golang"},
+		words("An Introduction to the générique: #golang 2024 v1"),
+	)
+}
@@ -0,0 +1,173 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package tabs reads the open tabs of browser profiles. Open tabs form a
+// separate collection: they are never mixed with bookmarks unless a snapshot
+// is explicitly taken with [AsBookmarks].
+package tabs
+
+import (
+	"net/url"
+	"slices"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/pkg/logging"
+	"github.com/blob42/gosuki/pkg/profiles"
+)
+
+// Module name used for bookmarks created from a tab snapshot
+const SnapshotModule = "tabs"
+
+var (
+	log = logging.GetLogger("tabs")
+
+	// only tabs with these schemes can be turned into bookmarks
+	bookmarkableSchemes = []string{"http", "https", "ftp", "file"}
+
+	registeredSources []source
+)
+
+// Tab is an open tab in a browser profile
+type Tab struct {
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	Window int    `json:"window"`
+	Pinned bool   `json:"pinned"`
+
+	// Browser module and profile the tab is open in, formatted like the
+	// module of bookmarks: <browser>[_<flavour>]_<profile>
+	Module string `json:"module"`
+}
+
+// Reader is implemented by browser modules that can list the open tabs of a
+// profile. Implementations must not change the state of the module.
+type Reader interface {
+	OpenTabs(p *profiles.Profile) ([]*Tab, error)
+}
+
+// ProfileReader is a [Reader] that can also list the profiles it reads tabs
+// from, usually a browser module.
+type ProfileReader interface {
+	Reader
+	profiles.ProfileManager
+}
+
+// SessionTab is a tab read from the session files of a browser
+type SessionTab interface {
+	Tab() *Tab
+}
+
+// ReadProfile returns the open tabs of the profile `p`. `read` reads the
+// session tabs from the profile directory.
+func ReadProfile[T SessionTab](p *profiles.Profile, read func(profileDir string) ([]T, error)) ([]*Tab, error) {
+	profileDir, err := p.AbsolutePath()
+	if err != nil {
+		return nil, err
+	}
+
+	sessionTabs, err := read(profileDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*Tab, 0, len(sessionTabs))
+	for _, t := range sessionTabs {
+		result = append(result, t.Tab())
+	}
+
+	return result, nil
+}
+
+type source struct {
+	id     string
+	reader ProfileReader
+}
+
+// RegisterReader registers the tabs reader of the browser module `id`.
+// Browser modules call it from their init() function.
+func RegisterReader(id string, reader ProfileReader) {
+	registeredSources = append(registeredSources, source{id, reader})
+}
+
+// Bookmarkable returns true if the tab points to a page that can be
+// bookmarked (ex. not about:newtab or chrome://settings)
+func (t *Tab) Bookmarkable() bool {
+	u, err := url.Parse(t.URL)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(bookmarkableSchemes, u.Scheme)
+}
+
+// Collect returns the open tabs of all detected profiles of the registered
+// readers. Profiles that cannot be read are skipped.
+func Collect() []*Tab {
+	var result []*Tab
+
+	for _, src := range registeredSources {
+		for _, flv := range src.reader.ListFlavours() {
+			profs, err := src.reader.GetProfiles(flv.Flavour)
+			if err != nil {
+				log.Debugf("listing <%s> profiles: %s", flv.Flavour, err)
+				continue
+			}
+
+			for _, p := range profs {
+				modName := profiles.ModuleName(src.id, flv.Flavour, p)
+				tabs, err := src.reader.OpenTabs(p)
+				if err != nil {
+					log.Debugf("reading tabs of <%s>: %s", modName, err)
+					continue
+				}
+
+				for _, t := range tabs {
+					t.Module = modName
+				}
+				result = append(result, tabs...)
+			}
+		}
+	}
+
+	return result
+}
+
+// AsBookmarks converts the bookmarkable tabs to bookmarks tagged with `tags`.
+// Duplicate URLs are kept once.
+func AsBookmarks(tabs []*Tab, tags []string) []*gosuki.Bookmark {
+	var result []*gosuki.Bookmark
+	seen := map[string]bool{}
+
+	for _, t := range tabs {
+		if !t.Bookmarkable() || seen[t.URL] {
+			continue
+		}
+		seen[t.URL] = true
+
+		result = append(result, &gosuki.Bookmark{
+			URL:    t.URL,
+			Title:  t.Title,
+			Tags:   slices.Clone(tags),
+			Module: SnapshotModule,
+		})
+	}
+
+	return result
+}
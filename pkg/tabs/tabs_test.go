@@ -0,0 +1,32 @@
+package tabs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsBookmarks(t *testing.T) {
+	tabs := []*Tab{
+		{URL: "https://go.dev/doc/", Title: "Go docs", Module: "firefox_default"},
+		{URL: "about:newtab", Title: "New Tab", Module: "firefox_default"},
+		{URL: "chrome://settings/", Title: "Settings", Module: "chrome_Default"},
+		{URL: "https://go.dev/doc/", Title: "Go docs", Module: "chrome_Default"},
+		{URL: "https://github.com/blob42/gosuki", Title: "gosuki", Module: "chrome_Default"},
+	}
+
+	tags := []string{"snapshot", "research"}
+	bookmarks := AsBookmarks(tabs, tags)
+	require.Len(t, bookmarks, 2)
+
+	assert.Equal(t, "https://go.dev/doc/", bookmarks[0].URL)
+	assert.Equal(t, "Go docs", bookmarks[0].Title)
+	assert.Equal(t, SnapshotModule, bookmarks[0].Module)
+	assert.Equal(t, tags, bookmarks[0].Tags)
+	assert.Equal(t, "https://github.com/blob42/gosuki", bookmarks[1].URL)
+
+	// tags are not shared between bookmarks
+	bookmarks[0].Tags[0] = "changed"
+	assert.Equal(t, "snapshot", bookmarks[1].Tags[0])
+}
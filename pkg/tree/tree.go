@@ -23,6 +23,7 @@ package tree
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/blob42/gosuki"
 	"github.com/blob42/gosuki/internal/index"
@@ -55,6 +56,10 @@ type Node struct {
 	NameHash   uint64 // hash of the metadata
 	Parent     *Node
 	Children   []*Node
+
+	// folder and tag nodes the node was added to with [AddChild], in order.
+	// Tag parents are only listed here, children do not point back to them.
+	containers []*Node
 }
 
 func (node *Node) GetRoot() *Node {
@@ -174,6 +179,7 @@ func AddChild(parent *Node, child *Node) {
 
 	if len(parent.Children) == 0 {
 		parent.Children = []*Node{child}
+		child.addContainer(parent)
 
 		// Do not point back to TAG parent node from child
 		if parent.Type != TagNode {
@@ -194,11 +200,27 @@ func AddChild(parent *Node, child *Node) {
 	}
 
 	parent.Children = append(parent.Children, child)
+	child.addContainer(parent)
 	if parent.Type != TagNode {
 		child.Parent = parent
 	}
 }
 
+func (node *Node) addContainer(parent *Node) {
+	if parent.Type == FolderNode || parent.Type == TagNode {
+		node.containers = append(node.containers, parent)
+	}
+}
+
+// parents returns the nodes containing node: the folder and tag nodes it was
+// added to and its parent.
+func (node *Node) parents() []*Node {
+	if node.Parent == nil || slices.Contains(node.containers, node.Parent) {
+		return node.containers
+	}
+	return append(slices.Clip(node.containers), node.Parent)
+}
+
 func PrintTree(root *Node) {
 	fmt.Println("---")
 	fmt.Println("PrintTree")
@@ -254,19 +276,35 @@ func MapNodeFunc(node *Node, nType NodeType, f func(*Node)) {
 }
 
 // Get all possible tags for this url node The tags make sense only in the
-// context of a URL node This will traverse the three breadth first to find all
-// parent folders and add them as a tag. URL nodes should already be populated
-// with the list of tags that exist under the TAG tree. So we only need to find
-// the parent folders and turn them into tags.
+// context of a URL node. All the folder and tag nodes containing the url node
+// are turned into tags, outer folders first. They are found by following the
+// parents of the node instead of traversing the whole tree.
 func (node *Node) getTags() []string {
 
 	if node.Type != URLNode {
 		return []string{}
 	}
 
-	root := node.GetRoot()
-	parentFolders := FindParents(root, node, FolderNode)
-	parentTags := FindParents(root, node, TagNode)
+	var parentFolders, parentTags, seen []*Node
+
+	var visit func(n *Node)
+	visit = func(n *Node) {
+		for _, p := range n.parents() {
+			if slices.Contains(seen, p) {
+				continue
+			}
+			seen = append(seen, p)
+			visit(p)
+
+			switch p.Type {
+			case FolderNode:
+				parentFolders = append(parentFolders, p)
+			case TagNode:
+				parentTags = append(parentTags, p)
+			}
+		}
+	}
+	visit(node)
 
 	for _, f := range parentFolders {
 		node.Tags = utils.Extends(node.Tags, f.Title)
@@ -1,9 +1,13 @@
 package tree
 
 import (
+	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/blob42/gosuki/internal/utils"
 )
 
 func Test_AddChild(t *testing.T) {
@@ -159,3 +163,120 @@ func Test_GetRoot(t *testing.T) {
 	foundRoot := url.GetRoot()
 	assert.Equal(t, root, foundRoot)
 }
+
+// synthTree builds a tree of nested folders holding nURLs url nodes. A tenth
+// of the urls are also put under one of nTags tag nodes.
+func synthTree(nURLs, nTags int) (*Node, []*Node) {
+	rnd := rand.New(rand.NewSource(42))
+	root := &Node{Type: RootNode, Title: "root"}
+
+	var tags []*Node
+	for i := range nTags {
+		tag := &Node{Type: TagNode, Title: fmt.Sprintf("tag%d", i)}
+		AddChild(root, tag)
+		tags = append(tags, tag)
+	}
+
+	folders := []*Node{root}
+	var urls []*Node
+	for i := range nURLs {
+		parent := folders[rnd.Intn(len(folders))]
+		if rnd.Intn(4) == 0 {
+			folder := &Node{Type: FolderNode, Title: fmt.Sprintf("folder%d", i)}
+			parent.Children = append(parent.Children, folder)
+			folder.Parent = parent
+			folders = append(folders, folder)
+			parent = folder
+		}
+
+		url := &Node{Type: URLNode, Title: fmt.Sprintf("url%d", i), URL: fmt.Sprintf("https://%d.example.com", i)}
+		parent.Children = append(parent.Children, url)
+		url.Parent = parent
+		urls = append(urls, url)
+
+		if nTags > 0 && rnd.Intn(10) == 0 {
+			AddChild(tags[rnd.Intn(nTags)], url)
+		}
+	}
+
+	return root, urls
+}
+
+// tags resolved by walking the whole tree for each node
+func treeWalkTags(node *Node) []string {
+	var tags []string
+	root := node.GetRoot()
+	for _, f := range FindParents(root, node, FolderNode) {
+		tags = utils.Extends(tags, f.Title)
+	}
+	for _, t := range FindParents(root, node, TagNode) {
+		tags = utils.Extends(tags, t.Title)
+	}
+	return tags
+}
+
+func TestGetTagsMembership(t *testing.T) {
+	_, urls := synthTree(2000, 20)
+
+	for _, url := range urls {
+		want := treeWalkTags(url)
+		assert.Equal(t, want, url.GetBookmark().Tags, url.URL)
+	}
+
+	t.Run("updated by AddChild", func(t *testing.T) {
+		url := urls[0]
+		root := url.GetRoot()
+		url.getTags()
+
+		tag := &Node{Type: TagNode, Title: "new tag"}
+		AddChild(root, tag)
+		AddChild(tag, url)
+		assert.Contains(t, url.GetBookmark().Tags, "new tag")
+	})
+
+	t.Run("nested tags and folders", func(t *testing.T) {
+		root := &Node{Type: RootNode, Title: "root"}
+		outer := &Node{Type: FolderNode, Title: "outer"}
+		inner := &Node{Type: FolderNode, Title: "inner"}
+		other := &Node{Type: FolderNode, Title: "other"}
+		tag := &Node{Type: TagNode, Title: "tag"}
+		url := &Node{Type: URLNode, Title: "url", URL: "https://example.com"}
+
+		AddChild(root, outer)
+		AddChild(outer, inner)
+		AddChild(root, other)
+		AddChild(root, tag)
+		AddChild(inner, url)
+		AddChild(tag, url)
+		AddChild(other, url)
+
+		assert.Equal(t, treeWalkTags(url), url.GetBookmark().Tags)
+	})
+}
+
+func BenchmarkGetBookmark(b *testing.B) {
+	for _, size := range []int{1000, 10000, 50000} {
+		b.Run(fmt.Sprintf("urls=%d", size), func(b *testing.B) {
+			_, urls := synthTree(size, 100)
+			for b.Loop() {
+				for _, url := range urls {
+					url.GetBookmark()
+				}
+			}
+		})
+	}
+}
+
+// baseline resolving the tags of each node with a walk of the whole tree
+func BenchmarkTreeWalkTags(b *testing.B) {
+	for _, size := range []int{1000, 5000} {
+		b.Run(fmt.Sprintf("urls=%d", size), func(b *testing.B) {
+			_, urls := synthTree(size, 100)
+			for b.Loop() {
+				for _, url := range urls {
+					treeWalkTags(url)
+				}
+			}
+		})
+	}
+}
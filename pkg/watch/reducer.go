@@ -45,6 +45,10 @@ func ReduceEvents(interval time.Duration,
 		case <-beat:
 			// log.Debugf("reducer beat %s", watch.ID)
 
+		case <-watch.done:
+			timer.Stop()
+			return
+
 		case <-timer.C:
 			if len(events) > 0 {
 				log.Debug("<reduce>: calling Run()")
@@ -25,6 +25,7 @@ package watch
 import (
 	"fmt"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/blob42/gosuki"
@@ -112,12 +113,18 @@ type WatchDescriptor struct {
 	// Watches is a slice of pointers to Watch objects, which represent specific files or directories being watched.
 	Watches []*Watch
 
+	// guards Watches once the watch loop is running, see AddWatch
+	mu sync.RWMutex
+
 	// eventsChan is a channel used for communicating events related to the watches. It's buffered and has a size determined by fsnotify.BufferSize().
 	eventsChan chan fsnotify.Event
 
 	// isWatching is a boolean flag that indicates whether this WatchDescriptor is actively watching any file or directory.
 	isWatching bool
 
+	// done is closed when the watcher is stopped
+	done chan struct{}
+
 	// List of unique event names that where encountered
 	// Useful to track unique filenames in a watched path
 	TrackEventNames bool
@@ -130,10 +137,40 @@ func (w *WatchDescriptor) AddEventName(name string) {
 	}
 }
 
-func (w WatchDescriptor) hasReducer() bool {
+// AddWatch adds a path to a running watcher
+func (w *WatchDescriptor) AddWatch(watch *Watch) error {
+	if err := w.W.Add(watch.Path); err != nil {
+		return fmt.Errorf("adding watch path: %s: %w", watch.Path, err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Watches = append(w.Watches, watch)
+	return nil
+}
+
+func (w *WatchDescriptor) watches() []*Watch {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return slices.Clone(w.Watches)
+}
+
+func (w *WatchDescriptor) hasReducer() bool {
 	return w.eventsChan != nil
 }
 
+// Stop closes the underlying fsnotify watcher and ends the watch loop and
+// reducer of this descriptor.
+func (w *WatchDescriptor) Stop() error {
+	select {
+	case <-w.done:
+		return nil
+	default:
+		close(w.done)
+	}
+	return w.W.Close()
+}
+
 func NewWatcherWithReducer(name string, reducerLen int, watches ...*Watch) (*WatchDescriptor, error) {
 	w, err := NewWatcher(name, watches...)
 	if err != nil {
@@ -161,6 +198,7 @@ func NewWatcher(name string, watches ...*Watch) (*WatchDescriptor, error) {
 		W:          fswatcher,
 		Watches:    watches,
 		eventsChan: nil,
+		done:       make(chan struct{}),
 	}
 
 	// Add all watched paths
@@ -241,12 +279,22 @@ func watchRun(w Watcher, m manager.UnitManager) {
 	<-m.ShouldStop()
 
 	// if module implements shutdowner
-	sht, ok := w.(Shutdowner)
-	if ok {
+	var sht Shutdowner
+	switch w := w.(type) {
+	case WatchLoad:
+		sht, _ = w.WatchLoader.(Shutdowner)
+	case WatchWork:
+		sht, _ = w.WatchRunner.(Shutdowner)
+	}
+	if sht != nil {
 		if err := sht.Shutdown(); err != nil {
 			m.Panic(err)
 		}
 	}
+
+	if err := watcher.Stop(); err != nil {
+		log.Error("stopping watcher", "id", watcher.ID, "err", err)
+	}
 	m.Done()
 }
 
@@ -300,6 +348,9 @@ watchloop:
 		select {
 		case <-beat:
 		// log.Debugf("main watch loop beat %s", watcher.ID)
+		case <-watch.done:
+			log.Debugf("<%s> stopped watcher", watch.ID)
+			return
 		case event := <-watch.W.Events:
 			// Very verbose
 			log.Trace("event", "OP", event.Op, "eventName", event.Name)
@@ -318,7 +369,7 @@ watchloop:
 			* Leaving comment until further testing
 			 */
 
-			for _, watched := range watch.Watches {
+			for _, watched := range watch.watches() {
 				if watch.TrackEventNames {
 					watch.AddEventName(event.Name)
 				}
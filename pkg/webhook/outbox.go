@@ -0,0 +1,345 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/config"
+)
+
+// Name of the outbox directory next to the database
+const OutboxDir = "webhooks"
+
+// delivery is an event waiting to be sent to an endpoint
+type delivery struct {
+	// index of the endpoint in the config and its URL
+	Endpoint  int             `json:"endpoint"`
+	URL       string          `json:"url"`
+	Event     string          `json:"event"`
+	ID        string          `json:"id"`
+	Body      json.RawMessage `json:"body"`
+	Attempts  int             `json:"attempts"`
+	Next      time.Time       `json:"next"`
+	LastError string          `json:"last_error,omitempty"`
+}
+
+// Outbox stores deliveries as JSON files until they are sent. Deliveries that
+// keep failing are moved to the `failed` subdirectory.
+type Outbox struct {
+	dir    string
+	client *http.Client
+	wake   chan struct{}
+	mu     sync.Mutex
+
+	// endpoints that failed are not retried before this time
+	retryAt map[int]time.Time
+}
+
+func NewOutbox(dir string) (*Outbox, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "failed"), 0o700); err != nil {
+		return nil, err
+	}
+
+	timeout := Config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Outbox{
+		dir:     dir,
+		client:  &http.Client{Timeout: timeout},
+		wake:    make(chan struct{}, 1),
+		retryAt: map[int]time.Time{},
+	}, nil
+}
+
+// Add writes a delivery of the event for every endpoint matching it
+func (o *Outbox) Add(ev Event, bk *gosuki.Bookmark) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	var added bool
+	for i, e := range Config.Endpoints {
+		if !e.Match(ev.Event, bk) {
+			continue
+		}
+
+		d := delivery{
+			Endpoint: i,
+			URL:      e.URL,
+			Event:    ev.Event,
+			ID:       ev.ID,
+			Body:     body,
+		}
+		name := fmt.Sprintf("%020d-%s-%d.json", time.Now().UnixNano(), ev.ID, i)
+		if err := o.write(filepath.Join(o.dir, name), d); err != nil {
+			return err
+		}
+		added = true
+	}
+
+	if added {
+		select {
+		case o.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// write saves the delivery with a rename so readers never see partial files
+func (o *Outbox) write(path string, d delivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Pending returns the number of deliveries waiting in the outbox
+func (o *Outbox) Pending() int {
+	files, _ := filepath.Glob(filepath.Join(o.dir, "*.json"))
+	return len(files)
+}
+
+// Flush sends the deliveries that are due, oldest first. It returns the time
+// to wait until the next delivery is due or zero when the outbox is empty.
+func (o *Outbox) Flush(ctx context.Context) time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(o.dir, "*.json"))
+	if err != nil {
+		log.Error("reading outbox", "err", err)
+		return 0
+	}
+	slices.Sort(files)
+
+	var next time.Duration
+	for _, path := range files {
+		if ctx.Err() != nil {
+			return 0
+		}
+
+		wait, err := o.deliver(ctx, path)
+		if err != nil {
+			log.Error("webhook delivery", "file", filepath.Base(path), "err", err)
+			continue
+		}
+		if wait > 0 && (next == 0 || wait < next) {
+			next = wait
+		}
+	}
+	return next
+}
+
+// deliver sends one delivery and returns how long to wait before it is due
+// again. All the deliveries to an endpoint that failed wait for its backoff.
+func (o *Outbox) deliver(ctx context.Context, path string) (time.Duration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var d delivery
+	if err := json.Unmarshal(data, &d); err != nil {
+		return 0, o.fail(path, d, fmt.Sprintf("invalid delivery: %s", err))
+	}
+
+	if wait := time.Until(d.Next); wait > 0 {
+		return wait, nil
+	}
+	if wait := time.Until(o.retryAt[d.Endpoint]); wait > 0 {
+		return wait, nil
+	}
+
+	e, ok := endpoint(d.Endpoint, d.URL)
+	if !ok {
+		log.Warn("dropping delivery to removed endpoint", "url", d.URL, "id", d.ID)
+		return 0, os.Remove(path)
+	}
+
+	d.Attempts++
+	err = o.send(ctx, e, d)
+	if err == nil {
+		log.Debug("webhook sent", "url", e.URL, "event", d.Event, "id", d.ID)
+		delete(o.retryAt, d.Endpoint)
+		return 0, os.Remove(path)
+	}
+
+	d.LastError = err.Error()
+	wait := backoff(d.Attempts)
+	o.retryAt[d.Endpoint] = time.Now().Add(wait)
+
+	retries := Config.Retries
+	if retries <= 0 {
+		retries = DefaultRetries
+	}
+	if d.Attempts >= retries {
+		return 0, o.fail(path, d, d.LastError)
+	}
+
+	d.Next = o.retryAt[d.Endpoint]
+	log.Warn("webhook failed", "url", e.URL, "id", d.ID, "attempt", d.Attempts,
+		"retry-in", wait, "err", err)
+	return wait, o.write(path, d)
+}
+
+// fail moves the delivery to the failed directory
+func (o *Outbox) fail(path string, d delivery, reason string) error {
+	log.Error("giving up webhook delivery", "url", d.URL, "id", d.ID,
+		"attempts", d.Attempts, "err", reason)
+	return os.Rename(path, filepath.Join(o.dir, "failed", filepath.Base(path)))
+}
+
+func (o *Outbox) send(ctx context.Context, e Endpoint, d delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gosuki-webhook")
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryHeader, d.ID)
+	if e.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(e.Secret, d.Body))
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: %s", e.URL, resp.Status)
+	}
+	return nil
+}
+
+// Run flushes the outbox when events are added or deliveries are due
+func (o *Outbox) Run(ctx context.Context) {
+	for {
+		wait := o.Flush(ctx)
+
+		var timer *time.Timer
+		var due <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			due = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-o.wake:
+		case <-due:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func backoff(attempts int) time.Duration {
+	wait := Config.Backoff
+	if wait <= 0 {
+		wait = DefaultBackoff
+	}
+	maxWait := Config.MaxBackoff
+	if maxWait <= 0 {
+		maxWait = DefaultMaxBackoff
+	}
+
+	for range attempts - 1 {
+		wait *= 2
+		if wait >= maxWait {
+			return maxWait
+		}
+	}
+	return wait
+}
+
+var (
+	outbox    *Outbox
+	outboxErr error
+	startOnce sync.Once
+)
+
+// Start opens the outbox next to the database and sends its deliveries in
+// the background until the context is done. Events left by a previous run
+// are sent first.
+func Start(ctx context.Context) error {
+	startOnce.Do(func() {
+		var dbPath string
+		if dbPath, outboxErr = utils.ExpandOnly(config.DBPath); outboxErr != nil {
+			return
+		}
+		dir := filepath.Join(filepath.Dir(dbPath), OutboxDir)
+		if outbox, outboxErr = NewOutbox(dir); outboxErr != nil {
+			return
+		}
+		go outbox.Run(ctx)
+	})
+	return outboxErr
+}
+
+// Send adds the event to the outbox for the endpoints matching it
+func Send(event string, bk *gosuki.Bookmark) error {
+	if len(Config.Endpoints) == 0 || bk == nil {
+		return nil
+	}
+	if strings.TrimSpace(config.DBPath) == "" {
+		return errors.New("webhook: database path not set")
+	}
+
+	if err := Start(context.Background()); err != nil {
+		return fmt.Errorf("webhook outbox: %w", err)
+	}
+	return outbox.Add(NewEvent(event, bk), bk)
+}
@@ -0,0 +1,184 @@
+//
+// Copyright (c) 2023-2025 Chakib Ben Ziane <contact@blob42.xyz> and [`GoSuki` contributors]
+// (https://github.com/blob42/gosuki/graphs/contributors).
+//
+// All rights reserved.
+//
+// SPDX-License-Identifier: AGPL-3.0-or-later
+//
+// This file is part of GoSuki.
+//
+// GoSuki is free software: you can redistribute it and/or modify it under the terms of
+// the GNU Affero General Public License as published by the Free Software Foundation,
+// either version 3 of the License, or (at your option) any later version.
+//
+// GoSuki is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY;
+// without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR
+// PURPOSE.  See the GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License along with
+// gosuki.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package webhook posts bookmark events to HTTP endpoints configured in the
+// [webhooks] config section.
+//
+//	[[webhooks.endpoints]]
+//	url = "https://example.com/gosuki"
+//	secret = "shared secret"
+//	events = ["insert", "update"]
+//	tags = ["work"]
+//	modules = ["firefox"]
+//
+// Events are written to an outbox on disk before they are sent so they are not
+// lost while an endpoint is down or gosuki is stopped. Failed deliveries are
+// retried with an exponential backoff.
+//
+// The body of requests is an [Event] in JSON. When the endpoint has a secret,
+// the `X-Gosuki-Signature` header holds `sha256=` followed by the hex encoded
+// HMAC-SHA256 of the body.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"time"
+
+	"github.com/blob42/gosuki"
+	"github.com/blob42/gosuki/internal/utils"
+	"github.com/blob42/gosuki/pkg/config"
+	"github.com/blob42/gosuki/pkg/logging"
+)
+
+// Bookmark events
+const (
+	EventInsert = "insert"
+	EventUpdate = "update"
+	EventDelete = "delete"
+)
+
+const (
+	DefaultRetries    = 10
+	DefaultBackoff    = 30 * time.Second
+	DefaultMaxBackoff = time.Hour
+	DefaultTimeout    = 10 * time.Second
+
+	SignatureHeader = "X-Gosuki-Signature"
+	EventHeader     = "X-Gosuki-Event"
+	DeliveryHeader  = "X-Gosuki-Delivery"
+)
+
+var (
+	Config *WebhooksConfig
+	log    = logging.GetLogger("webhook")
+)
+
+type WebhooksConfig struct {
+	Endpoints []Endpoint `toml:"endpoints" mapstructure:"endpoints"`
+
+	// Deliveries are given up after this number of attempts and moved to the
+	// `failed` directory of the outbox
+	Retries int `toml:"retries" mapstructure:"retries"`
+
+	// Wait before the first retry, doubled after each attempt up to
+	// max-backoff
+	Backoff    time.Duration `toml:"backoff" mapstructure:"backoff"`
+	MaxBackoff time.Duration `toml:"max-backoff" mapstructure:"max-backoff"`
+
+	// Time limit of requests
+	Timeout time.Duration `toml:"timeout" mapstructure:"timeout"`
+}
+
+// Endpoint is an URL receiving events. Empty filters match all the events.
+type Endpoint struct {
+	URL    string `toml:"url" mapstructure:"url"`
+	Secret string `toml:"secret" mapstructure:"secret"`
+
+	// Events sent to the endpoint: insert, update or delete
+	Events []string `toml:"events" mapstructure:"events"`
+
+	// The bookmark has one of these tags
+	Tags []string `toml:"tags" mapstructure:"tags"`
+
+	// `name[:profile]` globs matched against the module, ex. `chrome:work`,
+	// `firefox`
+	Modules []string `toml:"modules" mapstructure:"modules"`
+}
+
+// Match returns true if the event must be sent to the endpoint
+func (e Endpoint) Match(event string, bk *gosuki.Bookmark) bool {
+	if len(e.Events) > 0 && !slices.Contains(e.Events, event) {
+		return false
+	}
+	if len(e.Tags) > 0 && !slices.ContainsFunc(bk.Tags, func(t string) bool {
+		return slices.Contains(e.Tags, t)
+	}) {
+		return false
+	}
+	if len(e.Modules) > 0 && !slices.ContainsFunc(e.Modules, func(glob string) bool {
+		return utils.MatchModule(glob, bk.Module)
+	}) {
+		return false
+	}
+	return true
+}
+
+// Sign returns the signature of the body sent in the [SignatureHeader]
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// EventBookmark is the bookmark sent in events
+type EventBookmark struct {
+	URL    string   `json:"url"`
+	Title  string   `json:"title"`
+	Tags   []string `json:"tags"`
+	Desc   string   `json:"desc"`
+	Module string   `json:"module"`
+}
+
+// Event is the JSON body posted to endpoints
+type Event struct {
+	ID       string        `json:"id"`
+	Event    string        `json:"event"`
+	Time     int64         `json:"time"`
+	Bookmark EventBookmark `json:"bookmark"`
+}
+
+func NewEvent(event string, bk *gosuki.Bookmark) Event {
+	return Event{
+		ID:    utils.GenStringID(16),
+		Event: event,
+		Time:  time.Now().Unix(),
+		Bookmark: EventBookmark{
+			URL:    bk.URL,
+			Title:  bk.Title,
+			Tags:   bk.Tags,
+			Desc:   bk.Desc,
+			Module: bk.Module,
+		},
+	}
+}
+
+// endpoint returns the configured endpoint at index `i` if it still has this
+// URL. Endpoints can share an URL with different secrets and filters.
+func endpoint(i int, url string) (Endpoint, bool) {
+	if i < 0 || i >= len(Config.Endpoints) || Config.Endpoints[i].URL != url {
+		return Endpoint{}, false
+	}
+	return Config.Endpoints[i], true
+}
+
+func init() {
+	Config = &WebhooksConfig{
+		Endpoints:  []Endpoint{},
+		Retries:    DefaultRetries,
+		Backoff:    DefaultBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+		Timeout:    DefaultTimeout,
+	}
+	config.RegisterConfigurator("webhooks", config.AsConfigurator(Config))
+}
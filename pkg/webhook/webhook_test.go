@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blob42/gosuki"
+)
+
+type receiver struct {
+	*httptest.Server
+	fail   atomic.Int32
+	mu     sync.Mutex
+	events []Event
+	sigs   []string
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.fail.Load() > 0 {
+			r.fail.Add(-1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(req.Body)
+		var ev Event
+		if err := json.Unmarshal(body, &ev); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = append(r.events, ev)
+		if req.Header.Get(SignatureHeader) != "" {
+			r.sigs = append(r.sigs, req.Header.Get(SignatureHeader))
+			assert.Equal(t, Sign("s3cret", body), req.Header.Get(SignatureHeader))
+		}
+		assert.Equal(t, ev.Event, req.Header.Get(EventHeader))
+		assert.Equal(t, ev.ID, req.Header.Get(DeliveryHeader))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event{}, r.events...)
+}
+
+func withConfig(t *testing.T, endpoints ...Endpoint) {
+	prev := *Config
+	t.Cleanup(func() { *Config = prev })
+	Config.Endpoints = endpoints
+	Config.Backoff = 10 * time.Millisecond
+	Config.MaxBackoff = 40 * time.Millisecond
+	Config.Retries = 3
+}
+
+var testBookmark = &gosuki.Bookmark{
+	URL:    "https://example.com",
+	Title:  "Example",
+	Tags:   []string{"work"},
+	Module: "firefox_default",
+}
+
+func TestEndpointMatch(t *testing.T) {
+	bk := testBookmark
+	assert.True(t, Endpoint{}.Match(EventInsert, bk))
+	assert.True(t, Endpoint{Events: []string{EventInsert}}.Match(EventInsert, bk))
+	assert.False(t, Endpoint{Events: []string{EventDelete}}.Match(EventInsert, bk))
+	assert.True(t, Endpoint{Tags: []string{"home", "work"}}.Match(EventUpdate, bk))
+	assert.False(t, Endpoint{Tags: []string{"home"}}.Match(EventUpdate, bk))
+	assert.True(t, Endpoint{Modules: []string{"firefox"}}.Match(EventUpdate, bk))
+	assert.False(t, Endpoint{Modules: []string{"chrome*"}}.Match(EventUpdate, bk))
+}
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("signed delivery", func(t *testing.T) {
+		recv := newReceiver(t)
+		other := newReceiver(t)
+		withConfig(t,
+			Endpoint{URL: recv.URL, Secret: "s3cret"},
+			Endpoint{URL: other.URL, Tags: []string{"home"}},
+		)
+
+		o, err := NewOutbox(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, o.Add(NewEvent(EventInsert, testBookmark), testBookmark))
+		assert.Equal(t, 1, o.Pending())
+
+		assert.Zero(t, o.Flush(ctx))
+		assert.Zero(t, o.Pending())
+
+		events := recv.received()
+		require.Len(t, events, 1)
+		assert.Equal(t, EventInsert, events[0].Event)
+		assert.Equal(t, "https://example.com", events[0].Bookmark.URL)
+		assert.Equal(t, []string{"work"}, events[0].Bookmark.Tags)
+		assert.Len(t, recv.sigs, 1)
+		assert.Empty(t, other.received())
+	})
+
+	t.Run("retry and persistence", func(t *testing.T) {
+		recv := newReceiver(t)
+		recv.fail.Store(2)
+		withConfig(t, Endpoint{URL: recv.URL})
+		dir := t.TempDir()
+
+		o, err := NewOutbox(dir)
+		require.NoError(t, err)
+		require.NoError(t, o.Add(NewEvent(EventUpdate, testBookmark), testBookmark))
+		require.NoError(t, o.Add(NewEvent(EventDelete, testBookmark), testBookmark))
+
+		// the second delivery waits for the endpoint to come back
+		wait := o.Flush(ctx)
+		assert.Greater(t, wait, time.Duration(0))
+		assert.Equal(t, 2, o.Pending())
+		assert.Empty(t, recv.received())
+
+		// a new outbox on the same directory picks up the deliveries
+		o, err = NewOutbox(dir)
+		require.NoError(t, err)
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go o.Run(runCtx)
+
+		require.Eventually(t, func() bool { return o.Pending() == 0 },
+			2*time.Second, 10*time.Millisecond)
+		events := recv.received()
+		require.Len(t, events, 2)
+		assert.Equal(t, EventUpdate, events[0].Event)
+		assert.Equal(t, EventDelete, events[1].Event)
+	})
+
+	t.Run("down endpoint backoff", func(t *testing.T) {
+		recv := newReceiver(t)
+		recv.fail.Store(100)
+		withConfig(t, Endpoint{URL: recv.URL})
+		Config.Backoff = time.Hour
+
+		o, err := NewOutbox(t.TempDir())
+		require.NoError(t, err)
+		for range 3 {
+			require.NoError(t, o.Add(NewEvent(EventInsert, testBookmark), testBookmark))
+		}
+
+		// deliveries never tried wait for the backoff of their endpoint
+		for range 3 {
+			wait := o.Flush(ctx)
+			assert.Greater(t, wait, 59*time.Minute)
+		}
+		assert.EqualValues(t, 99, recv.fail.Load(), "one request to the down endpoint")
+		assert.Equal(t, 3, o.Pending())
+	})
+
+	t.Run("endpoints sharing an url", func(t *testing.T) {
+		recv := newReceiver(t)
+		withConfig(t,
+			Endpoint{URL: recv.URL, Tags: []string{"home"}},
+			Endpoint{URL: recv.URL, Secret: "s3cret"},
+		)
+
+		o, err := NewOutbox(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, o.Add(NewEvent(EventInsert, testBookmark), testBookmark))
+		assert.Zero(t, o.Flush(ctx))
+
+		// signed with the secret of the endpoint that matched
+		require.Len(t, recv.received(), 1)
+		assert.Len(t, recv.sigs, 1)
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		recv := newReceiver(t)
+		recv.fail.Store(100)
+		withConfig(t, Endpoint{URL: recv.URL})
+		dir := t.TempDir()
+
+		o, err := NewOutbox(dir)
+		require.NoError(t, err)
+		require.NoError(t, o.Add(NewEvent(EventInsert, testBookmark), testBookmark))
+
+		require.Eventually(t, func() bool {
+			o.Flush(ctx)
+			return o.Pending() == 0
+		}, 2*time.Second, 20*time.Millisecond)
+
+		failed, err := filepath.Glob(filepath.Join(dir, "failed", "*.json"))
+		require.NoError(t, err)
+		assert.Len(t, failed, 1)
+		assert.Empty(t, recv.received())
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	withConfig(t)
+	assert.Equal(t, 10*time.Millisecond, backoff(1))
+	assert.Equal(t, 20*time.Millisecond, backoff(2))
+	assert.Equal(t, 40*time.Millisecond, backoff(3))
+	assert.Equal(t, 40*time.Millisecond, backoff(10))
+}